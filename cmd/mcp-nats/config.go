@@ -0,0 +1,514 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sinadarbouy/mcp-nats/internal/audit"
+	"github.com/sinadarbouy/mcp-nats/tools"
+	"github.com/sinadarbouy/mcp-nats/tools/common"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// configFileEnvVar names the configuration file when --config is not given.
+	configFileEnvVar = "MCP_NATS_CONFIG"
+	// defaultClusterName names the default cluster when the configuration
+	// file does not.
+	defaultClusterName = "default"
+)
+
+// fileConfig is the schema of the --config file. JSON is a subset of YAML,
+// so both formats are read by the same decoder; unknown fields are rejected.
+type fileConfig struct {
+	Transport string          `yaml:"transport"`
+	Listen    listenConfig    `yaml:"listen"`
+	Logging   loggingConfig   `yaml:"logging"`
+	Clusters  []clusterConfig `yaml:"clusters"`
+	Policies  policyConfig    `yaml:"policies"`
+	Timeouts  timeoutConfig   `yaml:"timeouts"`
+	Readiness readinessConfig `yaml:"readiness"`
+}
+
+type listenConfig struct {
+	Address      string    `yaml:"address"`
+	EndpointPath string    `yaml:"endpoint_path"`
+	TLS          tlsConfig `yaml:"tls"`
+	// AllowedOrigins are the browser origins allowed to call the server.
+	AllowedOrigins []string `yaml:"allowed_origins"`
+}
+
+// tlsConfig enables TLS on the HTTP listener. Relative paths are resolved
+// against the directory of the configuration file.
+type tlsConfig struct {
+	Cert       string `yaml:"cert"`
+	Key        string `yaml:"key"`
+	ClientCA   string `yaml:"client_ca"`
+	ClientAuth string `yaml:"client_auth"`
+}
+
+type loggingConfig struct {
+	Level string `yaml:"level"`
+	JSON  *bool  `yaml:"json"`
+}
+
+// clusterConfig describes a NATS system and how to authenticate against it.
+type clusterConfig struct {
+	Name             string                   `yaml:"name"`
+	URL              string                   `yaml:"url"`
+	NoAuthentication *bool                    `yaml:"no_authentication"`
+	User             string                   `yaml:"user"`
+	Password         string                   `yaml:"password"`
+	Accounts         map[string]accountConfig `yaml:"accounts"`
+}
+
+// accountConfig holds the credentials of one account, either as a path to a
+// .creds file or as base64-encoded file contents.
+type accountConfig struct {
+	CredsFile string `yaml:"creds_file"`
+	Creds     string `yaml:"creds"`
+}
+
+type policyConfig struct {
+	ReadOnly  *bool               `yaml:"read_only"`
+	RateLimit rateLimitFileConfig `yaml:"rate_limit"`
+	Audit     auditFileConfig     `yaml:"audit"`
+	Bench     benchFileConfig     `yaml:"bench"`
+	Resources resourceFileConfig  `yaml:"resources"`
+}
+
+type rateLimitFileConfig struct {
+	Calls        *float64 `yaml:"calls"`
+	Burst        *int     `yaml:"burst"`
+	Messages     *float64 `yaml:"messages"`
+	MessageBurst *int     `yaml:"message_burst"`
+	Tools        string   `yaml:"tools"`
+}
+
+type auditFileConfig struct {
+	File    string `yaml:"file"`
+	Subject string `yaml:"subject"`
+	Stream  string `yaml:"stream"`
+	Account string `yaml:"account"`
+	Buffer  *int   `yaml:"buffer"`
+}
+
+// benchFileConfig caps the load the bench tool may generate.
+type benchFileConfig struct {
+	MaxMessages    *int           `yaml:"max_messages"`
+	MaxMessageSize *int           `yaml:"max_message_size"`
+	MaxClients     *int           `yaml:"max_clients"`
+	MaxDuration    *time.Duration `yaml:"max_duration"`
+}
+
+// resourceFileConfig limits what resources return.
+type resourceFileConfig struct {
+	MaxObjectSize *int `yaml:"max_object_size"`
+}
+
+// readinessConfig controls the checks behind /readyz.
+type readinessConfig struct {
+	JetStream        *bool          `yaml:"jetstream"`
+	CacheTTL         *time.Duration `yaml:"cache_ttl"`
+	RequiredAccounts []string       `yaml:"required_accounts"`
+}
+
+type timeoutConfig struct {
+	ToolCall  *time.Duration `yaml:"tool_call"`
+	Readiness *time.Duration `yaml:"readiness"`
+	Shutdown  *time.Duration `yaml:"shutdown"`
+}
+
+// defaultConfig returns the configuration used when nothing is set.
+func defaultConfig() *Config {
+	return &Config{
+		Transport:           "streamable-http",
+		Address:             "0.0.0.0:8000",
+		EndpointPath:        "/mcp",
+		TLSClientAuth:       clientAuthRequire,
+		LogLevel:            "info",
+		AuditBufferSize:     audit.DefaultBufferSize,
+		ReadinessTimeout:    defaultReadinessTimeout,
+		ShutdownTimeout:     defaultShutdownTimeout,
+		ReadinessCacheTTL:   defaultReadinessCacheTTL,
+		BenchMaxMessages:    tools.DefaultBenchLimits.MaxMessages,
+		BenchMaxMessageSize: tools.DefaultBenchLimits.MaxMessageSize,
+		BenchMaxClients:     tools.DefaultBenchLimits.MaxClients,
+		BenchMaxDuration:    tools.DefaultBenchLimits.MaxDuration,
+
+		ResourceMaxObjectSize: tools.DefaultResourceLimits.MaxObjectSize,
+	}
+}
+
+// newFlagSet defines the command line flags on cfg, using the current values
+// of cfg as the flag defaults.
+func newFlagSet(cfg *Config) *flag.FlagSet {
+	fs := flag.NewFlagSet(AppName, flag.ContinueOnError)
+	fs.StringVar(&cfg.ConfigFile, "config", cfg.ConfigFile, "YAML or JSON configuration file (can also be set via "+configFileEnvVar+" env var)")
+	fs.StringVar(&cfg.Transport, "transport", cfg.Transport, "Transport type (stdio, sse or streamable-http)")
+	fs.StringVar(&cfg.Address, "address", cfg.Address, "Address for HTTP server to listen on")
+	fs.StringVar(&cfg.Address, "sse-address", cfg.Address, "Deprecated: use --address instead")
+	fs.StringVar(&cfg.EndpointPath, "endpoint-path", cfg.EndpointPath, "Endpoint path for streamable-http server")
+	fs.StringVar(&cfg.AllowedOrigins, "allowed-origins", cfg.AllowedOrigins, "Comma-separated browser origins allowed to call the HTTP transports, or * for any (default: localhost origins only)")
+	fs.StringVar(&cfg.TLSCert, "tls-cert", cfg.TLSCert, "TLS certificate file for the HTTP transports (reloaded when it changes)")
+	fs.StringVar(&cfg.TLSKey, "tls-key", cfg.TLSKey, "TLS private key file for the HTTP transports")
+	fs.StringVar(&cfg.TLSClientCA, "tls-client-ca", cfg.TLSClientCA, "CA bundle to verify client certificates against (enables mTLS)")
+	fs.StringVar(&cfg.TLSClientAuth, "tls-client-auth", cfg.TLSClientAuth, "Client certificate policy with --tls-client-ca (require or verify-if-given)")
+	fs.StringVar(&cfg.LogLevel, "log-level", cfg.LogLevel, "Log level (debug, info, warn, error)")
+	fs.BoolVar(&cfg.JSONLogs, "json-logs", cfg.JSONLogs, "Output logs in JSON format")
+	fs.BoolVar(&cfg.NoAuthentication, "no-authentication", cfg.NoAuthentication, "Allow anonymous connections without credentials")
+	fs.StringVar(&cfg.NATSUser, "user", cfg.NATSUser, "NATS username or token (can also be set via NATS_USER env var)")
+	fs.StringVar(&cfg.NATSPassword, "password", cfg.NATSPassword, "NATS password (can also be set via NATS_PASSWORD env var)")
+	fs.BoolVar(&cfg.ReadOnly, "read-only", cfg.ReadOnly, "Omit mutating MCP tools; default from MCP_NATS_READ_ONLY (true/1/yes)")
+	fs.StringVar(&cfg.AuditFile, "audit-file", cfg.AuditFile, "Append an audit entry for every tool call to this JSONL file")
+	fs.StringVar(&cfg.AuditSubject, "audit-subject", cfg.AuditSubject, "Publish an audit entry for every tool call to this NATS subject")
+	fs.StringVar(&cfg.AuditStream, "audit-stream", cfg.AuditStream, "Publish audit entries through JetStream into this stream (created if missing; requires --audit-subject)")
+	fs.StringVar(&cfg.AuditAccount, "audit-account", cfg.AuditAccount, "NATS account whose credentials publish audit entries (credentials-based authentication)")
+	fs.IntVar(&cfg.AuditBufferSize, "audit-buffer", cfg.AuditBufferSize, "Number of recent audit entries kept in memory for audit_query")
+	fs.Float64Var(&cfg.RateLimitCalls, "rate-limit-calls", cfg.RateLimitCalls, "Tool calls per second allowed per session and per client identity (0 disables)")
+	fs.IntVar(&cfg.RateLimitBurst, "rate-limit-burst", cfg.RateLimitBurst, "Burst size for --rate-limit-calls (default: the rate rounded up)")
+	fs.Float64Var(&cfg.RateLimitMessages, "rate-limit-messages", cfg.RateLimitMessages, "Published messages per second allowed per session and per client identity (0 disables)")
+	fs.IntVar(&cfg.RateLimitMessageBurst, "rate-limit-message-burst", cfg.RateLimitMessageBurst, "Burst size for --rate-limit-messages (default: the rate rounded up)")
+	fs.StringVar(&cfg.RateLimitTools, "rate-limit-tools", cfg.RateLimitTools, "Per-tool call limits overriding --rate-limit-calls, e.g. publish=1:5,stream_report=0.2")
+	fs.IntVar(&cfg.BenchMaxMessages, "bench-max-messages", cfg.BenchMaxMessages, "Most messages one bench tool call may publish")
+	fs.IntVar(&cfg.BenchMaxMessageSize, "bench-max-message-size", cfg.BenchMaxMessageSize, "Largest message size in bytes the bench tool may use")
+	fs.IntVar(&cfg.BenchMaxClients, "bench-max-clients", cfg.BenchMaxClients, "Most publishers and subscribers together in one bench tool call")
+	fs.DurationVar(&cfg.BenchMaxDuration, "bench-max-duration", cfg.BenchMaxDuration, "Longest a bench tool call may run")
+	fs.IntVar(&cfg.ResourceMaxObjectSize, "resource-max-object-size", cfg.ResourceMaxObjectSize, "Largest object in bytes returned as a resource")
+	fs.DurationVar(&cfg.ToolTimeout, "tool-timeout", cfg.ToolTimeout, "Cancel tool calls running longer than this (0 disables)")
+	fs.DurationVar(&cfg.ReadinessTimeout, "readiness-timeout", cfg.ReadinessTimeout, "Timeout of each NATS check behind /readyz")
+	fs.BoolVar(&cfg.ReadinessJetStream, "readiness-jetstream", cfg.ReadinessJetStream, "Also check JetStream API availability in /readyz")
+	fs.DurationVar(&cfg.ReadinessCacheTTL, "readiness-cache-ttl", cfg.ReadinessCacheTTL, "Reuse /readyz results for this long (0 checks on every probe)")
+	fs.StringVar(&cfg.ReadinessRequiredAccounts, "readiness-required-accounts", cfg.ReadinessRequiredAccounts, "Comma-separated accounts, as account or cluster/account, whose failed check makes /readyz unavailable")
+	fs.DurationVar(&cfg.ShutdownTimeout, "shutdown-timeout", cfg.ShutdownTimeout, "Time allowed for HTTP servers and trace exporters to shut down")
+	fs.BoolVar(&cfg.WatchConfig, "watch-config", cfg.WatchConfig, "Reload the configuration file when it changes (SIGHUP always reloads it)")
+	return fs
+}
+
+// loadConfig builds the configuration from defaults, the configuration file,
+// environment variables and the command line flags in args, each overriding
+// the previous.
+func loadConfig(args []string) (*Config, error) {
+	// A first pass finds --config and reports malformed flags.
+	probe := defaultConfig()
+	if err := newFlagSet(probe).Parse(args); err != nil {
+		return nil, err
+	}
+	path := probe.ConfigFile
+	if path == "" {
+		path = strings.TrimSpace(os.Getenv(configFileEnvVar))
+	}
+
+	cfg := defaultConfig()
+	cfg.ConfigFile = path
+	if path != "" {
+		fc, err := readConfigFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := fc.apply(cfg, filepath.Dir(path)); err != nil {
+			return nil, fmt.Errorf("config file %s: %w", path, err)
+		}
+	}
+
+	applyEnv(cfg)
+
+	// Flags are defined with the merged values as defaults, so only flags
+	// given on the command line override them.
+	fs := newFlagSet(cfg)
+	fs.SetOutput(io.Discard)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	cfg.args = args
+	return cfg, nil
+}
+
+// readConfigFile decodes the configuration file at path.
+func readConfigFile(path string) (*fileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	fc := &fileConfig{}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(fc); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+	return fc, nil
+}
+
+// apply validates the file and copies the values it sets into cfg. Relative
+// creds_file paths are resolved against dir.
+func (fc *fileConfig) apply(cfg *Config, dir string) error {
+	if fc.Transport != "" {
+		cfg.Transport = fc.Transport
+	}
+	if fc.Listen.Address != "" {
+		cfg.Address = fc.Listen.Address
+	}
+	if fc.Listen.EndpointPath != "" {
+		cfg.EndpointPath = fc.Listen.EndpointPath
+	}
+	if len(fc.Listen.AllowedOrigins) > 0 {
+		cfg.AllowedOrigins = strings.Join(fc.Listen.AllowedOrigins, ",")
+	}
+	if fc.Listen.TLS.Cert != "" {
+		cfg.TLSCert = resolvePath(fc.Listen.TLS.Cert, dir)
+	}
+	if fc.Listen.TLS.Key != "" {
+		cfg.TLSKey = resolvePath(fc.Listen.TLS.Key, dir)
+	}
+	if fc.Listen.TLS.ClientCA != "" {
+		cfg.TLSClientCA = resolvePath(fc.Listen.TLS.ClientCA, dir)
+	}
+	if fc.Listen.TLS.ClientAuth != "" {
+		cfg.TLSClientAuth = fc.Listen.TLS.ClientAuth
+	}
+	if fc.Logging.Level != "" {
+		cfg.LogLevel = fc.Logging.Level
+	}
+	if fc.Logging.JSON != nil {
+		cfg.JSONLogs = *fc.Logging.JSON
+	}
+
+	names := make(map[string]int, len(fc.Clusters))
+	for i, cluster := range fc.Clusters {
+		if cluster.Name == "" && len(fc.Clusters) > 1 {
+			return fmt.Errorf("clusters[%d]: name is required when several clusters are configured", i)
+		}
+		if j, dup := names[cluster.Name]; dup {
+			return fmt.Errorf("clusters[%d]: name %q is already used by clusters[%d]", i, cluster.Name, j)
+		}
+		names[cluster.Name] = i
+
+		conn, err := cluster.connection(dir)
+		if err != nil {
+			return fmt.Errorf("clusters[%d]: %w", i, err)
+		}
+		if i == 0 {
+			// The first cluster is the default one, which the flat
+			// settings, environment variables and flags refer to.
+			cfg.ClusterName = conn.Name
+			cfg.NATSURL = conn.URL
+			cfg.NoAuthentication = conn.NoAuthentication
+			cfg.NATSUser = conn.User
+			cfg.NATSPassword = conn.Password
+			cfg.Creds = conn.Creds
+			continue
+		}
+		// Only the default cluster may take its URL from NATS_URL or a flag.
+		if conn.URL == "" {
+			return fmt.Errorf("clusters[%d]: url is required", i)
+		}
+		cfg.Clusters = append(cfg.Clusters, conn)
+	}
+
+	policies := fc.Policies
+	if policies.ReadOnly != nil {
+		cfg.ReadOnly = *policies.ReadOnly
+	}
+	rl := policies.RateLimit
+	if rl.Calls != nil {
+		cfg.RateLimitCalls = *rl.Calls
+	}
+	if rl.Burst != nil {
+		cfg.RateLimitBurst = *rl.Burst
+	}
+	if rl.Messages != nil {
+		cfg.RateLimitMessages = *rl.Messages
+	}
+	if rl.MessageBurst != nil {
+		cfg.RateLimitMessageBurst = *rl.MessageBurst
+	}
+	if rl.Tools != "" {
+		cfg.RateLimitTools = rl.Tools
+	}
+	a := policies.Audit
+	if a.File != "" {
+		cfg.AuditFile = resolvePath(a.File, dir)
+	}
+	if a.Subject != "" {
+		cfg.AuditSubject = a.Subject
+	}
+	if a.Stream != "" {
+		cfg.AuditStream = a.Stream
+	}
+	if a.Account != "" {
+		cfg.AuditAccount = a.Account
+	}
+	if a.Buffer != nil {
+		cfg.AuditBufferSize = *a.Buffer
+	}
+	b := policies.Bench
+	if b.MaxMessages != nil {
+		cfg.BenchMaxMessages = *b.MaxMessages
+	}
+	if b.MaxMessageSize != nil {
+		cfg.BenchMaxMessageSize = *b.MaxMessageSize
+	}
+	if b.MaxClients != nil {
+		cfg.BenchMaxClients = *b.MaxClients
+	}
+	if b.MaxDuration != nil {
+		cfg.BenchMaxDuration = *b.MaxDuration
+	}
+	if r := policies.Resources; r.MaxObjectSize != nil {
+		cfg.ResourceMaxObjectSize = *r.MaxObjectSize
+	}
+
+	for _, t := range []struct {
+		name string
+		d    *time.Duration
+	}{
+		{"tool_call", fc.Timeouts.ToolCall},
+		{"readiness", fc.Timeouts.Readiness},
+		{"shutdown", fc.Timeouts.Shutdown},
+	} {
+		if t.d != nil && *t.d < 0 {
+			return fmt.Errorf("timeouts.%s must not be negative", t.name)
+		}
+	}
+	if fc.Timeouts.ToolCall != nil {
+		cfg.ToolTimeout = *fc.Timeouts.ToolCall
+	}
+	if fc.Timeouts.Readiness != nil {
+		cfg.ReadinessTimeout = *fc.Timeouts.Readiness
+	}
+	if fc.Timeouts.Shutdown != nil {
+		cfg.ShutdownTimeout = *fc.Timeouts.Shutdown
+	}
+
+	if fc.Readiness.JetStream != nil {
+		cfg.ReadinessJetStream = *fc.Readiness.JetStream
+	}
+	if ttl := fc.Readiness.CacheTTL; ttl != nil {
+		if *ttl < 0 {
+			return fmt.Errorf("readiness.cache_ttl must not be negative")
+		}
+		cfg.ReadinessCacheTTL = *ttl
+	}
+	if len(fc.Readiness.RequiredAccounts) > 0 {
+		cfg.ReadinessRequiredAccounts = strings.Join(fc.Readiness.RequiredAccounts, ",")
+	}
+	return nil
+}
+
+// connection validates the cluster and returns its connection settings.
+func (c clusterConfig) connection(dir string) (common.Connection, error) {
+	noAuth := c.NoAuthentication != nil && *c.NoAuthentication
+	if noAuth && (c.User != "" || len(c.Accounts) > 0) {
+		return common.Connection{}, fmt.Errorf("no_authentication cannot be combined with user or accounts")
+	}
+	if c.Password != "" && c.User == "" {
+		return common.Connection{}, fmt.Errorf("password requires user")
+	}
+
+	creds := make(map[string]common.NATSCreds, len(c.Accounts))
+	for name, account := range c.Accounts {
+		value, err := account.creds(dir)
+		if err != nil {
+			return common.Connection{}, fmt.Errorf("accounts.%s: %w", name, err)
+		}
+		creds[name] = common.NATSCreds{AccountName: name, Creds: value}
+	}
+
+	return common.Connection{
+		Name:             c.Name,
+		URL:              c.URL,
+		NoAuthentication: noAuth,
+		User:             c.User,
+		Password:         c.Password,
+		Creds:            creds,
+	}, nil
+}
+
+// creds returns the base64-encoded credentials of the account.
+func (a accountConfig) creds(dir string) (string, error) {
+	switch {
+	case a.Creds != "" && a.CredsFile != "":
+		return "", fmt.Errorf("creds and creds_file are mutually exclusive")
+	case a.Creds != "":
+		if _, err := base64.StdEncoding.DecodeString(a.Creds); err != nil {
+			return "", fmt.Errorf("creds: invalid base64: %w", err)
+		}
+		return a.Creds, nil
+	case a.CredsFile != "":
+		data, err := os.ReadFile(resolvePath(a.CredsFile, dir))
+		if err != nil {
+			return "", fmt.Errorf("creds_file: %w", err)
+		}
+		return base64.StdEncoding.EncodeToString(data), nil
+	default:
+		return "", fmt.Errorf("one of creds or creds_file is required")
+	}
+}
+
+// applyEnv copies the settings given by environment variables into cfg.
+func applyEnv(cfg *Config) {
+	if v := strings.TrimSpace(os.Getenv("NATS_URL")); v != "" {
+		cfg.NATSURL = v
+	}
+	if v, ok := os.LookupEnv("NATS_NO_AUTHENTICATION"); ok {
+		cfg.NoAuthentication = v == "true"
+	}
+	if v := os.Getenv("NATS_USER"); v != "" {
+		cfg.NATSUser = v
+	}
+	if v := os.Getenv("NATS_PASSWORD"); v != "" {
+		cfg.NATSPassword = v
+	}
+	if creds, _ := common.GetCredsFromEnv(); len(creds) > 0 {
+		if cfg.Creds == nil {
+			cfg.Creds = make(map[string]common.NATSCreds, len(creds))
+		}
+		for name, c := range creds {
+			cfg.Creds[name] = c
+		}
+	}
+	if v, ok := os.LookupEnv("MCP_NATS_READ_ONLY"); ok {
+		v = strings.TrimSpace(strings.ToLower(v))
+		cfg.ReadOnly = v == "1" || v == "true" || v == "yes"
+	}
+}
+
+// connection returns the NATS connection settings of the default cluster.
+func (cfg *Config) connection() common.Connection {
+	name := cfg.ClusterName
+	if name == "" {
+		name = defaultClusterName
+	}
+	return common.Connection{
+		Name:             name,
+		URL:              cfg.NATSURL,
+		NoAuthentication: cfg.NoAuthentication,
+		User:             cfg.NATSUser,
+		Password:         cfg.NATSPassword,
+		Creds:            cfg.Creds,
+	}
+}
+
+// connections returns the connection settings of all configured clusters,
+// the default cluster first.
+func (cfg *Config) connections() []common.Connection {
+	return append([]common.Connection{cfg.connection()}, cfg.Clusters...)
+}
+
+// resolvePath resolves a path of the configuration file against dir, the
+// directory of that file.
+func resolvePath(path, dir string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sinadarbouy/mcp-nats/tools"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	return path
+}
+
+func TestLoadConfig_precedence(t *testing.T) {
+	path := writeConfigFile(t, "mcp-nats.yaml", `
+transport: sse
+listen:
+  address: 127.0.0.1:9000
+logging:
+  level: debug
+clusters:
+  - name: east
+    url: nats://east:4222
+    accounts:
+      ORDERS:
+        creds_file: orders.creds
+      BILLING:
+        creds: `+base64.StdEncoding.EncodeToString([]byte("billing"))+`
+policies:
+  read_only: true
+  rate_limit:
+    calls: 5
+    tools: publish=1
+  audit:
+    file: audit.jsonl
+  bench:
+    max_messages: 5000
+    max_duration: 10s
+timeouts:
+  tool_call: 30s
+`)
+	if err := os.WriteFile(filepath.Join(filepath.Dir(path), "orders.creds"), []byte("orders"), 0600); err != nil {
+		t.Fatalf("failed to write creds file: %v", err)
+	}
+
+	t.Setenv("NATS_URL", "nats://env:4222")
+	t.Setenv("MCP_NATS_READ_ONLY", "false")
+	t.Setenv("NATS_BILLING_CREDS", base64.StdEncoding.EncodeToString([]byte("billing-env")))
+
+	cfg, err := loadConfig([]string{"--config", path, "--log-level", "warn"})
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+
+	if cfg.Transport != "sse" || cfg.Address != "127.0.0.1:9000" || cfg.EndpointPath != "/mcp" {
+		t.Fatalf("listener settings = %q %q %q", cfg.Transport, cfg.Address, cfg.EndpointPath)
+	}
+	if cfg.LogLevel != "warn" {
+		t.Fatalf("log level = %q, want the flag to override the file", cfg.LogLevel)
+	}
+	if cfg.NATSURL != "nats://env:4222" {
+		t.Fatalf("NATS URL = %q, want NATS_URL to override the file", cfg.NATSURL)
+	}
+	if cfg.ReadOnly {
+		t.Fatalf("read-only = true, want MCP_NATS_READ_ONLY to override the file")
+	}
+	if cfg.RateLimitCalls != 5 || cfg.RateLimitTools != "publish=1" || cfg.ToolTimeout != 30*time.Second {
+		t.Fatalf("policies = %v %q %v", cfg.RateLimitCalls, cfg.RateLimitTools, cfg.ToolTimeout)
+	}
+	if cfg.BenchMaxMessages != 5000 || cfg.BenchMaxDuration != 10*time.Second || cfg.BenchMaxClients != tools.DefaultBenchLimits.MaxClients {
+		t.Fatalf("bench limits = %d %v %d", cfg.BenchMaxMessages, cfg.BenchMaxDuration, cfg.BenchMaxClients)
+	}
+	if cfg.AuditFile != filepath.Join(filepath.Dir(path), "audit.jsonl") {
+		t.Fatalf("audit file = %q, want it relative to the config file", cfg.AuditFile)
+	}
+	if cfg.ShutdownTimeout != defaultShutdownTimeout {
+		t.Fatalf("shutdown timeout = %v, want default", cfg.ShutdownTimeout)
+	}
+
+	decode := func(account string) string {
+		data, _ := base64.StdEncoding.DecodeString(cfg.Creds[account].Creds)
+		return string(data)
+	}
+	if got := decode("ORDERS"); got != "orders" {
+		t.Fatalf("ORDERS creds = %q, want creds_file relative to the config file", got)
+	}
+	if got := decode("BILLING"); got != "billing-env" {
+		t.Fatalf("BILLING creds = %q, want NATS_BILLING_CREDS to override the file", got)
+	}
+	if !cfg.connection().IsAccountNameRequired() {
+		t.Fatalf("credentials-based connection should require account_name")
+	}
+}
+
+func TestLoadConfig_json(t *testing.T) {
+	path := writeConfigFile(t, "mcp-nats.json", `{
+		"transport": "stdio",
+		"clusters": [{"url": "nats://json:4222", "no_authentication": true}],
+		"timeouts": {"readiness": "500ms"}
+	}`)
+
+	cfg, err := loadConfig([]string{"--config", path})
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+	if cfg.Transport != "stdio" || cfg.NATSURL != "nats://json:4222" || !cfg.NoAuthentication || cfg.ReadinessTimeout != 500*time.Millisecond {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+}
+
+func TestLoadConfig_clusters(t *testing.T) {
+	path := writeConfigFile(t, "mcp-nats.yaml", `
+clusters:
+  - name: prod
+    url: nats://prod:4222
+    user: admin
+    password: secret
+  - name: edge
+    url: nats://edge:4222
+    no_authentication: true
+`)
+	t.Setenv("NATS_URL", "nats://prod-override:4222")
+
+	cfg, err := loadConfig([]string{"--config", path})
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+	conns := cfg.connections()
+	if len(conns) != 2 {
+		t.Fatalf("got %d clusters, want 2", len(conns))
+	}
+	if conns[0].Name != "prod" || conns[0].URL != "nats://prod-override:4222" || conns[0].AuthMode() != "userpass" {
+		t.Fatalf("default cluster = %+v, want NATS_URL to override its URL", conns[0])
+	}
+	if conns[1].Name != "edge" || conns[1].URL != "nats://edge:4222" || conns[1].AuthMode() != "anonymous" {
+		t.Fatalf("second cluster = %+v", conns[1])
+	}
+}
+
+func TestLoadConfig_errors(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"unknown field", "listen:\n  adress: :8000\n", "field adress not found"},
+		{"wrong type", "policies:\n  read_only: sometimes\n", "line 2"},
+		{"two creds", "clusters:\n  - accounts:\n      SYS:\n        creds: eA==\n        creds_file: sys.creds\n", "clusters[0]: accounts.SYS: creds and creds_file are mutually exclusive"},
+		{"no creds", "clusters:\n  - accounts:\n      SYS: {}\n", "clusters[0]: accounts.SYS: one of creds or creds_file is required"},
+		{"bad base64", "clusters:\n  - accounts:\n      SYS:\n        creds: '%%%'\n", "clusters[0]: accounts.SYS: creds: invalid base64"},
+		{"missing creds file", "clusters:\n  - accounts:\n      SYS:\n        creds_file: missing.creds\n", "clusters[0]: accounts.SYS: creds_file:"},
+		{"password only", "clusters:\n  - password: secret\n", "clusters[0]: password requires user"},
+		{"unnamed cluster", "clusters:\n  - name: prod\n  - url: b\n", "clusters[1]: name is required when several clusters are configured"},
+		{"duplicate cluster", "clusters:\n  - name: prod\n  - name: prod\n", `clusters[1]: name "prod" is already used by clusters[0]`},
+		{"cluster without url", "clusters:\n  - name: prod\n  - name: edge\n", "clusters[1]: url is required"},
+		{"negative timeout", "timeouts:\n  shutdown: -1s\n", "timeouts.shutdown must not be negative"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := writeConfigFile(t, "mcp-nats.yaml", tc.content)
+			_, err := loadConfig([]string{"--config", path})
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("loadConfig error = %v, want it to contain %q", err, tc.want)
+			}
+			if !strings.Contains(err.Error(), path) {
+				t.Fatalf("loadConfig error = %v, want it to name the file", err)
+			}
+		})
+	}
+}
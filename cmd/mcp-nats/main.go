@@ -5,9 +5,7 @@ import (
 	"errors"
 	"flag"
 	"fmt"
-	"net"
 	"net/http"
-	"net/url"
 	"os"
 	"os/signal"
 	"strings"
@@ -16,8 +14,13 @@ import (
 
 	"github.com/mark3labs/mcp-go/server"
 	mcpnats "github.com/sinadarbouy/mcp-nats"
+	"github.com/sinadarbouy/mcp-nats/internal/audit"
 	"github.com/sinadarbouy/mcp-nats/internal/logger"
+	"github.com/sinadarbouy/mcp-nats/internal/metrics"
+	"github.com/sinadarbouy/mcp-nats/internal/ratelimit"
+	"github.com/sinadarbouy/mcp-nats/internal/tracing"
 	"github.com/sinadarbouy/mcp-nats/tools"
+	"github.com/sinadarbouy/mcp-nats/tools/common"
 )
 
 const (
@@ -29,6 +32,9 @@ const (
 
 // Config holds all configuration for the server
 type Config struct {
+	// ConfigFile is the YAML or JSON file the configuration was read from.
+	ConfigFile string
+
 	Transport        string
 	Address          string
 	EndpointPath     string
@@ -38,6 +44,68 @@ type Config struct {
 	NATSUser         string
 	NATSPassword     string
 	ReadOnly         bool
+
+	// AllowedOrigins is the comma-separated list of browser origins allowed
+	// to call the HTTP transports. Empty allows local origins only.
+	AllowedOrigins string
+
+	// TLSCert and TLSKey make the HTTP transports serve TLS. With
+	// TLSClientCA, client certificates are verified against that bundle.
+	TLSCert       string
+	TLSKey        string
+	TLSClientCA   string
+	TLSClientAuth string
+
+	// NATSURL and Creds come from the configuration file and the NATS_URL
+	// and NATS_<ACCOUNT>_CREDS environment variables. Together with the
+	// authentication settings above they describe the default cluster.
+	NATSURL     string
+	Creds       map[string]common.NATSCreds
+	ClusterName string
+	// Clusters are the further clusters of the configuration file.
+	Clusters []common.Connection
+
+	AuditFile       string
+	AuditSubject    string
+	AuditStream     string
+	AuditAccount    string
+	AuditBufferSize int
+
+	RateLimitCalls        float64
+	RateLimitBurst        int
+	RateLimitMessages     float64
+	RateLimitMessageBurst int
+	RateLimitTools        string
+
+	// BenchMax* cap the load of a single bench tool call.
+	BenchMaxMessages    int
+	BenchMaxMessageSize int
+	BenchMaxClients     int
+	BenchMaxDuration    time.Duration
+
+	// ResourceMaxObjectSize is the largest object in bytes returned as a
+	// resource.
+	ResourceMaxObjectSize int
+
+	ToolTimeout      time.Duration
+	ReadinessTimeout time.Duration
+	ShutdownTimeout  time.Duration
+
+	// ReadinessJetStream makes /readyz also check the JetStream API.
+	// Readiness results are reused for ReadinessCacheTTL.
+	ReadinessJetStream bool
+	ReadinessCacheTTL  time.Duration
+	// ReadinessRequiredAccounts is the comma-separated list of accounts,
+	// as account or cluster/account, whose failed check makes the server
+	// unready. Other failed accounts only degrade it.
+	ReadinessRequiredAccounts string
+
+	// WatchConfig reloads the configuration when ConfigFile changes.
+	WatchConfig bool
+
+	// args is the command line the configuration was loaded from, so a
+	// reload applies the same flags again.
+	args []string
 }
 
 // validateConfig ensures all config values are valid
@@ -56,15 +124,112 @@ func validateConfig(cfg *Config) error {
 			return fmt.Errorf("endpoint-path must start with '/'")
 		}
 	}
+	if _, err := parseAllowedOrigins(cfg.AllowedOrigins); err != nil {
+		return err
+	}
+	if err := validateTLSConfig(cfg); err != nil {
+		return err
+	}
+	if cfg.AuditStream != "" && cfg.AuditSubject == "" {
+		return fmt.Errorf("audit-stream requires audit-subject")
+	}
+	if cfg.AuditBufferSize < 0 {
+		return fmt.Errorf("audit-buffer must not be negative")
+	}
+	if cfg.RateLimitCalls < 0 || cfg.RateLimitMessages < 0 || cfg.RateLimitBurst < 0 || cfg.RateLimitMessageBurst < 0 {
+		return fmt.Errorf("rate limits must not be negative")
+	}
+	if _, err := rateLimitConfig(cfg); err != nil {
+		return err
+	}
+	// Every benchmark needs a publisher and a subscriber, and room for the
+	// 8 byte send timestamp in its messages.
+	if cfg.BenchMaxMessages <= 0 || cfg.BenchMaxMessageSize < 8 || cfg.BenchMaxClients < 2 || cfg.BenchMaxDuration <= 0 {
+		return fmt.Errorf("bench limits must allow at least 1 message of 8 bytes, 2 clients and a positive duration")
+	}
+	if cfg.ResourceMaxObjectSize <= 0 {
+		return fmt.Errorf("resource-max-object-size must be positive")
+	}
+	if cfg.ToolTimeout < 0 || cfg.ReadinessTimeout < 0 || cfg.ShutdownTimeout < 0 || cfg.ReadinessCacheTTL < 0 {
+		return fmt.Errorf("timeouts must not be negative")
+	}
 	return nil
 }
 
+// rateLimitConfig builds the rate limiter configuration from cfg.
+func rateLimitConfig(cfg *Config) (ratelimit.Config, error) {
+	def := ratelimit.Limit{
+		CallsPerSecond:    cfg.RateLimitCalls,
+		CallBurst:         cfg.RateLimitBurst,
+		MessagesPerSecond: cfg.RateLimitMessages,
+		MessageBurst:      cfg.RateLimitMessageBurst,
+	}
+	toolLimits, err := ratelimit.ParseToolLimits(cfg.RateLimitTools, def)
+	if err != nil {
+		return ratelimit.Config{}, fmt.Errorf("invalid rate-limit-tools: %w", err)
+	}
+	return ratelimit.Config{Default: def, Tools: toolLimits}, nil
+}
+
+// auditEnabled reports whether any audit output is configured.
+func auditEnabled(cfg *Config) bool {
+	return cfg.AuditFile != "" || cfg.AuditSubject != ""
+}
+
+// newAuditRecorder builds the audit recorder and its sinks from cfg.
+func newAuditRecorder(cfg *Config) (*audit.Recorder, error) {
+	var sinks []audit.Sink
+	closeSinks := func() {
+		for _, sink := range sinks {
+			_ = sink.Close()
+		}
+	}
+
+	if cfg.AuditFile != "" {
+		sink, err := audit.NewFileSink(cfg.AuditFile)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+
+	if cfg.AuditSubject != "" {
+		natsURL := cfg.NATSURL
+		if natsURL == "" {
+			natsURL = "localhost:4222"
+		}
+		strategy, err := cfg.connection().AuthStrategy(cfg.AuditAccount)
+		if err != nil {
+			closeSinks()
+			return nil, fmt.Errorf("failed to resolve audit NATS authentication: %w", err)
+		}
+		nc, err := common.Connect(natsURL, strategy)
+		if err != nil {
+			closeSinks()
+			return nil, err
+		}
+		sink, err := audit.NewNATSSink(nc, cfg.AuditSubject, cfg.AuditStream)
+		if err != nil {
+			nc.Close()
+			closeSinks()
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+
+	return audit.NewRecorder(cfg.AuditBufferSize, sinks...), nil
+}
+
 type httpServer interface {
 	Start(addr string) error
 	Shutdown(ctx context.Context) error
 }
 
-const readinessTimeout = 2 * time.Second
+const (
+	defaultReadinessTimeout  = 2 * time.Second
+	defaultReadinessCacheTTL = 5 * time.Second
+	defaultShutdownTimeout   = 5 * time.Second
+)
 
 func writeOK(w http.ResponseWriter) {
 	w.WriteHeader(http.StatusOK)
@@ -75,73 +240,33 @@ func handleLivez(w http.ResponseWriter, _ *http.Request) {
 	writeOK(w)
 }
 
-func handleReadyz(w http.ResponseWriter, _ *http.Request) {
-	ctx, cancel := context.WithTimeout(context.Background(), readinessTimeout)
-	defer cancel()
-
-	if err := checkNATSConnectivity(ctx, os.Getenv("NATS_URL")); err != nil {
-		logger.Warn("Readiness check failed", "error", err)
-		http.Error(w, "nats unavailable", http.StatusServiceUnavailable)
-		return
-	}
-
-	writeOK(w)
-}
-
 func handleHealthz(w http.ResponseWriter, _ *http.Request) {
 	// Keep /healthz as a stable alias for liveness checks.
 	handleLivez(w, nil)
 }
 
-func checkNATSConnectivity(ctx context.Context, rawURL string) error {
-	natsURL := strings.TrimSpace(rawURL)
-	if natsURL == "" {
-		natsURL = "localhost:4222"
-	}
-
-	address := natsURL
-	if strings.Contains(natsURL, "://") {
-		parsedURL, err := url.Parse(natsURL)
-		if err != nil {
-			return fmt.Errorf("invalid NATS URL %q: %w", natsURL, err)
-		}
-		if parsedURL.Host == "" {
-			return fmt.Errorf("invalid NATS URL %q: missing host", natsURL)
-		}
-		address = parsedURL.Host
-	}
-
-	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", address)
-	if err != nil {
-		return fmt.Errorf("dial NATS at %s: %w", address, err)
-	}
-
-	if closeErr := conn.Close(); closeErr != nil {
-		logger.Debug("Failed to close readiness probe connection", "error", closeErr)
-	}
-	return nil
-}
-
-func newHTTPMux(mcpPath string, mcpHandler http.Handler) *http.ServeMux {
+func newHTTPMux(mcpPath string, mcpHandler, readyz http.Handler) *http.ServeMux {
 	mux := http.NewServeMux()
 	mux.Handle(mcpPath, mcpHandler)
 	mux.HandleFunc("/livez", handleLivez)
-	mux.HandleFunc("/readyz", handleReadyz)
+	mux.Handle("/readyz", readyz)
 	mux.HandleFunc("/healthz", handleHealthz)
+	mux.Handle("/metrics", metrics.Handler())
 	return mux
 }
 
-func newSSEHTTPMux(sseSrv *server.SSEServer) *http.ServeMux {
+func newSSEHTTPMux(sseSrv *server.SSEServer, readyz http.Handler) *http.ServeMux {
 	mux := http.NewServeMux()
 	mux.Handle("/sse", sseSrv.SSEHandler())
 	mux.Handle("/message", sseSrv.MessageHandler())
 	mux.HandleFunc("/livez", handleLivez)
-	mux.HandleFunc("/readyz", handleReadyz)
+	mux.Handle("/readyz", readyz)
 	mux.HandleFunc("/healthz", handleHealthz)
+	mux.Handle("/metrics", metrics.Handler())
 	return mux
 }
 
-func runHTTPServer(ctx context.Context, srv httpServer, addr, transportName string) error {
+func runHTTPServer(ctx context.Context, srv httpServer, addr, transportName string, shutdownTimeout time.Duration) error {
 	serverErr := make(chan error, 1)
 	go func() {
 		if err := srv.Start(addr); err != nil {
@@ -157,7 +282,7 @@ func runHTTPServer(ctx context.Context, srv httpServer, addr, transportName stri
 		}
 		return nil
 	case <-ctx.Done():
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 		defer cancel()
 
 		logger.Info("Shutting down HTTP server", "transport", transportName)
@@ -171,58 +296,156 @@ func runHTTPServer(ctx context.Context, srv httpServer, addr, transportName stri
 	}
 }
 
-func newServer(readOnly bool) (*server.MCPServer, error) {
+func newServer(cfg *Config, recorder *audit.Recorder) (*server.MCPServer, *tools.NATSServerTools, *tools.ResourceWatcher, error) {
+	// Initialize NATS server tools
+	natsTools, err := tools.NewNATSServerToolsWithClusters(cfg.connections())
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to initialize NATS tools: %w", err)
+	}
+
+	hooks := &server.Hooks{}
+	hooks.AddOnRegisterSession(func(_ context.Context, _ server.ClientSession) {
+		metrics.SessionOpened()
+	})
+	hooks.AddOnUnregisterSession(func(_ context.Context, _ server.ClientSession) {
+		metrics.SessionClosed()
+	})
+	// Drive resources/subscribe from KV and object store watches
+	watcher := tools.NewResourceWatcher(natsTools)
+	watcher.AddHooks(hooks)
+
 	s := server.NewMCPServer(
 		AppName,
 		Version,
+		server.WithToolCapabilities(true),
 		server.WithResourceCapabilities(true, true),
+		server.WithPromptCapabilities(false),
+		server.WithCompletions(),
+		server.WithPromptCompletionProvider(natsTools.CompletionTools()),
+		server.WithResourceCompletionProvider(natsTools.CompletionTools()),
 		server.WithLogging(),
 		server.WithRecovery(),
+		server.WithHooks(hooks),
 	)
 
-	// Initialize NATS server tools
-	natsTools, err := tools.NewNATSServerTools()
+	// Register all NATS server tools
+	if err := applyToolPolicies(s, natsTools, cfg, recorder); err != nil {
+		return nil, nil, nil, err
+	}
+
+	// Expose JetStream assets as resources
+	tools.RegisterResources(s, natsTools)
+
+	// Register prompts for common operational workflows
+	tools.RegisterPrompts(s, natsTools)
+
+	return s, natsTools, watcher, nil
+}
+
+// applyToolPolicies installs the tool middlewares and resource limits for the
+// policies in cfg and registers the tool catalog, replacing any registered
+// before.
+func applyToolPolicies(s *server.MCPServer, natsTools *tools.NATSServerTools, cfg *Config, recorder *audit.Recorder) error {
+	limits, err := rateLimitConfig(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize NATS tools: %w", err)
+		return err
 	}
 
-	// Register all NATS server tools
-	tools.RegisterTools(s, natsTools, readOnly)
+	// Resource reads share the rate limits and the timeout of tool calls.
+	resourceLimits := tools.ResourceLimits{
+		MaxObjectSize: cfg.ResourceMaxObjectSize,
+		Timeout:       cfg.ToolTimeout,
+	}
+	natsTools.ResetMiddlewares()
+	natsTools.Use(tools.TracingMiddleware(), tools.MetricsMiddleware())
+	if recorder != nil {
+		natsTools.EnableAudit(recorder)
+	}
+	if limits.Enabled() {
+		limiter := ratelimit.New(limits)
+		natsTools.Use(tools.RateLimitMiddleware(limiter))
+		resourceLimits.Limiter = limiter
+	}
+	if cfg.ToolTimeout > 0 {
+		natsTools.Use(tools.TimeoutMiddleware(cfg.ToolTimeout))
+	}
+	natsTools.SetResourceLimits(resourceLimits)
 
-	return s, nil
+	natsTools.SetBenchLimits(tools.BenchLimits{
+		MaxMessages:    cfg.BenchMaxMessages,
+		MaxMessageSize: cfg.BenchMaxMessageSize,
+		MaxClients:     cfg.BenchMaxClients,
+		MaxDuration:    cfg.BenchMaxDuration,
+	})
+
+	tools.SetTools(s, natsTools, cfg.ReadOnly)
+	return nil
 }
 
 func run(ctx context.Context, cfg *Config) error {
-	// Set environment variables for authentication configuration
-	if cfg.NoAuthentication {
-		if err := os.Setenv("NATS_NO_AUTHENTICATION", "true"); err != nil {
-			return fmt.Errorf("failed to set NATS_NO_AUTHENTICATION env var: %w", err)
-		}
+	shutdownTracing, err := tracing.Setup(ctx, AppName, Version)
+	if err != nil {
+		return fmt.Errorf("failed to set up tracing: %w", err)
 	}
-	if cfg.NATSUser != "" {
-		if err := os.Setenv("NATS_USER", cfg.NATSUser); err != nil {
-			return fmt.Errorf("failed to set NATS_USER env var: %w", err)
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(shutdownCtx); err != nil {
+			logger.Error("Failed to flush traces", "error", err)
 		}
+	}()
+	if tracing.Enabled() {
+		logger.Info("OpenTelemetry tracing enabled")
 	}
-	if cfg.NATSPassword != "" {
-		if err := os.Setenv("NATS_PASSWORD", cfg.NATSPassword); err != nil {
-			return fmt.Errorf("failed to set NATS_PASSWORD env var: %w", err)
+
+	var recorder *audit.Recorder
+	if auditEnabled(cfg) {
+		var err error
+		recorder, err = newAuditRecorder(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to set up audit log: %w", err)
 		}
+		defer recorder.Close()
+		logger.Info("Audit log enabled",
+			"file", cfg.AuditFile,
+			"subject", cfg.AuditSubject,
+			"stream", cfg.AuditStream,
+		)
 	}
 
-	s, err := newServer(cfg.ReadOnly)
+	s, natsTools, watcher, err := newServer(cfg, recorder)
 	if err != nil {
 		return fmt.Errorf("failed to create server: %w", err)
 	}
+	// Stop the watches behind resource subscriptions and their connections
+	defer watcher.Close()
 
 	if cfg.ReadOnly {
 		logger.Info("Read-only mode enabled; mutating tools omitted")
 	}
 
+	// Reload the configuration on SIGHUP and, if enabled, when the file changes
+	rl := newReloader(cfg, s, natsTools, recorder)
+	go rl.watchSignals(ctx)
+	if cfg.WatchConfig {
+		go rl.watchFile(ctx, configWatchInterval)
+	}
+
+	// HTTP requests pick up reloaded connection settings; tool calls on any
+	// transport are bound to the current cluster definitions by the tools.
+	readyz := (&readinessChecker{}).handler(rl.current)
+	allowedOrigins := func() []string {
+		origins, _ := parseAllowedOrigins(rl.current().AllowedOrigins)
+		return origins
+	}
+	httpContextFunc := func(ctx context.Context, req *http.Request) context.Context {
+		return mcpnats.NewComposedSSEContextFunc(rl.current().connection())(ctx, req)
+	}
+
 	switch cfg.Transport {
 	case "stdio":
 		srv := server.NewStdioServer(s)
-		srv.SetContextFunc(mcpnats.ComposedStdioContextFunc())
+		srv.SetContextFunc(mcpnats.NewComposedStdioContextFunc(cfg.connection()))
 		logger.Info("Starting NATS MCP server using stdio transport")
 		return srv.Listen(ctx, os.Stdin, os.Stdout)
 
@@ -230,54 +453,53 @@ func run(ctx context.Context, cfg *Config) error {
 		httpSrv := &http.Server{Addr: cfg.Address}
 		srv := server.NewSSEServer(
 			s,
-			server.WithSSEContextFunc(mcpnats.ComposedSSEContextFunc()),
+			server.WithSSEContextFunc(httpContextFunc),
 			server.WithHTTPServer(httpSrv),
 		)
-		httpSrv.Handler = newSSEHTTPMux(srv)
+		httpSrv.Handler = originHandler(allowedOrigins, newSSEHTTPMux(srv, readyz))
+		transport, err := withTLS(cfg, srv, httpSrv)
+		if err != nil {
+			return err
+		}
 		logger.Info("Starting NATS MCP server using SSE transport",
 			"address", cfg.Address,
+			"tls", tlsEnabled(cfg),
 		)
-		return runHTTPServer(ctx, srv, cfg.Address, "sse")
+		return runHTTPServer(ctx, transport, cfg.Address, "sse", cfg.ShutdownTimeout)
 
 	case "streamable-http":
 		httpSrv := &http.Server{Addr: cfg.Address}
 		srv := server.NewStreamableHTTPServer(s,
-			server.WithHTTPContextFunc(server.HTTPContextFunc(mcpnats.ComposedSSEContextFunc())),
+			server.WithHTTPContextFunc(httpContextFunc),
 			server.WithEndpointPath(cfg.EndpointPath),
 			server.WithStreamableHTTPServer(httpSrv),
 		)
-		httpSrv.Handler = newHTTPMux(cfg.EndpointPath, srv)
+		httpSrv.Handler = originHandler(allowedOrigins, newHTTPMux(cfg.EndpointPath, srv, readyz))
+		transport, err := withTLS(cfg, srv, httpSrv)
+		if err != nil {
+			return err
+		}
 
 		logger.Info("Starting NATS MCP server using Streamable HTTP transport",
 			"address", cfg.Address,
 			"endpointPath", cfg.EndpointPath,
+			"tls", tlsEnabled(cfg),
 		)
-		return runHTTPServer(ctx, srv, cfg.Address, "streamable-http")
+		return runHTTPServer(ctx, transport, cfg.Address, "streamable-http", cfg.ShutdownTimeout)
 	}
 
 	return nil
 }
 
-func envReadOnly() bool {
-	v := strings.TrimSpace(strings.ToLower(os.Getenv("MCP_NATS_READ_ONLY")))
-	return v == "1" || v == "true" || v == "yes"
-}
-
 func main() {
-	cfg := &Config{}
-
-	// Parse command line flags
-	flag.StringVar(&cfg.Transport, "transport", "streamable-http", "Transport type (stdio, sse or streamable-http)")
-	flag.StringVar(&cfg.Address, "address", "0.0.0.0:8000", "Address for HTTP server to listen on")
-	flag.StringVar(&cfg.Address, "sse-address", "0.0.0.0:8000", "Deprecated: use --address instead")
-	flag.StringVar(&cfg.EndpointPath, "endpoint-path", "/mcp", "Endpoint path for streamable-http server")
-	flag.StringVar(&cfg.LogLevel, "log-level", "info", "Log level (debug, info, warn, error)")
-	flag.BoolVar(&cfg.JSONLogs, "json-logs", false, "Output logs in JSON format")
-	flag.BoolVar(&cfg.NoAuthentication, "no-authentication", false, "Allow anonymous connections without credentials")
-	flag.StringVar(&cfg.NATSUser, "user", "", "NATS username or token (can also be set via NATS_USER env var)")
-	flag.StringVar(&cfg.NATSPassword, "password", "", "NATS password (can also be set via NATS_PASSWORD env var)")
-	flag.BoolVar(&cfg.ReadOnly, "read-only", envReadOnly(), "Omit mutating MCP tools; default from MCP_NATS_READ_ONLY (true/1/yes)")
-	flag.Parse()
+	cfg, err := loadConfig(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Configuration error: %v\n", err)
+		os.Exit(2)
+	}
 
 	// Validate configuration
 	if err := validateConfig(cfg); err != nil {
@@ -294,6 +516,7 @@ func main() {
 	logger.Info("Starting MCP NATS server",
 		"transport", cfg.Transport,
 		"version", Version,
+		"config", cfg.ConfigFile,
 	)
 
 	// Setup context with cancellation for graceful shutdown
@@ -1,15 +1,15 @@
 package main
 
 import (
-	"context"
-	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/sinadarbouy/mcp-nats/internal/logger"
+	"github.com/sinadarbouy/mcp-nats/tools/common"
 )
 
 func TestMain(m *testing.M) {
@@ -32,11 +32,10 @@ func TestHandleLivez(t *testing.T) {
 }
 
 func TestHandleReadyzUnavailable(t *testing.T) {
-	t.Setenv("NATS_URL", "nats://127.0.0.1:1")
-
+	cfg := &Config{NATSURL: "nats://127.0.0.1:1", NoAuthentication: true, ReadinessTimeout: defaultReadinessTimeout}
 	req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
 	rec := httptest.NewRecorder()
-	handleReadyz(rec, req)
+	(&readinessChecker{}).handler(func() *Config { return cfg })(rec, req)
 
 	if rec.Code != http.StatusServiceUnavailable {
 		t.Fatalf("expected status %d, got %d", http.StatusServiceUnavailable, rec.Code)
@@ -44,23 +43,24 @@ func TestHandleReadyzUnavailable(t *testing.T) {
 }
 
 func TestCheckNATSConnectivityReachable(t *testing.T) {
-	listener, err := net.Listen("tcp", "127.0.0.1:0")
-	if err != nil {
-		t.Fatalf("failed to listen: %v", err)
-	}
-	defer func() { _ = listener.Close() }()
+	addr, _ := fakeNATSServer(t)
+	target := targetStatus{Cluster: "default"}
+	checkTarget(&target, common.Connection{URL: "nats://" + addr, User: "admin", Password: "secret"}, time.Second, false)
 
-	go func() {
-		conn, acceptErr := listener.Accept()
-		if acceptErr == nil {
-			_ = conn.Close()
-		}
-	}()
+	if target.Status != targetOK {
+		t.Fatalf("expected reachable server to pass readiness check: %+v", target)
+	}
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
+func TestMetricsEndpoint(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rec := httptest.NewRecorder()
+	newHTTPMux("/mcp", http.NotFoundHandler(), http.NotFoundHandler()).ServeHTTP(rec, req)
 
-	if err := checkNATSConnectivity(ctx, listener.Addr().String()); err != nil {
-		t.Fatalf("expected reachable listener to pass readiness check: %v", err)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "mcp_nats_active_sessions") {
+		t.Fatalf("metrics output missing mcp_nats_active_sessions")
 	}
 }
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/sinadarbouy/mcp-nats/internal/logger"
+)
+
+// corsAllowedHeaders are the request headers MCP clients send.
+const corsAllowedHeaders = "Accept, Authorization, Content-Type, Last-Event-ID, Mcp-Protocol-Version, Mcp-Session-Id, X-Nats-URL, traceparent, tracestate"
+
+// parseAllowedOrigins splits the comma-separated --allowed-origins value.
+// Entries are origins such as https://app.example.com or * for any origin.
+func parseAllowedOrigins(value string) ([]string, error) {
+	var origins []string
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if entry != "*" {
+			u, err := url.Parse(entry)
+			if err != nil || u.Scheme == "" || u.Host == "" || (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+				return nil, fmt.Errorf("invalid allowed origin %q (want scheme://host[:port] or *)", entry)
+			}
+			entry = u.Scheme + "://" + u.Host
+		}
+		origins = append(origins, strings.ToLower(entry))
+	}
+	return origins, nil
+}
+
+// originAllowed reports whether a browser on origin may call the server.
+// Without configured origins only pages served from the local machine are
+// allowed, which protects servers on developer machines from DNS rebinding.
+func originAllowed(origin string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return isLocalOrigin(origin)
+	}
+	return containsOrigin(allowed, "*") || containsOrigin(allowed, strings.ToLower(origin))
+}
+
+// originTrusted reports whether a browser on an allowed origin may send
+// credentials: only origins allowed by name or, without configured origins,
+// local ones. Origins only allowed by * get no credentialed access, as that
+// would let any website act with the user's cookies or certificates.
+func originTrusted(origin string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return isLocalOrigin(origin)
+	}
+	return containsOrigin(allowed, strings.ToLower(origin))
+}
+
+func containsOrigin(allowed []string, origin string) bool {
+	for _, entry := range allowed {
+		if entry == origin {
+			return true
+		}
+	}
+	return false
+}
+
+func isLocalOrigin(origin string) bool {
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// originHandler rejects requests whose Origin header is not allowed and
+// answers CORS preflight requests. Requests without an Origin header come
+// from non-browser clients and pass through. allowedOrigins is called per
+// request so reloaded settings apply immediately.
+func originHandler(allowedOrigins func() []string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		origin := req.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		w.Header().Add("Vary", "Origin")
+		allowed := allowedOrigins()
+		if !originAllowed(origin, allowed) {
+			logger.Warn("Rejected request from disallowed origin",
+				"origin", origin,
+				"path", req.URL.Path,
+				"remoteAddr", req.RemoteAddr,
+			)
+			http.Error(w, "origin not allowed", http.StatusForbidden)
+			return
+		}
+
+		h := w.Header()
+		h.Set("Access-Control-Allow-Origin", origin)
+		if originTrusted(origin, allowed) {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+		h.Set("Access-Control-Expose-Headers", "Mcp-Session-Id, Mcp-Protocol-Version")
+		if req.Method == http.MethodOptions && req.Header.Get("Access-Control-Request-Method") != "" {
+			h.Add("Vary", "Access-Control-Request-Method")
+			h.Add("Vary", "Access-Control-Request-Headers")
+			h.Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+			h.Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+			h.Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
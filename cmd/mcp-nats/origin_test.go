@@ -0,0 +1,88 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOriginHandler(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	cases := []struct {
+		name    string
+		allowed string
+		method  string
+		origin  string
+		want    int
+	}{
+		{"no origin", "", http.MethodPost, "", http.StatusOK},
+		{"localhost", "", http.MethodPost, "http://localhost:6274", http.StatusOK},
+		{"loopback IP", "", http.MethodPost, "http://127.0.0.1:3000", http.StatusOK},
+		{"IPv6 loopback", "", http.MethodPost, "http://[::1]:3000", http.StatusOK},
+		{"rebinding", "", http.MethodPost, "http://attacker.example", http.StatusForbidden},
+		{"localhost lookalike", "", http.MethodPost, "http://localhost.attacker.example", http.StatusForbidden},
+		{"null origin", "", http.MethodPost, "null", http.StatusForbidden},
+		{"configured", "https://app.example.com", http.MethodPost, "https://APP.example.com", http.StatusOK},
+		{"configured excludes localhost", "https://app.example.com", http.MethodPost, "http://localhost:6274", http.StatusForbidden},
+		{"wildcard", "*", http.MethodPost, "https://anything.example", http.StatusOK},
+		{"wildcard preflight", "*", http.MethodOptions, "https://anything.example", http.StatusNoContent},
+		{"preflight", "https://app.example.com", http.MethodOptions, "https://app.example.com", http.StatusNoContent},
+		{"rejected preflight", "https://app.example.com", http.MethodOptions, "https://evil.example", http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			allowed, err := parseAllowedOrigins(tc.allowed)
+			if err != nil {
+				t.Fatalf("parseAllowedOrigins: %v", err)
+			}
+			req := httptest.NewRequest(tc.method, "/mcp", nil)
+			if tc.origin != "" {
+				req.Header.Set("Origin", tc.origin)
+			}
+			if tc.method == http.MethodOptions {
+				req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+				req.Header.Set("Access-Control-Request-Headers", "content-type, mcp-session-id")
+			}
+			rec := httptest.NewRecorder()
+			originHandler(func() []string { return allowed }, next).ServeHTTP(rec, req)
+
+			if rec.Code != tc.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tc.want)
+			}
+			allowOrigin := rec.Header().Get("Access-Control-Allow-Origin")
+			switch {
+			case tc.origin == "" || tc.want == http.StatusForbidden:
+				if allowOrigin != "" {
+					t.Fatalf("Access-Control-Allow-Origin = %q, want none", allowOrigin)
+				}
+			case allowOrigin != tc.origin:
+				t.Fatalf("Access-Control-Allow-Origin = %q, want %q", allowOrigin, tc.origin)
+			}
+			credentials := rec.Header().Get("Access-Control-Allow-Credentials") == "true"
+			if tc.origin != "" && tc.want != http.StatusForbidden && credentials != (tc.allowed != "*") {
+				t.Fatalf("Access-Control-Allow-Credentials = %v, want credentials only for origins allowed by name", credentials)
+			}
+			if tc.want == http.StatusNoContent && rec.Header().Get("Access-Control-Allow-Methods") == "" {
+				t.Fatalf("preflight response lacks Access-Control-Allow-Methods")
+			}
+		})
+	}
+}
+
+func TestParseAllowedOrigins(t *testing.T) {
+	origins, err := parseAllowedOrigins(" https://App.example.com/ , http://localhost:3000,*")
+	if err != nil {
+		t.Fatalf("parseAllowedOrigins: %v", err)
+	}
+	if len(origins) != 3 || origins[0] != "https://app.example.com" || origins[2] != "*" {
+		t.Fatalf("origins = %v", origins)
+	}
+	for _, invalid := range []string{"app.example.com", "https://app.example.com/path", "https://"} {
+		if _, err := parseAllowedOrigins(invalid); err == nil {
+			t.Fatalf("parseAllowedOrigins(%q) accepted an invalid origin", invalid)
+		}
+	}
+}
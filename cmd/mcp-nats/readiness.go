@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/sinadarbouy/mcp-nats/internal/logger"
+	"github.com/sinadarbouy/mcp-nats/internal/metrics"
+	"github.com/sinadarbouy/mcp-nats/tools/common"
+)
+
+// Readiness states of the report and of each target.
+const (
+	readinessReady       = "ready"
+	readinessDegraded    = "degraded"
+	readinessUnavailable = "unavailable"
+	targetOK             = "ok"
+	targetError          = "error"
+)
+
+// readinessReport is the outcome of a readiness check. Only its status is
+// served, as /readyz is not authenticated; failed targets are logged.
+type readinessReport struct {
+	Status    string
+	CheckedAt time.Time
+	Targets   []targetStatus
+}
+
+// readinessResponse is the JSON body of /readyz.
+type readinessResponse struct {
+	Status string `json:"status"`
+}
+
+// targetStatus is the outcome of checking one account of one cluster.
+type targetStatus struct {
+	Cluster   string
+	Account   string
+	URL       string
+	Status    string
+	JetStream string
+	Error     string
+	LatencyMS int64
+}
+
+// readinessChecker connects to every configured cluster as every configured
+// account and caches the result, so probe storms do not turn into
+// connection storms against NATS. Concurrent probes wait for a running check
+// and share its result.
+type readinessChecker struct {
+	mu     sync.Mutex
+	cfg    *Config
+	report readinessReport
+}
+
+// check returns the cached report for cfg if it is recent enough, and runs
+// the checks otherwise. A reloaded configuration is always checked afresh.
+func (c *readinessChecker) check(cfg *Config) readinessReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cfg == cfg && time.Since(c.report.CheckedAt) < cfg.ReadinessCacheTTL {
+		return c.report
+	}
+	c.report = checkReadiness(cfg)
+	c.cfg = cfg
+	metrics.ObserveReadiness(c.report.Status != readinessUnavailable)
+	return c.report
+}
+
+// handler serves /readyz for the configuration returned by current.
+func (c *readinessChecker) handler(current func() *Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		report := c.check(current())
+
+		code := http.StatusOK
+		if report.Status == readinessUnavailable {
+			code = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(code)
+		if err := json.NewEncoder(w).Encode(readinessResponse{Status: report.Status}); err != nil {
+			logger.Debug("Failed to write readiness report", "error", err)
+		}
+	}
+}
+
+// checkReadiness checks all targets of cfg concurrently. The server is
+// unavailable when a required account or every target fails, and degraded
+// when only some other accounts fail.
+func checkReadiness(cfg *Config) readinessReport {
+	var targets []targetStatus
+	var conns []common.Connection
+	for _, conn := range cfg.connections() {
+		accounts := []string{""}
+		if names := conn.AccountNames(); conn.IsAccountNameRequired() && len(names) > 0 {
+			accounts = names
+		}
+		for _, account := range accounts {
+			targets = append(targets, targetStatus{Cluster: conn.Name, Account: account, URL: redactURL(readinessURL(conn.URL))})
+			conns = append(conns, conn)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checkTarget(&targets[i], conns[i], cfg.ReadinessTimeout, cfg.ReadinessJetStream)
+		}()
+	}
+	wg.Wait()
+
+	required := parseRequiredAccounts(cfg.ReadinessRequiredAccounts)
+	failed, requiredFailed := 0, false
+	for _, target := range targets {
+		if target.Status == targetOK {
+			continue
+		}
+		failed++
+		isRequired := required[target.Account] || required[target.Cluster+"/"+target.Account]
+		requiredFailed = requiredFailed || isRequired
+		logger.Warn("Readiness check failed",
+			"cluster", target.Cluster,
+			"account", target.Account,
+			"url", target.URL,
+			"required", isRequired,
+			"error", target.Error,
+		)
+	}
+
+	report := readinessReport{Status: readinessReady, CheckedAt: time.Now(), Targets: targets}
+	switch {
+	case requiredFailed || failed > 0 && failed == len(targets):
+		report.Status = readinessUnavailable
+	case failed > 0:
+		report.Status = readinessDegraded
+	}
+	return report
+}
+
+// parseRequiredAccounts splits the comma-separated required accounts.
+func parseRequiredAccounts(value string) map[string]bool {
+	required := make(map[string]bool)
+	for _, account := range strings.Split(value, ",") {
+		if account = strings.TrimSpace(account); account != "" {
+			required[account] = true
+		}
+	}
+	return required
+}
+
+// checkTarget connects to the cluster of target as its account and, if
+// checkJetStream is set, queries the JetStream account information.
+func checkTarget(target *targetStatus, conn common.Connection, timeout time.Duration, checkJetStream bool) {
+	start := time.Now()
+	defer func() { target.LatencyMS = time.Since(start).Milliseconds() }()
+	fail := func(err error) {
+		target.Status = targetError
+		target.Error = err.Error()
+	}
+
+	if conn.IsAccountNameRequired() && target.Account == "" {
+		fail(fmt.Errorf("no credentials configured"))
+		return
+	}
+	strategy, err := conn.AuthStrategy(target.Account)
+	if err != nil {
+		fail(err)
+		return
+	}
+	if target.Account == "" {
+		target.Account = strategy.GetAccountName()
+	}
+	defer func() {
+		if err := strategy.Cleanup(); err != nil {
+			logger.Debug("Failed to clean up readiness credentials", "error", err)
+		}
+	}()
+
+	nc, err := common.Connect(readinessURL(conn.URL), strategy,
+		nats.Name("mcp-nats-readiness"),
+		nats.Timeout(timeout),
+		nats.NoReconnect(),
+	)
+	if err != nil {
+		fail(err)
+		return
+	}
+	defer nc.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := nc.FlushWithContext(ctx); err != nil {
+		fail(err)
+		return
+	}
+	target.Status = targetOK
+
+	if !checkJetStream {
+		return
+	}
+	js, err := jetstream.New(nc)
+	if err == nil {
+		_, err = js.AccountInfo(ctx)
+	}
+	if err != nil {
+		target.JetStream = targetError
+		fail(err)
+		return
+	}
+	target.JetStream = targetOK
+}
+
+// readinessURL returns the URL the readiness check connects to.
+func readinessURL(natsURL string) string {
+	if natsURL = strings.TrimSpace(natsURL); natsURL == "" {
+		return "localhost:4222"
+	}
+	return natsURL
+}
+
+// redactURL removes user information such as tokens and passwords from
+// natsURL.
+func redactURL(natsURL string) string {
+	u, err := url.Parse(natsURL)
+	if err != nil || u.User == nil {
+		return natsURL
+	}
+	u.User = nil
+	return u.String()
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sinadarbouy/mcp-nats/tools/common"
+)
+
+// fakeNATSServer speaks enough of the NATS protocol to complete a client
+// handshake. Only clients authenticating as admin/secret are accepted;
+// JetStream API requests are never answered.
+func fakeNATSServer(t *testing.T) (string, *atomic.Int32) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	var connections atomic.Int32
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			connections.Add(1)
+			go func() {
+				defer conn.Close()
+				_, _ = conn.Write([]byte(`INFO {"server_id":"fake","version":"2.10.0","proto":1,"headers":true,"max_payload":1048576,"auth_required":true}` + "\r\n"))
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					line := scanner.Text()
+					switch {
+					case strings.HasPrefix(line, "CONNECT "):
+						var opts struct {
+							User string `json:"user"`
+							Pass string `json:"pass"`
+						}
+						_ = json.Unmarshal([]byte(strings.TrimPrefix(line, "CONNECT ")), &opts)
+						if opts.User != "admin" || opts.Pass != "secret" {
+							_, _ = conn.Write([]byte("-ERR 'Authorization Violation'\r\n"))
+							return
+						}
+					case line == "PING":
+						_, _ = conn.Write([]byte("PONG\r\n"))
+					}
+				}
+			}()
+		}
+	}()
+	return ln.Addr().String(), &connections
+}
+
+// getReadyz probes handler and returns the status code and the reported
+// status, checking that the public body holds nothing else.
+func getReadyz(t *testing.T, handler http.Handler) (int, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	var body map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid readiness body %q: %v", rec.Body.String(), err)
+	}
+	status, _ := body["status"].(string)
+	if len(body) != 1 || status == "" {
+		t.Fatalf("readiness body %q should only report the status", rec.Body.String())
+	}
+	return rec.Code, status
+}
+
+func TestReadyz_authenticatesEveryCluster(t *testing.T) {
+	addr, connections := fakeNATSServer(t)
+	cfg := &Config{
+		NATSURL:           "nats://" + addr,
+		NATSUser:          "admin",
+		NATSPassword:      "secret",
+		ClusterName:       "prod",
+		ReadinessTimeout:  time.Second,
+		ReadinessCacheTTL: time.Minute,
+	}
+	current := func() *Config { return cfg }
+	checker := &readinessChecker{}
+	handler := checker.handler(current)
+
+	if code, status := getReadyz(t, handler); code != http.StatusOK || status != readinessReady {
+		t.Fatalf("readyz = %d %s, want ready", code, status)
+	}
+	if targets := checker.report.Targets; len(targets) != 1 || targets[0].Cluster != "prod" || targets[0].Account != "userpass_admin" {
+		t.Fatalf("targets = %+v", targets)
+	}
+
+	// A cluster with the wrong credentials only degrades the server, even
+	// though its socket accepts connections.
+	cfg = &Config{
+		NATSURL:          cfg.NATSURL,
+		NATSUser:         "admin",
+		NATSPassword:     "secret",
+		ClusterName:      "prod",
+		ReadinessTimeout: time.Second,
+		Clusters: []common.Connection{
+			{Name: "edge", URL: "nats://user:hunter2@" + addr, User: "admin", Password: "wrong"},
+		},
+		ReadinessCacheTTL: time.Minute,
+	}
+	if code, status := getReadyz(t, handler); code != http.StatusOK || status != readinessDegraded {
+		t.Fatalf("readyz = %d %s, want degraded", code, status)
+	}
+	targets := checker.report.Targets
+	edge := targets[1]
+	if targets[0].Status != targetOK || edge.Status != targetError || !strings.Contains(edge.Error, "uthorization") {
+		t.Fatalf("targets = %+v", targets)
+	}
+	if strings.Contains(edge.URL, "hunter2") {
+		t.Fatalf("target URL %q leaks credentials", edge.URL)
+	}
+
+	// Probes within the cache TTL reuse the result.
+	before := connections.Load()
+	for range 5 {
+		getReadyz(t, handler)
+	}
+	if after := connections.Load(); after != before {
+		t.Fatalf("cached readiness opened %d new connections", after-before)
+	}
+
+	// A failed required account makes the server unavailable.
+	for _, required := range []string{"edge/userpass_admin", "userpass_admin"} {
+		next := *cfg
+		next.ReadinessRequiredAccounts = "SYS, " + required
+		cfg = &next
+		if code, status := getReadyz(t, handler); code != http.StatusServiceUnavailable || status != readinessUnavailable {
+			t.Fatalf("required %s: readyz = %d %s, want unavailable", required, code, status)
+		}
+	}
+	next := *cfg
+	next.ReadinessRequiredAccounts = "prod/userpass_admin"
+	cfg = &next
+	if code, status := getReadyz(t, handler); code != http.StatusOK || status != readinessDegraded {
+		t.Fatalf("readyz = %d %s, want degraded while the required account passes", code, status)
+	}
+}
+
+func TestReadyz_jetStreamAndMissingCredentials(t *testing.T) {
+	addr, _ := fakeNATSServer(t)
+	cfg := &Config{
+		NATSURL:            "nats://" + addr,
+		NATSUser:           "admin",
+		NATSPassword:       "secret",
+		ReadinessTimeout:   200 * time.Millisecond,
+		ReadinessJetStream: true,
+		Clusters:           []common.Connection{{Name: "creds", URL: "nats://" + addr}},
+	}
+
+	// Every target failing makes the server unavailable.
+	report := checkReadiness(cfg)
+	if report.Status != readinessUnavailable || len(report.Targets) != 2 {
+		t.Fatalf("report = %+v", report)
+	}
+	if js := report.Targets[0]; js.JetStream != targetError || js.Status != targetError {
+		t.Fatalf("JetStream target = %+v, want a failed JetStream check", js)
+	}
+	if creds := report.Targets[1]; creds.Status != targetError || creds.Error != "no credentials configured" {
+		t.Fatalf("credentials target = %+v", creds)
+	}
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sinadarbouy/mcp-nats/internal/audit"
+	"github.com/sinadarbouy/mcp-nats/internal/logger"
+	"github.com/sinadarbouy/mcp-nats/tools"
+)
+
+// configWatchInterval is how often --watch-config checks the configuration
+// file for changes.
+const configWatchInterval = 2 * time.Second
+
+// reloader applies configuration changes to a running server without
+// dropping MCP sessions. Credentials, cluster definitions, policies, timeouts
+// and the log level are reloaded; listener, log format and audit output
+// settings keep their values until a restart.
+type reloader struct {
+	server    *server.MCPServer
+	natsTools *tools.NATSServerTools
+	recorder  *audit.Recorder
+
+	mu  sync.Mutex
+	cfg *Config
+}
+
+func newReloader(cfg *Config, s *server.MCPServer, natsTools *tools.NATSServerTools, recorder *audit.Recorder) *reloader {
+	return &reloader{
+		server:    s,
+		natsTools: natsTools,
+		recorder:  recorder,
+		cfg:       cfg,
+	}
+}
+
+// current returns the configuration in effect.
+func (r *reloader) current() *Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cfg
+}
+
+// reload loads the configuration again from the same command line and
+// applies it. An invalid configuration leaves the running one untouched.
+func (r *reloader) reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cfg, err := loadConfig(r.cfg.args)
+	if err != nil {
+		return err
+	}
+	if err := validateConfig(cfg); err != nil {
+		return err
+	}
+
+	old := r.cfg
+	if kept := keepRestartSettings(old, cfg); len(kept) > 0 {
+		logger.Warn("Ignoring configuration changes that require a restart",
+			"settings", strings.Join(kept, ", "),
+		)
+	}
+
+	logger.SetLevel(logger.GetLevel(cfg.LogLevel))
+	if err := r.natsTools.SetClusters(cfg.connections()); err != nil {
+		return err
+	}
+	if toolSettingsOf(old) != toolSettingsOf(cfg) {
+		if err := applyToolPolicies(r.server, r.natsTools, cfg, r.recorder); err != nil {
+			return err
+		}
+		logger.Info("Re-registered tools after a policy change", "readOnly", cfg.ReadOnly)
+	}
+
+	r.cfg = cfg
+	logger.Info("Configuration reloaded", "config", cfg.ConfigFile)
+	return nil
+}
+
+// watchSignals reloads the configuration on SIGHUP until ctx is done.
+func (r *reloader) watchSignals(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			logger.Info("Received SIGHUP, reloading configuration")
+			if err := r.reload(); err != nil {
+				logger.Error("Failed to reload configuration", "error", err)
+			}
+		}
+	}
+}
+
+// watchFile reloads the configuration whenever the modification time or size
+// of the configuration file changes, checking every interval until ctx is
+// done.
+func (r *reloader) watchFile(ctx context.Context, interval time.Duration) {
+	path := r.current().ConfigFile
+	if path == "" {
+		logger.Warn("--watch-config has no effect without a configuration file")
+		return
+	}
+
+	stat := func() (time.Time, int64) {
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, -1
+		}
+		return info.ModTime(), info.Size()
+	}
+	modTime, size := stat()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m, s := stat()
+			if s < 0 || (m.Equal(modTime) && s == size) {
+				continue
+			}
+			modTime, size = m, s
+			logger.Info("Configuration file changed, reloading", "config", path)
+			if err := r.reload(); err != nil {
+				logger.Error("Failed to reload configuration", "error", err)
+			}
+		}
+	}
+}
+
+// keepRestartSettings resets the settings of cfg that cannot change while
+// the server runs to their values in old, and returns the names of those
+// that differed.
+func keepRestartSettings(old, cfg *Config) []string {
+	var kept []string
+	keepSetting(&kept, "transport", old.Transport, &cfg.Transport)
+	keepSetting(&kept, "address", old.Address, &cfg.Address)
+	keepSetting(&kept, "endpoint-path", old.EndpointPath, &cfg.EndpointPath)
+	keepSetting(&kept, "tls-cert", old.TLSCert, &cfg.TLSCert)
+	keepSetting(&kept, "tls-key", old.TLSKey, &cfg.TLSKey)
+	keepSetting(&kept, "tls-client-ca", old.TLSClientCA, &cfg.TLSClientCA)
+	keepSetting(&kept, "tls-client-auth", old.TLSClientAuth, &cfg.TLSClientAuth)
+	keepSetting(&kept, "json-logs", old.JSONLogs, &cfg.JSONLogs)
+	keepSetting(&kept, "audit-file", old.AuditFile, &cfg.AuditFile)
+	keepSetting(&kept, "audit-subject", old.AuditSubject, &cfg.AuditSubject)
+	keepSetting(&kept, "audit-stream", old.AuditStream, &cfg.AuditStream)
+	keepSetting(&kept, "audit-account", old.AuditAccount, &cfg.AuditAccount)
+	keepSetting(&kept, "audit-buffer", old.AuditBufferSize, &cfg.AuditBufferSize)
+	keepSetting(&kept, "shutdown-timeout", old.ShutdownTimeout, &cfg.ShutdownTimeout)
+	keepSetting(&kept, "watch-config", old.WatchConfig, &cfg.WatchConfig)
+	return kept
+}
+
+func keepSetting[T comparable](kept *[]string, name string, old T, cur *T) {
+	if *cur != old {
+		*kept = append(*kept, name)
+		*cur = old
+	}
+}
+
+// toolSettings are the settings that shape the registered tool catalog:
+// which tools exist, their schemas and the middlewares wrapping them.
+type toolSettings struct {
+	readOnly              bool
+	rateLimitCalls        float64
+	rateLimitBurst        int
+	rateLimitMessages     float64
+	rateLimitMessageBurst int
+	rateLimitTools        string
+	toolTimeout           time.Duration
+	clusters              string
+	accountNameRequired   bool
+	benchMaxMessages      int
+	benchMaxMessageSize   int
+	benchMaxClients       int
+	benchMaxDuration      time.Duration
+	resourceMaxObjectSize int
+}
+
+func toolSettingsOf(cfg *Config) toolSettings {
+	settings := toolSettings{
+		readOnly:              cfg.ReadOnly,
+		rateLimitCalls:        cfg.RateLimitCalls,
+		rateLimitBurst:        cfg.RateLimitBurst,
+		rateLimitMessages:     cfg.RateLimitMessages,
+		rateLimitMessageBurst: cfg.RateLimitMessageBurst,
+		rateLimitTools:        cfg.RateLimitTools,
+		toolTimeout:           cfg.ToolTimeout,
+		benchMaxMessages:      cfg.BenchMaxMessages,
+		benchMaxMessageSize:   cfg.BenchMaxMessageSize,
+		benchMaxClients:       cfg.BenchMaxClients,
+		benchMaxDuration:      cfg.BenchMaxDuration,
+		resourceMaxObjectSize: cfg.ResourceMaxObjectSize,
+	}
+	var names []string
+	for _, conn := range cfg.connections() {
+		names = append(names, conn.Name)
+		settings.accountNameRequired = settings.accountNameRequired || conn.IsAccountNameRequired()
+	}
+	settings.clusters = strings.Join(names, ",")
+	return settings
+}
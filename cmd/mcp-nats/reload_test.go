@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+type testSession struct {
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *testSession) Initialize()       {}
+func (s *testSession) Initialized() bool { return true }
+func (s *testSession) SessionID() string { return "reload-test" }
+func (s *testSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+
+func TestReloader_appliesChanges(t *testing.T) {
+	path := writeConfigFile(t, "mcp-nats.yaml", `
+transport: stdio
+clusters:
+  - name: prod
+    url: nats://prod:4222
+    no_authentication: true
+`)
+	cfg, err := loadConfig([]string{"--config", path})
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+	s, natsTools, _, err := newServer(cfg, nil)
+	if err != nil {
+		t.Fatalf("newServer: %v", err)
+	}
+	rl := newReloader(cfg, s, natsTools, nil)
+
+	session := &testSession{notifications: make(chan mcp.JSONRPCNotification, 10)}
+	if err := s.RegisterSession(context.Background(), session); err != nil {
+		t.Fatalf("RegisterSession: %v", err)
+	}
+	defer s.UnregisterSession(context.Background(), session.SessionID())
+
+	rewrite := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("failed to rewrite config file: %v", err)
+		}
+	}
+	listChanged := func() bool {
+		for {
+			select {
+			case n := <-session.notifications:
+				if n.Method == mcp.MethodNotificationToolsListChanged {
+					return true
+				}
+			default:
+				return false
+			}
+		}
+	}
+
+	// A new cluster and read-only mode change the tool catalog; the
+	// transport cannot change without a restart.
+	rewrite(`
+transport: sse
+logging:
+  level: debug
+clusters:
+  - name: prod
+    url: nats://prod:4222
+    no_authentication: true
+  - name: edge
+    url: nats://edge:4222
+    no_authentication: true
+policies:
+  read_only: true
+`)
+	if err := rl.reload(); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	current := rl.current()
+	if !current.ReadOnly || current.Transport != "stdio" || current.LogLevel != "debug" {
+		t.Fatalf("reloaded config = read-only %v, transport %q, log level %q", current.ReadOnly, current.Transport, current.LogLevel)
+	}
+	if s.GetTool("kv_put") != nil {
+		t.Fatalf("mutating tool still registered in read-only mode")
+	}
+	if _, ok := s.GetTool("stream_info").Tool.InputSchema.Properties["nats_cluster"]; !ok {
+		t.Fatalf("nats_cluster argument missing after adding a cluster")
+	}
+	if !listChanged() {
+		t.Fatalf("expected notifications/tools/list_changed")
+	}
+
+	// Only the log level changes: the tools stay as they are.
+	rewrite(strings.Replace(`
+transport: stdio
+logging:
+  level: debug
+clusters:
+  - name: prod
+    url: nats://prod:4222
+    no_authentication: true
+  - name: edge
+    url: nats://edge:4222
+    no_authentication: true
+policies:
+  read_only: true
+`, "debug", "warn", 1))
+	if err := rl.reload(); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if listChanged() {
+		t.Fatalf("tools re-registered although no tool setting changed")
+	}
+	current = rl.current()
+
+	// An invalid file keeps the running configuration.
+	rewrite("policies:\n  read_only: sometimes\n")
+	if err := rl.reload(); err == nil {
+		t.Fatalf("reload accepted an invalid configuration")
+	}
+	if rl.current() != current {
+		t.Fatalf("invalid configuration replaced the running one")
+	}
+}
+
+func TestReloader_appliesBenchLimits(t *testing.T) {
+	config := `
+transport: stdio
+clusters:
+  - url: nats://prod:4222
+    no_authentication: true
+policies:
+  bench:
+    max_messages: %d
+`
+	path := writeConfigFile(t, "mcp-nats.yaml", fmt.Sprintf(config, 1000))
+	cfg, err := loadConfig([]string{"--config", path})
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+	s, natsTools, _, err := newServer(cfg, nil)
+	if err != nil {
+		t.Fatalf("newServer: %v", err)
+	}
+	rl := newReloader(cfg, s, natsTools, nil)
+
+	if err := os.WriteFile(path, []byte(fmt.Sprintf(config, 500)), 0600); err != nil {
+		t.Fatalf("failed to rewrite config file: %v", err)
+	}
+	if err := rl.reload(); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	messages := s.GetTool("bench").Tool.InputSchema.Properties["messages"].(map[string]interface{})
+	if !strings.Contains(messages["description"].(string), "at most 500") {
+		t.Fatalf("bench limits not reloaded: %v", messages["description"])
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sinadarbouy/mcp-nats/internal/logger"
+)
+
+// Client certificate policies for --tls-client-auth.
+const (
+	clientAuthRequire       = "require"
+	clientAuthVerifyIfGiven = "verify-if-given"
+)
+
+// tlsEnabled reports whether the HTTP listener serves TLS.
+func tlsEnabled(cfg *Config) bool {
+	return cfg.TLSCert != "" || cfg.TLSKey != ""
+}
+
+// validateTLSConfig checks the TLS settings of cfg.
+func validateTLSConfig(cfg *Config) error {
+	if !tlsEnabled(cfg) {
+		if cfg.TLSClientCA != "" {
+			return fmt.Errorf("tls-client-ca requires tls-cert and tls-key")
+		}
+		return nil
+	}
+	if cfg.Transport == "stdio" {
+		return fmt.Errorf("TLS is not supported with stdio transport")
+	}
+	if cfg.TLSCert == "" || cfg.TLSKey == "" {
+		return fmt.Errorf("tls-cert and tls-key must be set together")
+	}
+	if cfg.TLSClientAuth != clientAuthRequire && cfg.TLSClientAuth != clientAuthVerifyIfGiven {
+		return fmt.Errorf("invalid tls-client-auth: %s (must be '%s' or '%s')", cfg.TLSClientAuth, clientAuthRequire, clientAuthVerifyIfGiven)
+	}
+	return nil
+}
+
+// certReloader serves the certificate and client CA bundle of the HTTP
+// listener, reading the files again whenever they change on disk so that
+// rotated certificates are picked up without a restart. A file that cannot
+// be loaded keeps the previous contents in use.
+type certReloader struct {
+	certFile   string
+	keyFile    string
+	clientCA   string
+	clientAuth tls.ClientAuthType
+
+	mu       sync.Mutex
+	cert     *tls.Certificate
+	certMod  [2]time.Time
+	caPool   *x509.CertPool
+	caMod    time.Time
+	lastStat time.Time
+}
+
+// certCheckInterval limits how often handshakes look for rotated files.
+const certCheckInterval = time.Second
+
+func newCertReloader(cfg *Config) (*certReloader, error) {
+	r := &certReloader{
+		certFile: cfg.TLSCert,
+		keyFile:  cfg.TLSKey,
+		clientCA: cfg.TLSClientCA,
+	}
+	if r.clientCA != "" {
+		r.clientAuth = tls.RequireAndVerifyClientCert
+		if cfg.TLSClientAuth == clientAuthVerifyIfGiven {
+			r.clientAuth = tls.VerifyClientCertIfGiven
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.loadCertificate(); err != nil {
+		return nil, err
+	}
+	if err := r.loadClientCA(); err != nil {
+		return nil, err
+	}
+	r.lastStat = time.Now()
+	return r, nil
+}
+
+// tlsConfig returns the listener configuration. Every handshake gets the
+// current certificate and client CA bundle.
+func (r *certReloader) tlsConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, pool := r.current()
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+				ClientCAs:    pool,
+				ClientAuth:   r.clientAuth,
+				NextProtos:   []string{"h2", "http/1.1"},
+			}, nil
+		},
+	}
+}
+
+// current reloads files that changed since the last check and returns the
+// certificate and client CA pool to use.
+func (r *certReloader) current() (*tls.Certificate, *x509.CertPool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.lastStat) >= certCheckInterval {
+		r.lastStat = time.Now()
+		if modTime(r.certFile) != r.certMod[0] || modTime(r.keyFile) != r.certMod[1] {
+			if err := r.loadCertificate(); err != nil {
+				logger.Error("Failed to reload TLS certificate, keeping the previous one", "error", err)
+			} else {
+				logger.Info("Reloaded TLS certificate", "cert", r.certFile)
+			}
+		}
+		if r.clientCA != "" && modTime(r.clientCA) != r.caMod {
+			if err := r.loadClientCA(); err != nil {
+				logger.Error("Failed to reload TLS client CA bundle, keeping the previous one", "error", err)
+			} else {
+				logger.Info("Reloaded TLS client CA bundle", "clientCA", r.clientCA)
+			}
+		}
+	}
+	return r.cert, r.caPool
+}
+
+func (r *certReloader) loadCertificate() error {
+	certMod, keyMod := modTime(r.certFile), modTime(r.keyFile)
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	r.cert = &cert
+	r.certMod = [2]time.Time{certMod, keyMod}
+	return nil
+}
+
+func (r *certReloader) loadClientCA() error {
+	if r.clientCA == "" {
+		return nil
+	}
+	caMod := modTime(r.clientCA)
+	data, err := os.ReadFile(r.clientCA)
+	if err != nil {
+		return fmt.Errorf("failed to read TLS client CA bundle: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return fmt.Errorf("no certificates found in TLS client CA bundle %s", r.clientCA)
+	}
+	r.caPool = pool
+	r.caMod = caMod
+	return nil
+}
+
+func modTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// tlsHTTPServer serves an MCP HTTP transport over TLS. The transports only
+// know how to start plain HTTP listeners, so Start is replaced while
+// Shutdown still closes the transport's sessions.
+type tlsHTTPServer struct {
+	httpServer
+	srv *http.Server
+}
+
+func (s tlsHTTPServer) Start(string) error {
+	return s.srv.ListenAndServeTLS("", "")
+}
+
+// withTLS configures srv to serve TLS if cfg enables it and wraps transport
+// accordingly.
+func withTLS(cfg *Config, transport httpServer, srv *http.Server) (httpServer, error) {
+	if !tlsEnabled(cfg) {
+		return transport, nil
+	}
+	reloader, err := newCertReloader(cfg)
+	if err != nil {
+		return nil, err
+	}
+	srv.TLSConfig = reloader.tlsConfig()
+	return tlsHTTPServer{httpServer: transport, srv: srv}, nil
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	mcpnats "github.com/sinadarbouy/mcp-nats"
+	"github.com/sinadarbouy/mcp-nats/internal/logger"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate CA key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create CA certificate: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a PEM certificate and key signed by the CA.
+func (ca *testCA) issue(t *testing.T, cn string, serial int64, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: cn, Organization: []string{"ops"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func TestCertReloader_mutualTLS(t *testing.T) {
+	logger.Initialize(logger.Config{Level: logger.LevelError})
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	cfg := &Config{
+		TLSCert:       filepath.Join(dir, "server.crt"),
+		TLSKey:        filepath.Join(dir, "server.key"),
+		TLSClientCA:   filepath.Join(dir, "ca.crt"),
+		TLSClientAuth: clientAuthRequire,
+	}
+	serverCert, serverKey := ca.issue(t, "server", 2, x509.ExtKeyUsageServerAuth)
+	writeFile(t, cfg.TLSCert, serverCert)
+	writeFile(t, cfg.TLSKey, serverKey)
+	writeFile(t, cfg.TLSClientCA, ca.pem)
+
+	reloader, err := newCertReloader(cfg)
+	if err != nil {
+		t.Fatalf("newCertReloader: %v", err)
+	}
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", reloader.tlsConfig())
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := mcpnats.ExtractInboundIdentity(req.Context(), req)
+		_, _ = io.WriteString(w, mcpnats.GetInboundIdentityFromContext(ctx))
+	})}
+	go func() { _ = srv.Serve(ln) }()
+	defer srv.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	get := func(clientCerts []tls.Certificate) (string, *x509.Certificate, error) {
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			RootCAs:      roots,
+			Certificates: clientCerts,
+		}}}
+		defer client.CloseIdleConnections()
+		resp, err := client.Get("https://" + ln.Addr().String())
+		if err != nil {
+			return "", nil, err
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body), resp.TLS.PeerCertificates[0], nil
+	}
+
+	if _, _, err := get(nil); err == nil {
+		t.Fatalf("request without a client certificate succeeded")
+	}
+
+	clientCert, clientKey := ca.issue(t, "alice", 3, x509.ExtKeyUsageClientAuth)
+	pair, err := tls.X509KeyPair(clientCert, clientKey)
+	if err != nil {
+		t.Fatalf("failed to load client certificate: %v", err)
+	}
+	identity, served, err := get([]tls.Certificate{pair})
+	if err != nil {
+		t.Fatalf("request with a client certificate: %v", err)
+	}
+	if identity != "CN=alice,O=ops" {
+		t.Fatalf("inbound identity = %q, want the client certificate subject", identity)
+	}
+
+	// A rotated certificate is served without restarting the listener.
+	rotatedCert, rotatedKey := ca.issue(t, "server", 4, x509.ExtKeyUsageServerAuth)
+	writeFile(t, cfg.TLSCert, rotatedCert)
+	writeFile(t, cfg.TLSKey, rotatedKey)
+	later := time.Now().Add(time.Minute)
+	for _, path := range []string{cfg.TLSCert, cfg.TLSKey} {
+		if err := os.Chtimes(path, later, later); err != nil {
+			t.Fatalf("failed to touch %s: %v", path, err)
+		}
+	}
+	reloader.mu.Lock()
+	reloader.lastStat = time.Time{}
+	reloader.mu.Unlock()
+
+	_, rotated, err := get([]tls.Certificate{pair})
+	if err != nil {
+		t.Fatalf("request after rotation: %v", err)
+	}
+	if rotated.SerialNumber.Cmp(served.SerialNumber) == 0 || rotated.SerialNumber.Int64() != 4 {
+		t.Fatalf("served certificate serial = %v, want the rotated one", rotated.SerialNumber)
+	}
+}
+
+func TestValidateTLSConfig(t *testing.T) {
+	cases := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{"key missing", Config{Transport: "sse", TLSCert: "a.crt", TLSClientAuth: clientAuthRequire}, "must be set together"},
+		{"stdio", Config{Transport: "stdio", TLSCert: "a.crt", TLSKey: "a.key", TLSClientAuth: clientAuthRequire}, "stdio"},
+		{"client CA without TLS", Config{Transport: "sse", TLSClientCA: "ca.crt"}, "requires tls-cert"},
+		{"client auth", Config{Transport: "sse", TLSCert: "a.crt", TLSKey: "a.key", TLSClientAuth: "maybe"}, "invalid tls-client-auth"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := validateTLSConfig(&tc.cfg); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("validateTLSConfig error = %v, want it to contain %q", err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+// Package audit records every MCP tool invocation handled by the server.
+// Entries are kept in a bounded in-memory buffer for querying and are fanned
+// out asynchronously to one or more sinks (JSONL file, NATS subject or
+// JetStream stream).
+package audit
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sinadarbouy/mcp-nats/internal/logger"
+)
+
+// Outcomes recorded for a tool invocation.
+const (
+	OutcomeSuccess   = "success"
+	OutcomeError     = "error"
+	OutcomeToolError = "tool_error"
+)
+
+const (
+	// DefaultBufferSize is the number of recent entries kept in memory.
+	DefaultBufferSize = 1000
+
+	redactedValue  = "[REDACTED]"
+	maxValueLength = 512
+	queueSize      = 256
+)
+
+// Entry is a single audit record.
+type Entry struct {
+	Time       time.Time      `json:"time"`
+	Session    string         `json:"session,omitempty"`
+	Identity   string         `json:"identity,omitempty"`
+	Tool       string         `json:"tool"`
+	Arguments  map[string]any `json:"arguments,omitempty"`
+	Account    string         `json:"account,omitempty"`
+	Outcome    string         `json:"outcome"`
+	Error      string         `json:"error,omitempty"`
+	DurationMS float64        `json:"duration_ms"`
+}
+
+// Sink receives audit entries.
+type Sink interface {
+	Write(entry Entry) error
+	Close() error
+}
+
+// Filter narrows the entries returned by Recorder.Recent. Empty fields match
+// everything.
+type Filter struct {
+	Tool     string
+	Session  string
+	Identity string
+	Account  string
+	Outcome  string
+}
+
+func (f Filter) matches(e Entry) bool {
+	return (f.Tool == "" || f.Tool == e.Tool) &&
+		(f.Session == "" || f.Session == e.Session) &&
+		(f.Identity == "" || f.Identity == e.Identity) &&
+		(f.Account == "" || f.Account == e.Account) &&
+		(f.Outcome == "" || f.Outcome == e.Outcome)
+}
+
+// Recorder keeps recent entries and forwards them to the configured sinks.
+type Recorder struct {
+	mu     sync.RWMutex
+	recent []Entry
+	next   int
+	full   bool
+
+	sinks []Sink
+	queue chan Entry
+	done  chan struct{}
+	once  sync.Once
+}
+
+// NewRecorder creates a Recorder keeping up to bufferSize recent entries and
+// writing to sinks in the background.
+func NewRecorder(bufferSize int, sinks ...Sink) *Recorder {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	r := &Recorder{
+		recent: make([]Entry, bufferSize),
+		sinks:  sinks,
+		queue:  make(chan Entry, queueSize),
+		done:   make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+// Record stores the entry and queues it for the sinks. Arguments are redacted
+// before the entry is stored. Record never blocks on sink I/O; when the queue
+// is full the entry is still kept in memory but dropped for the sinks.
+func (r *Recorder) Record(e Entry) {
+	e.Arguments = Redact(e.Arguments)
+
+	r.mu.Lock()
+	r.recent[r.next] = e
+	r.next = (r.next + 1) % len(r.recent)
+	if r.next == 0 {
+		r.full = true
+	}
+	r.mu.Unlock()
+
+	select {
+	case r.queue <- e:
+	default:
+		logger.Warn("Audit queue full; entry not written to sinks", "tool", e.Tool)
+	}
+}
+
+// Recent returns up to limit entries matching filter, newest first.
+func (r *Recorder) Recent(limit int, filter Filter) []Entry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	size := r.next
+	if r.full {
+		size = len(r.recent)
+	}
+
+	entries := make([]Entry, 0, min(limit, size))
+	for i := 0; i < size && len(entries) < limit; i++ {
+		idx := (r.next - 1 - i + len(r.recent)) % len(r.recent)
+		if e := r.recent[idx]; filter.matches(e) {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// Close flushes queued entries and closes all sinks.
+func (r *Recorder) Close() {
+	r.once.Do(func() {
+		close(r.queue)
+		<-r.done
+		for _, sink := range r.sinks {
+			if err := sink.Close(); err != nil {
+				logger.Error("Failed to close audit sink", "error", err)
+			}
+		}
+	})
+}
+
+func (r *Recorder) run() {
+	defer close(r.done)
+	for e := range r.queue {
+		for _, sink := range r.sinks {
+			if err := sink.Write(e); err != nil {
+				logger.Error("Failed to write audit entry", "error", err, "tool", e.Tool)
+			}
+		}
+	}
+}
+
+// sensitiveKeys lists argument and header names whose values are never
+// recorded.
+var sensitiveKeys = []string{
+	"password",
+	"passwd",
+	"secret",
+	"token",
+	"creds",
+	"credential",
+	"seed",
+	"nkey",
+	"authorization",
+}
+
+func isSensitive(name string) bool {
+	name = strings.ToLower(name)
+	for _, key := range sensitiveKeys {
+		if strings.Contains(name, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact returns a copy of args with secrets removed and long values
+// truncated. Header-style strings ("Name: value") with a sensitive name are
+// redacted as well.
+func Redact(args map[string]any) map[string]any {
+	if args == nil {
+		return nil
+	}
+	out := make(map[string]any, len(args))
+	for k, v := range args {
+		if isSensitive(k) {
+			out[k] = redactedValue
+			continue
+		}
+		out[k] = redactValue(v)
+	}
+	return out
+}
+
+func redactValue(v any) any {
+	switch val := v.(type) {
+	case string:
+		if name, _, ok := strings.Cut(val, ":"); ok && isSensitive(name) {
+			return name + ":" + redactedValue
+		}
+		if len(val) > maxValueLength {
+			return val[:maxValueLength] + "...(truncated)"
+		}
+		return val
+	case []any:
+		items := make([]any, len(val))
+		for i, item := range val {
+			items[i] = redactValue(item)
+		}
+		return items
+	case map[string]any:
+		return Redact(val)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,95 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	args := map[string]any{
+		"account_name": "A",
+		"password":     "hunter2",
+		"header":       []any{"Authorization: Bearer abc", "X-Trace: 1"},
+		"body":         strings.Repeat("x", maxValueLength+10),
+	}
+
+	got := Redact(args)
+
+	if got["account_name"] != "A" {
+		t.Errorf("account_name = %v, want A", got["account_name"])
+	}
+	if got["password"] != redactedValue {
+		t.Errorf("password = %v, want redacted", got["password"])
+	}
+	headers := got["header"].([]any)
+	if headers[0] != "Authorization:"+redactedValue {
+		t.Errorf("header[0] = %v, want redacted authorization", headers[0])
+	}
+	if headers[1] != "X-Trace: 1" {
+		t.Errorf("header[1] = %v, want unchanged", headers[1])
+	}
+	if body := got["body"].(string); !strings.HasSuffix(body, "(truncated)") {
+		t.Errorf("body was not truncated: %d bytes", len(body))
+	}
+	if args["password"] != "hunter2" {
+		t.Error("Redact modified its input")
+	}
+}
+
+func TestRecorderRecentWrapsAndFilters(t *testing.T) {
+	r := NewRecorder(3)
+	defer r.Close()
+
+	for _, tool := range []string{"a", "b", "a", "c"} {
+		r.Record(Entry{Tool: tool, Outcome: OutcomeSuccess})
+	}
+
+	all := r.Recent(10, Filter{})
+	if len(all) != 3 {
+		t.Fatalf("Recent returned %d entries, want 3", len(all))
+	}
+	if all[0].Tool != "c" || all[2].Tool != "b" {
+		t.Fatalf("Recent order = %v, want newest first", all)
+	}
+
+	onlyA := r.Recent(10, Filter{Tool: "a"})
+	if len(onlyA) != 1 {
+		t.Fatalf("Recent(tool=a) returned %d entries, want 1", len(onlyA))
+	}
+}
+
+func TestFileSinkWritesJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	sink, err := NewFileSink(path)
+	if err != nil {
+		t.Fatalf("NewFileSink: %v", err)
+	}
+
+	r := NewRecorder(10, sink)
+	r.Record(Entry{Tool: "stream_info", Outcome: OutcomeSuccess})
+	r.Record(Entry{Tool: "publish", Outcome: OutcomeError, Error: "boom"})
+	r.Close()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open audit file: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var tools []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", scanner.Text(), err)
+		}
+		tools = append(tools, e.Tool)
+	}
+	if len(tools) != 2 || tools[0] != "stream_info" || tools[1] != "publish" {
+		t.Fatalf("audit file tools = %v, want [stream_info publish]", tools)
+	}
+}
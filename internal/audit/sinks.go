@@ -0,0 +1,111 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const publishTimeout = 5 * time.Second
+
+// FileSink appends entries as JSON lines to a local file.
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+// NewFileSink opens (or creates) path for appending.
+func NewFileSink(path string) (*FileSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit file %s: %w", path, err)
+	}
+	return &FileSink{file: f, enc: json.NewEncoder(f)}, nil
+}
+
+// Write implements Sink.
+func (s *FileSink) Write(entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enc.Encode(entry)
+}
+
+// Close implements Sink.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// NATSSink publishes entries as JSON to a NATS subject. When a stream name is
+// configured, entries are published through JetStream and acknowledged.
+type NATSSink struct {
+	nc      *nats.Conn
+	js      jetstream.JetStream
+	subject string
+}
+
+// NewNATSSink creates a sink publishing to subject over nc. If stream is not
+// empty, the stream is created to capture subject when it does not exist yet
+// and publishes wait for a JetStream acknowledgement. The sink takes ownership
+// of nc.
+func NewNATSSink(nc *nats.Conn, subject, stream string) (*NATSSink, error) {
+	if subject == "" {
+		return nil, fmt.Errorf("audit subject cannot be empty")
+	}
+	s := &NATSSink{nc: nc, subject: subject}
+	if stream == "" {
+		return s, nil
+	}
+
+	js, err := jetstream.New(nc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JetStream context: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+	if _, err := js.Stream(ctx, stream); err != nil {
+		if !errors.Is(err, jetstream.ErrStreamNotFound) {
+			return nil, fmt.Errorf("failed to look up audit stream %s: %w", stream, err)
+		}
+		if _, err := js.CreateStream(ctx, jetstream.StreamConfig{
+			Name:        stream,
+			Description: "mcp-nats tool invocation audit log",
+			Subjects:    []string{subject},
+		}); err != nil {
+			return nil, fmt.Errorf("failed to create audit stream %s: %w", stream, err)
+		}
+	}
+	s.js = js
+	return s, nil
+}
+
+// Write implements Sink.
+func (s *NATSSink) Write(entry Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if s.js == nil {
+		return s.nc.Publish(s.subject, data)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+	_, err = s.js.Publish(ctx, s.subject, data)
+	return err
+}
+
+// Close implements Sink.
+func (s *NATSSink) Close() error {
+	return s.nc.Drain()
+}
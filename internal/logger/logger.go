@@ -25,12 +25,17 @@ type Config struct {
 
 var defaultLogger *slog.Logger
 
+// level is shared by the handlers created by Initialize, so SetLevel can
+// change it at runtime.
+var level slog.LevelVar
+
 // Initialize sets up the global logger with the given configuration
 func Initialize(cfg Config) {
 	var handler slog.Handler
 
+	level.Set(cfg.Level)
 	opts := &slog.HandlerOptions{
-		Level:     cfg.Level,
+		Level:     &level,
 		AddSource: true,
 	}
 
@@ -44,6 +49,11 @@ func Initialize(cfg Config) {
 	slog.SetDefault(defaultLogger)
 }
 
+// SetLevel changes the level of the global logger.
+func SetLevel(l Level) {
+	level.Set(l)
+}
+
 // GetLevel converts a string level to slog.Level
 func GetLevel(level string) Level {
 	switch strings.ToLower(level) {
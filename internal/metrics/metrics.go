@@ -0,0 +1,128 @@
+// Package metrics exposes Prometheus metrics for the MCP server: tool calls,
+// NATS CLI command durations, active MCP sessions, executor cache size and
+// readiness probe results.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "mcp_nats"
+
+var (
+	registry = prometheus.NewRegistry()
+
+	toolCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tool_calls_total",
+		Help:      "Total number of MCP tool calls by tool, account and outcome.",
+	}, []string{"tool", "account", "outcome"})
+
+	toolErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tool_errors_total",
+		Help:      "Total number of MCP tool calls that failed, by tool and account.",
+	}, []string{"tool", "account"})
+
+	toolDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "tool_call_duration_seconds",
+		Help:      "Duration of MCP tool calls by tool and account.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"tool", "account"})
+
+	natsCommandDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "nats_command_duration_seconds",
+		Help:      "Duration of NATS CLI commands by command, account and outcome.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"command", "account", "outcome"})
+
+	activeSessions = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "active_sessions",
+		Help:      "Number of currently registered MCP sessions.",
+	})
+
+	executorCacheSize = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "executor_cache_size",
+		Help:      "Number of cached NATS executors.",
+	})
+
+	readinessChecks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "readiness_checks_total",
+		Help:      "Total number of readiness probe checks by result.",
+	}, []string{"result"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		toolCalls,
+		toolErrors,
+		toolDuration,
+		natsCommandDuration,
+		activeSessions,
+		executorCacheSize,
+		readinessChecks,
+	)
+}
+
+// Handler returns the HTTP handler serving the metrics in the Prometheus
+// exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
+
+// ObserveToolCall records a completed tool call. outcome is "success" for
+// successful calls; any other value also counts as an error.
+func ObserveToolCall(tool, account, outcome string, d time.Duration) {
+	toolCalls.WithLabelValues(tool, account, outcome).Inc()
+	toolDuration.WithLabelValues(tool, account).Observe(d.Seconds())
+	if outcome != "success" {
+		toolErrors.WithLabelValues(tool, account).Inc()
+	}
+}
+
+// ObserveNATSCommand records the duration of a NATS CLI invocation. command
+// must be a bounded subcommand name such as "stream info", never raw
+// arguments.
+func ObserveNATSCommand(command, account string, err error, d time.Duration) {
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+	natsCommandDuration.WithLabelValues(command, account, outcome).Observe(d.Seconds())
+}
+
+// SessionOpened increments the active session gauge.
+func SessionOpened() {
+	activeSessions.Inc()
+}
+
+// SessionClosed decrements the active session gauge.
+func SessionClosed() {
+	activeSessions.Dec()
+}
+
+// SetExecutorCacheSize records the number of cached executors.
+func SetExecutorCacheSize(n int) {
+	executorCacheSize.Set(float64(n))
+}
+
+// ObserveReadiness records the result of a readiness probe.
+func ObserveReadiness(ready bool) {
+	result := "ready"
+	if !ready {
+		result = "not_ready"
+	}
+	readinessChecks.WithLabelValues(result).Inc()
+}
@@ -0,0 +1,220 @@
+// Package ratelimit implements token-bucket limits for MCP tool calls. Calls
+// and published messages are limited separately, per MCP session and per
+// inbound identity, with optional overrides for individual tools.
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Limit kinds reported in a Decision.
+const (
+	KindCalls    = "calls"
+	KindMessages = "messages"
+)
+
+const (
+	// idleTTL is how long an unused bucket is kept before being dropped.
+	idleTTL = 10 * time.Minute
+	// pruneInterval is the minimum time between idle bucket sweeps.
+	pruneInterval = time.Minute
+	// allTools is the bucket key shared by tools without an override.
+	allTools = "*"
+)
+
+// Limit is a pair of token buckets. A zero rate disables that bucket.
+type Limit struct {
+	CallsPerSecond    float64
+	CallBurst         int
+	MessagesPerSecond float64
+	MessageBurst      int
+}
+
+// Config holds the default limit and per-tool overrides. Tools with an
+// override get their own buckets; all other tools share the default buckets.
+type Config struct {
+	Default Limit
+	Tools   map[string]Limit
+}
+
+// Enabled reports whether any limit is configured.
+func (c Config) Enabled() bool {
+	if c.Default.CallsPerSecond > 0 || c.Default.MessagesPerSecond > 0 {
+		return true
+	}
+	for _, l := range c.Tools {
+		if l.CallsPerSecond > 0 || l.MessagesPerSecond > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Key identifies who a call is charged to, e.g. {"session", "<id>"}.
+type Key struct {
+	Scope string
+	ID    string
+}
+
+// Decision is the result of Limiter.Allow.
+type Decision struct {
+	Allowed    bool
+	Scope      string
+	Kind       string
+	Limit      float64
+	RetryAfter time.Duration
+	// Reason is set when the request can never be satisfied, e.g. because it
+	// asks for more messages than the bucket holds.
+	Reason string
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+// Limiter tracks token buckets for all keys.
+type Limiter struct {
+	cfg Config
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastPrune time.Time
+	now       func() time.Time
+}
+
+// New creates a Limiter for cfg.
+func New(cfg Config) *Limiter {
+	return &Limiter{
+		cfg:     cfg,
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Allow charges one call and messages published messages of tool to every
+// key. Either all buckets are charged or none is.
+func (l *Limiter) Allow(tool string, keys []Key, messages int) Decision {
+	limit, toolKey := l.cfg.Default, allTools
+	if override, ok := l.cfg.Tools[tool]; ok {
+		limit, toolKey = override, tool
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.prune(now)
+
+	var reservations []*rate.Reservation
+	cancelAll := func() {
+		for _, r := range reservations {
+			r.CancelAt(now)
+		}
+	}
+
+	for _, key := range keys {
+		checks := []struct {
+			kind  string
+			rate  float64
+			burst int
+			n     int
+		}{
+			{KindCalls, limit.CallsPerSecond, limit.CallBurst, 1},
+			{KindMessages, limit.MessagesPerSecond, limit.MessageBurst, messages},
+		}
+		for _, c := range checks {
+			if c.rate <= 0 || c.n <= 0 {
+				continue
+			}
+			b := l.bucket(strings.Join([]string{key.Scope, key.ID, toolKey, c.kind}, "\x00"), c.rate, c.burst, now)
+			r := b.limiter.ReserveN(now, c.n)
+			if !r.OK() {
+				cancelAll()
+				return Decision{
+					Scope:  key.Scope,
+					Kind:   c.kind,
+					Limit:  c.rate,
+					Reason: fmt.Sprintf("request needs %d %s but at most %d are allowed at once", c.n, c.kind, b.limiter.Burst()),
+				}
+			}
+			if delay := r.DelayFrom(now); delay > 0 {
+				r.CancelAt(now)
+				cancelAll()
+				return Decision{
+					Scope:      key.Scope,
+					Kind:       c.kind,
+					Limit:      c.rate,
+					RetryAfter: delay,
+				}
+			}
+			reservations = append(reservations, r)
+		}
+	}
+	return Decision{Allowed: true}
+}
+
+func (l *Limiter) bucket(key string, perSecond float64, burst int, now time.Time) *bucket {
+	b, ok := l.buckets[key]
+	if !ok {
+		if burst <= 0 {
+			burst = int(math.Max(1, math.Ceil(perSecond)))
+		}
+		b = &bucket{limiter: rate.NewLimiter(rate.Limit(perSecond), burst)}
+		l.buckets[key] = b
+	}
+	b.lastUsed = now
+	return b
+}
+
+func (l *Limiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < pruneInterval {
+		return
+	}
+	l.lastPrune = now
+	for key, b := range l.buckets {
+		if now.Sub(b.lastUsed) > idleTTL {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// ParseToolLimits parses per-tool call limits of the form
+// "tool=rate[:burst],tool2=rate[:burst]". Message limits are inherited from
+// def.
+func ParseToolLimits(spec string, def Limit) (map[string]Limit, error) {
+	limits := make(map[string]Limit)
+	if strings.TrimSpace(spec) == "" {
+		return limits, nil
+	}
+	for _, part := range strings.Split(spec, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid tool rate limit %q: want tool=rate[:burst]", part)
+		}
+		rateStr, burstStr, hasBurst := strings.Cut(value, ":")
+		perSecond, err := strconv.ParseFloat(rateStr, 64)
+		if err != nil || perSecond < 0 {
+			return nil, fmt.Errorf("invalid rate in tool rate limit %q", part)
+		}
+		limit := def
+		limit.CallsPerSecond = perSecond
+		limit.CallBurst = 0
+		if hasBurst {
+			burst, err := strconv.Atoi(burstStr)
+			if err != nil || burst < 0 {
+				return nil, fmt.Errorf("invalid burst in tool rate limit %q", part)
+			}
+			limit.CallBurst = burst
+		}
+		limits[name] = limit
+	}
+	return limits, nil
+}
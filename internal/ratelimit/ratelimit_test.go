@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestLimiter(cfg Config) (*Limiter, *time.Time) {
+	now := time.Unix(1700000000, 0)
+	l := New(cfg)
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+func TestAllow_callBurstThenRetryAfter(t *testing.T) {
+	l, now := newTestLimiter(Config{Default: Limit{CallsPerSecond: 1, CallBurst: 2}})
+	keys := []Key{{Scope: "session", ID: "s1"}}
+
+	for i := 0; i < 2; i++ {
+		if d := l.Allow("stream_report", keys, 0); !d.Allowed {
+			t.Fatalf("call %d denied: %+v", i, d)
+		}
+	}
+
+	d := l.Allow("stream_report", keys, 0)
+	if d.Allowed || d.Kind != KindCalls || d.Scope != "session" {
+		t.Fatalf("third call decision = %+v, want calls/session denial", d)
+	}
+	if d.RetryAfter <= 0 || d.RetryAfter > time.Second {
+		t.Fatalf("RetryAfter = %v, want within (0, 1s]", d.RetryAfter)
+	}
+
+	*now = now.Add(time.Second)
+	if d := l.Allow("stream_report", keys, 0); !d.Allowed {
+		t.Fatalf("call after refill denied: %+v", d)
+	}
+
+	if d := l.Allow("stream_report", []Key{{Scope: "session", ID: "s2"}}, 0); !d.Allowed {
+		t.Fatalf("other session denied: %+v", d)
+	}
+}
+
+func TestAllow_messagesOverBurstNeverAllowed(t *testing.T) {
+	l, _ := newTestLimiter(Config{Default: Limit{MessagesPerSecond: 10, MessageBurst: 50}})
+
+	d := l.Allow("publish", []Key{{Scope: "identity", ID: "10.0.0.1"}}, 100)
+	if d.Allowed || d.Kind != KindMessages || d.Reason == "" {
+		t.Fatalf("decision = %+v, want permanent messages denial", d)
+	}
+	if d := l.Allow("publish", []Key{{Scope: "identity", ID: "10.0.0.1"}}, 50); !d.Allowed {
+		t.Fatalf("publish within burst denied: %+v", d)
+	}
+}
+
+func TestAllow_deniedCallDoesNotChargeOtherKeys(t *testing.T) {
+	l, _ := newTestLimiter(Config{Default: Limit{CallsPerSecond: 1, CallBurst: 1}})
+	session := Key{Scope: "session", ID: "s1"}
+	identity := Key{Scope: "identity", ID: "i1"}
+
+	// Exhaust the identity bucket only.
+	if d := l.Allow("kv_get", []Key{identity}, 0); !d.Allowed {
+		t.Fatalf("first identity call denied: %+v", d)
+	}
+	if d := l.Allow("kv_get", []Key{session, identity}, 0); d.Allowed || d.Scope != "identity" {
+		t.Fatalf("decision = %+v, want identity denial", d)
+	}
+	// The session bucket must not have been charged by the denied call.
+	if d := l.Allow("kv_get", []Key{session}, 0); !d.Allowed {
+		t.Fatalf("session call denied after rollback: %+v", d)
+	}
+}
+
+func TestParseToolLimits(t *testing.T) {
+	def := Limit{CallsPerSecond: 5, MessagesPerSecond: 100}
+	limits, err := ParseToolLimits("publish=1:5, stream_report=0.2", def)
+	if err != nil {
+		t.Fatalf("ParseToolLimits: %v", err)
+	}
+	if got := limits["publish"]; got.CallsPerSecond != 1 || got.CallBurst != 5 || got.MessagesPerSecond != 100 {
+		t.Fatalf("publish limit = %+v", got)
+	}
+	if got := limits["stream_report"]; got.CallsPerSecond != 0.2 || got.CallBurst != 0 {
+		t.Fatalf("stream_report limit = %+v", got)
+	}
+
+	for _, bad := range []string{"publish", "publish=x", "publish=1:-2", "=1"} {
+		if _, err := ParseToolLimits(bad, def); err == nil {
+			t.Errorf("ParseToolLimits(%q) succeeded, want error", bad)
+		}
+	}
+}
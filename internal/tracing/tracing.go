@@ -0,0 +1,101 @@
+// Package tracing configures OpenTelemetry tracing for the MCP server. Spans
+// are exported over OTLP/HTTP when the standard OTEL_EXPORTER_OTLP_* variables
+// are set; otherwise tracing stays a no-op. W3C trace context is used for
+// propagation in both directions.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/sinadarbouy/mcp-nats"
+
+func init() {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+}
+
+// Enabled reports whether the environment requests span export.
+func Enabled() bool {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return false
+	}
+	switch strings.ToLower(os.Getenv("OTEL_TRACES_EXPORTER")) {
+	case "none":
+		return false
+	case "otlp":
+		return true
+	}
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" ||
+		os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// Setup installs a global tracer provider exporting over OTLP/HTTP. Endpoint,
+// headers, timeouts and TLS are read from the standard OTEL_EXPORTER_OTLP_*
+// variables; service attributes from OTEL_SERVICE_NAME and
+// OTEL_RESOURCE_ATTRIBUTES. The returned function flushes and stops the
+// provider.
+func Setup(ctx context.Context, serviceName, version string) (func(context.Context) error, error) {
+	if !Enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	// Later options take precedence, so OTEL_SERVICE_NAME and
+	// OTEL_RESOURCE_ATTRIBUTES override the built-in service attributes.
+	res, err := resource.New(ctx,
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(
+			semconv.ServiceName(serviceName),
+			semconv.ServiceVersion(version),
+		),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the tracer used for mcp-nats spans.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// HeaderArgs returns the trace context of ctx as "Name:value" header strings,
+// sorted by name, suitable for the NATS CLI --header flag.
+func HeaderArgs(ctx context.Context) []string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+
+	headers := make([]string, 0, len(carrier))
+	for _, key := range carrier.Keys() {
+		headers = append(headers, key+":"+carrier.Get(key))
+	}
+	sort.Strings(headers)
+	return headers
+}
@@ -0,0 +1,24 @@
+// Package tracingtest records spans in memory for tests. It is kept out of
+// the tracing package so the test exporter is not linked into the server.
+package tracingtest
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// SetupInMemory installs a global tracer provider that records spans in
+// memory. The returned function restores the previous provider.
+func SetupInMemory() (*tracetest.InMemoryExporter, func()) {
+	previous := otel.GetTracerProvider()
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTracerProvider(provider)
+	return exporter, func() {
+		_ = provider.Shutdown(context.Background())
+		otel.SetTracerProvider(previous)
+	}
+}
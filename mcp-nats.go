@@ -9,30 +9,36 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"net/url"
-	"os"
+	"sort"
 	"strings"
 
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/nats-io/nats.go"
 	"github.com/sinadarbouy/mcp-nats/tools/common"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
 )
 
 const (
 	defaultNatsURL = "localhost:4222"
 
-	natsURLEnvVar = "NATS_URL"
-
 	natsURLHeader = "X-Nats-URL"
 )
 
 type natsURLKey struct{}
 type natsCredsKey struct{}
 type natsAuthStrategyKey struct{}
+type inboundIdentityKey struct{}
+type clusterNameKey struct{}
+type natsURLOverrideKey struct{}
 
 // NATSAuthStrategy defines the interface for different authentication strategies
 type NATSAuthStrategy interface {
 	BuildArgs(baseURL string) []string
+	NATSOptions() []nats.Option
 	GetAccountName() string
 	Cleanup() error
 }
@@ -45,12 +51,6 @@ func urlFromHeaders(req *http.Request) (string, string) {
 	return req.Header.Get(natsURLHeader), ""
 }
 
-func urlFromEnv() (string, string) {
-	slog.Debug("Processing NATS environment variables")
-	u := strings.TrimRight(os.Getenv(natsURLEnvVar), "/")
-	return u, ""
-}
-
 // WithNatsURL adds the Nats URL to the context.
 func WithNatsURL(ctx context.Context, url string) context.Context {
 	return context.WithValue(ctx, natsURLKey{}, url)
@@ -66,6 +66,37 @@ func WithNatsAuthConfig(ctx context.Context, authStrategy common.NATSAuthStrateg
 	return context.WithValue(ctx, natsAuthStrategyKey{}, authStrategy)
 }
 
+// WithClusterName records in the context which configured cluster a tool
+// call targets.
+func WithClusterName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, clusterNameKey{}, name)
+}
+
+// GetClusterNameFromContext returns the cluster recorded by WithClusterName,
+// or an empty string for the default cluster.
+func GetClusterNameFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	name, _ := ctx.Value(clusterNameKey{}).(string)
+	return name
+}
+
+// GetNatsURLOverrideFromContext returns the NATS URL the client chose with
+// the X-Nats-URL header, or an empty string when it did not.
+func GetNatsURLOverrideFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	u, _ := ctx.Value(natsURLOverrideKey{}).(string)
+	return u
+}
+
+// WithInboundIdentity adds the identity of the calling MCP client to the context.
+func WithInboundIdentity(ctx context.Context, identity string) context.Context {
+	return context.WithValue(ctx, inboundIdentityKey{}, identity)
+}
+
 // natsURLFromContext extracts the nats url from the context.
 // This can be used by tools to extract the url regardless of the
 // transport being used by the server.
@@ -109,89 +140,129 @@ func natsAuthStrategyFromContext(ctx context.Context) (common.NATSAuthStrategy,
 
 // ExtractNatsInfoFromHeaders is a SSEContextFunc that extracts nats configuration
 // from request headers and injects a configured client into the context.
+// Settings not given by headers are read from the environment.
 var ExtractNatsInfoFromHeaders server.SSEContextFunc = func(ctx context.Context, req *http.Request) context.Context {
-	if ctx == nil {
-		ctx = context.Background()
-	}
+	return ConnectionSSEContextFunc(common.ConnectionFromEnv())(ctx, req)
+}
+
+// ExtractNatsInfoFromEnv is a StdioContextFunc that extracts NATS configuration
+// from environment variables and injects a configured client into the context.
+var ExtractNatsInfoFromEnv server.StdioContextFunc = func(ctx context.Context) context.Context {
+	return ConnectionStdioContextFunc(common.ConnectionFromEnv())(ctx)
+}
+
+// ConnectionSSEContextFunc returns a SSEContextFunc that injects the URL and
+// authentication of conn into the context. The X-Nats-URL header overrides
+// the URL of conn.
+func ConnectionSSEContextFunc(conn common.Connection) server.SSEContextFunc {
+	return func(ctx context.Context, req *http.Request) context.Context {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		header, _ := urlFromHeaders(req)
 
-	u, _ := urlFromHeaders(req)
-	uEnv, _ := urlFromEnv()
+		// Determine final URL with fallbacks
+		u := determineNatsURL(header, strings.TrimRight(conn.URL, "/"))
 
-	// Determine final URL with fallbacks
-	u = determineNatsURL(u, uEnv)
+		// Validate URL
+		if err := validateNatsURL(u); err != nil {
+			slog.Error("Invalid NATS URL", "url", u, "error", err)
+			// Use default URL as fallback
+			u = defaultNatsURL
+		}
+		if header != "" && u == header {
+			ctx = context.WithValue(ctx, natsURLOverrideKey{}, u)
+		}
 
-	// Validate URL
-	if err := validateNatsURL(u); err != nil {
-		slog.Error("Invalid NATS URL", "url", u, "error", err)
-		// Use default URL as fallback
-		u = defaultNatsURL
+		return WithConnection(ctx, u, conn)
 	}
+}
 
-	// Check for no-authentication flag
-	noAuth := os.Getenv("NATS_NO_AUTHENTICATION") == "true"
+// ConnectionStdioContextFunc returns a StdioContextFunc that injects the URL
+// and authentication of conn into the context.
+func ConnectionStdioContextFunc(conn common.Connection) server.StdioContextFunc {
+	return func(ctx context.Context) context.Context {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		u := determineNatsURL(strings.TrimRight(conn.URL, "/"), "")
+		if err := validateNatsURL(u); err != nil {
+			slog.Error("Invalid NATS URL", "url", u, "error", err)
+			u = defaultNatsURL
+		}
 
-	// Check for user/password authentication
-	user, password := common.GetUserPassFromEnv()
+		return WithConnection(ctx, u, conn)
+	}
+}
 
-	if noAuth {
-		// Anonymous connection
-		authStrategy := common.NewAnonymousAuthStrategy()
-		return WithNatsAuthConfig(WithNatsURL(ctx, u), authStrategy)
-	} else if user != "" && password != "" {
-		// User/password authentication
-		authStrategy := common.NewUserPassAuthStrategy(user, password)
-		return WithNatsAuthConfig(WithNatsURL(ctx, u), authStrategy)
-	} else {
-		// Credentials-based authentication (existing behavior)
-		creds, err := common.GetCredsFromEnv()
-		if err != nil {
-			slog.Error("Failed to get NATS credentials", "error", err)
+// WithConnection stores the NATS URL and the authentication of conn in the
+// context: an authentication strategy for anonymous and user/password
+// authentication, or the per-account credentials otherwise. Authentication
+// stored earlier in the context is replaced. An empty u selects the default
+// URL.
+func WithConnection(ctx context.Context, u string, conn common.Connection) context.Context {
+	ctx = WithNatsURL(ctx, determineNatsURL(strings.TrimRight(u, "/"), ""))
+	switch conn.AuthMode() {
+	case common.AuthAnonymous:
+		ctx = WithNatsCreds(ctx, nil)
+		return WithNatsAuthConfig(ctx, common.NewAnonymousAuthStrategy())
+	case common.AuthUserPass:
+		ctx = WithNatsCreds(ctx, nil)
+		return WithNatsAuthConfig(ctx, common.NewUserPassAuthStrategy(conn.User, conn.Password))
+	default:
+		creds := conn.Creds
+		if creds == nil {
 			creds = make(map[string]common.NATSCreds)
 		}
-		return WithNatsCreds(WithNatsURL(ctx, u), creds)
+		ctx = WithNatsAuthConfig(ctx, nil)
+		return WithNatsCreds(ctx, creds)
 	}
 }
 
-// ExtractNatsInfoFromEnv is a StdioContextFunc that extracts NATS configuration
-// from environment variables and injects a configured client into the context.
-var ExtractNatsInfoFromEnv server.StdioContextFunc = func(ctx context.Context) context.Context {
+// ExtractInboundIdentity is a SSEContextFunc that records who is calling the
+// server. Clients that presented a verified TLS client certificate are
+// identified by its subject, plain HTTP clients by their remote address.
+var ExtractInboundIdentity server.SSEContextFunc = func(ctx context.Context, req *http.Request) context.Context {
 	if ctx == nil {
 		ctx = context.Background()
 	}
-
-	u, _ := urlFromEnv()
-	if u == "" {
-		u = defaultNatsURL
+	if req == nil {
+		return ctx
 	}
 
-	if err := validateNatsURL(u); err != nil {
-		slog.Error("Invalid NATS URL from environment", "url", u, "error", err)
-		u = defaultNatsURL
+	if req.TLS != nil && len(req.TLS.VerifiedChains) > 0 && len(req.TLS.VerifiedChains[0]) > 0 {
+		return WithInboundIdentity(ctx, req.TLS.VerifiedChains[0][0].Subject.String())
 	}
 
-	// Check for no-authentication flag
-	noAuth := os.Getenv("NATS_NO_AUTHENTICATION") == "true"
+	identity := req.RemoteAddr
+	if host, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+		identity = host
+	}
+	return WithInboundIdentity(ctx, identity)
+}
 
-	// Check for user/password authentication
-	user, password := common.GetUserPassFromEnv()
+// ExtractTraceContext is a SSEContextFunc that continues the W3C trace context
+// (traceparent/tracestate headers) sent by the client, so tool call spans join
+// the caller's trace.
+var ExtractTraceContext server.SSEContextFunc = func(ctx context.Context, req *http.Request) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if req == nil {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(req.Header))
+}
 
-	if noAuth {
-		// Anonymous connection
-		authStrategy := common.NewAnonymousAuthStrategy()
-		return WithNatsAuthConfig(WithNatsURL(ctx, u), authStrategy)
-	} else if user != "" && password != "" {
-		// User/password authentication
-		authStrategy := common.NewUserPassAuthStrategy(user, password)
-		return WithNatsAuthConfig(WithNatsURL(ctx, u), authStrategy)
-	} else {
-		// Credentials-based authentication (existing behavior)
-		creds, err := common.GetCredsFromEnv()
-		if err != nil {
-			slog.Error("Failed to get NATS credentials from environment", "error", err)
-			creds = make(map[string]common.NATSCreds)
-		}
-		return WithNatsCreds(WithNatsURL(ctx, u), creds)
+// ExtractStdioIdentity is a StdioContextFunc that marks calls as coming from
+// the local process attached to stdin/stdout.
+var ExtractStdioIdentity server.StdioContextFunc = func(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
 	}
+	return WithInboundIdentity(ctx, "stdio")
 }
 
 // ComposeSSEContextFuncs composes multiple SSEContextFuncs into a single function.
@@ -207,10 +278,22 @@ func ComposeSSEContextFuncs(funcs ...server.SSEContextFunc) server.SSEContextFun
 
 // ComposedSSEContextFunc returns a composed SSEContextFunc that includes all
 // predefined context functions for SSE handling. Currently, this includes
-// ExtractNatsInfoFromHeaders.
+// ExtractNatsInfoFromHeaders, ExtractInboundIdentity and ExtractTraceContext.
 func ComposedSSEContextFunc() server.SSEContextFunc {
 	return ComposeSSEContextFuncs(
 		ExtractNatsInfoFromHeaders,
+		ExtractInboundIdentity,
+		ExtractTraceContext,
+	)
+}
+
+// NewComposedSSEContextFunc is like ComposedSSEContextFunc, but takes the NATS
+// connection settings from conn instead of the environment.
+func NewComposedSSEContextFunc(conn common.Connection) server.SSEContextFunc {
+	return ComposeSSEContextFuncs(
+		ConnectionSSEContextFunc(conn),
+		ExtractInboundIdentity,
+		ExtractTraceContext,
 	)
 }
 
@@ -227,13 +310,38 @@ func ComposeStdioContextFuncs(funcs ...server.StdioContextFunc) server.StdioCont
 
 // ComposedStdioContextFunc returns a composed StdioContextFunc that includes all
 // predefined context functions for stdio handling. Currently, this includes
-// ExtractNatsInfoFromEnv.
+// ExtractNatsInfoFromEnv and ExtractStdioIdentity.
 func ComposedStdioContextFunc() server.StdioContextFunc {
 	return ComposeStdioContextFuncs(
 		ExtractNatsInfoFromEnv,
+		ExtractStdioIdentity,
+	)
+}
+
+// NewComposedStdioContextFunc is like ComposedStdioContextFunc, but takes the
+// NATS connection settings from conn instead of the environment.
+func NewComposedStdioContextFunc(conn common.Connection) server.StdioContextFunc {
+	return ComposeStdioContextFuncs(
+		ConnectionStdioContextFunc(conn),
+		ExtractStdioIdentity,
 	)
 }
 
+// DetermineAccountName determines the account a tool call runs as from the
+// authentication in the context: the account_name argument for
+// credentials-based authentication, or the account implied by the
+// authentication strategy otherwise.
+func DetermineAccountName(ctx context.Context, requestArgs map[string]interface{}) (string, error) {
+	if strategy, err := natsAuthStrategyFromContext(ctx); err == nil {
+		return strategy.GetAccountName(), nil
+	}
+	accountName, ok := requestArgs["account_name"].(string)
+	if !ok {
+		return "", fmt.Errorf("missing account_name")
+	}
+	return accountName, nil
+}
+
 // GetCredsFromContext retrieves NATS credentials for a specific account from the context.
 // It returns an error if:
 // - The NATS credentials are not found in the context
@@ -252,6 +360,21 @@ func GetCredsFromContext(ctx context.Context, accountName string) (common.NATSCr
 	return common.NATSCreds{}, fmt.Errorf("no credentials found for account %s", accountName)
 }
 
+// GetAccountNamesFromContext returns the sorted names of the accounts that
+// have credentials in the context.
+func GetAccountNamesFromContext(ctx context.Context) []string {
+	creds, err := natsCredsFromContext(ctx)
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(creds))
+	for name := range creds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // GetAuthStrategyFromContext retrieves NATS authentication strategy from the context.
 // It returns an error if:
 // - The NATS authentication strategy is not found in the context
@@ -275,6 +398,16 @@ func GetNatsURLFromContext(ctx context.Context) (string, error) {
 	return url, nil
 }
 
+// GetInboundIdentityFromContext returns the identity of the calling MCP client,
+// or an empty string when the transport did not record one.
+func GetInboundIdentityFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	identity, _ := ctx.Value(inboundIdentityKey{}).(string)
+	return identity
+}
+
 // Helper functions
 
 // determineNatsURL returns the appropriate NATS URL based on the provided values
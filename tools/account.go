@@ -207,7 +207,7 @@ func (a *AccountTools) accountInfoHandler() server.ToolHandlerFunc {
 
 		args := []string{"account", "info"}
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -244,7 +244,7 @@ func (a *AccountTools) accountReportConnectionsHandler() server.ToolHandlerFunc
 			args = append(args, fmt.Sprintf("--subject=%s", subject))
 		}
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -266,7 +266,7 @@ func (a *AccountTools) accountReportStatisticsHandler() server.ToolHandlerFunc {
 
 		args := []string{"account", "report", "statistics"}
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -320,7 +320,7 @@ func (a *AccountTools) accountBackupHandler() server.ToolHandlerFunc {
 		// Add target directory as the final argument
 		args = append(args, target)
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -364,7 +364,7 @@ func (a *AccountTools) accountRestoreHandler() server.ToolHandlerFunc {
 		// Add directory as the final argument
 		args = append(args, directory)
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -405,7 +405,7 @@ func (a *AccountTools) accountTLSHandler() server.ToolHandlerFunc {
 			}
 		}
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -62,7 +62,7 @@ func (s *AccountTestSuite) SetupSuite() {
 
 	// Create NATSServerTools instance
 	natsTools := &NATSServerTools{
-		executors: make(map[string]*common.NATSExecutor),
+		executors: make(map[executorKey]*common.NATSExecutor),
 	}
 
 	// Create AccountTools instance
@@ -0,0 +1,128 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	mcpnats "github.com/sinadarbouy/mcp-nats"
+	"github.com/sinadarbouy/mcp-nats/internal/audit"
+)
+
+const defaultAuditQueryLimit = 50
+
+// AuditTools represents the tools exposing the audit log
+type AuditTools struct {
+	recorder *audit.Recorder
+}
+
+// NewAuditTools creates a new AuditTools instance
+func NewAuditTools(recorder *audit.Recorder) *AuditTools {
+	return &AuditTools{
+		recorder: recorder,
+	}
+}
+
+// GetTools implements the ToolCategory interface
+func (a *AuditTools) GetTools() []Tool {
+	return []Tool{
+		{
+			Tool: mcp.Tool{
+				Name:        "audit_query",
+				Description: "Query recent tool invocations recorded by the audit log, newest first",
+				InputSchema: mcp.ToolInputSchema{
+					Type: "object",
+					Properties: map[string]interface{}{
+						"limit": map[string]interface{}{
+							"type":        "integer",
+							"description": "Maximum number of entries to return",
+							"default":     defaultAuditQueryLimit,
+						},
+						"tool": map[string]interface{}{
+							"type":        "string",
+							"description": "Only return invocations of this tool",
+						},
+						"session": map[string]interface{}{
+							"type":        "string",
+							"description": "Only return invocations from this MCP session",
+						},
+						"identity": map[string]interface{}{
+							"type":        "string",
+							"description": "Only return invocations from this inbound identity",
+						},
+						"account": map[string]interface{}{
+							"type":        "string",
+							"description": "Only return invocations against this NATS account",
+						},
+						"outcome": map[string]interface{}{
+							"type":        "string",
+							"description": "Only return invocations with this outcome",
+							"enum":        []string{audit.OutcomeSuccess, audit.OutcomeError, audit.OutcomeToolError},
+						},
+					},
+				},
+			},
+			Handler: a.auditQueryHandler(),
+		},
+	}
+}
+
+func (a *AuditTools) auditQueryHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+
+		limit := defaultAuditQueryLimit
+		if l, ok := args["limit"].(float64); ok && l > 0 {
+			limit = int(l)
+		}
+
+		filter := audit.Filter{}
+		filter.Tool, _ = args["tool"].(string)
+		filter.Session, _ = args["session"].(string)
+		filter.Identity, _ = args["identity"].(string)
+		filter.Account, _ = args["account"].(string)
+		filter.Outcome, _ = args["outcome"].(string)
+
+		output, err := json.MarshalIndent(a.recorder.Recent(limit, filter), "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode audit entries: %w", err)
+		}
+		return mcp.NewToolResultText(string(output)), nil
+	}
+}
+
+// AuditMiddleware records every invocation of the wrapped tool with rec.
+func AuditMiddleware(rec *audit.Recorder) Middleware {
+	return func(tool mcp.Tool, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			start := time.Now()
+			result, err := next(ctx, request)
+
+			entry := audit.Entry{
+				Time:       start.UTC(),
+				Identity:   mcpnats.GetInboundIdentityFromContext(ctx),
+				Tool:       tool.Name,
+				Arguments:  request.GetArguments(),
+				Account:    requestAccountName(ctx, request),
+				Outcome:    audit.OutcomeSuccess,
+				DurationMS: float64(time.Since(start).Microseconds()) / 1000,
+			}
+			if session := server.ClientSessionFromContext(ctx); session != nil {
+				entry.Session = session.SessionID()
+			}
+			switch {
+			case err != nil:
+				entry.Outcome = audit.OutcomeError
+				entry.Error = err.Error()
+			case result != nil && result.IsError:
+				entry.Outcome = audit.OutcomeToolError
+			}
+			rec.Record(entry)
+
+			return result, err
+		}
+	}
+}
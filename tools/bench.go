@@ -0,0 +1,631 @@
+package tools
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// BenchLimits cap the load the bench tool may generate. They are set by the
+// operator; tool arguments beyond them are rejected.
+type BenchLimits struct {
+	MaxMessages    int
+	MaxMessageSize int
+	// MaxClients caps publishers and subscribers together; each client has
+	// its own connection.
+	MaxClients  int
+	MaxDuration time.Duration
+}
+
+// DefaultBenchLimits are the bench caps unless configured otherwise.
+var DefaultBenchLimits = BenchLimits{
+	MaxMessages:    100000,
+	MaxMessageSize: 64 * 1024,
+	MaxClients:     10,
+	MaxDuration:    30 * time.Second,
+}
+
+// Benchmark modes.
+const (
+	benchPubSub    = "pubsub"
+	benchRequest   = "request"
+	benchJSPublish = "js_publish"
+	benchJSPull    = "js_pull"
+	benchJSPush    = "js_push"
+)
+
+var benchModes = []string{benchPubSub, benchRequest, benchJSPublish, benchJSPull, benchJSPush}
+
+// defaultBenchMessages is the number of messages a benchmark publishes
+// unless asked otherwise.
+const defaultBenchMessages = 10000
+
+// benchStampSize is the size of the send timestamp at the start of every
+// benchmark payload.
+const benchStampSize = 8
+
+// benchConsumer names the consumer of the JetStream consumer modes.
+const benchConsumer = "BENCH"
+
+// BenchTools represents the load generation tools
+type BenchTools struct {
+	nats *NATSServerTools
+}
+
+// NewBenchTools creates a new BenchTools instance
+func NewBenchTools(nats *NATSServerTools) *BenchTools {
+	return &BenchTools{
+		nats: nats,
+	}
+}
+
+// GetTools implements the ToolCategory interface
+func (b *BenchTools) GetTools() []Tool {
+	needsAccountName := b.nats.accountNameRequired()
+	limits := b.nats.currentBenchLimits()
+
+	props := map[string]interface{}{
+		"mode": map[string]interface{}{
+			"type": "string",
+			"enum": benchModes,
+			"description": "pubsub: core publishers to fan-out subscribers; request: requesters to queue group responders; " +
+				"js_publish: synchronous JetStream publishes; js_pull and js_push: JetStream publishers to workers sharing a pull or push consumer",
+		},
+		"messages": map[string]interface{}{
+			"type":        "integer",
+			"description": fmt.Sprintf("Number of messages to publish (at most %d)", limits.MaxMessages),
+			"default":     min(defaultBenchMessages, limits.MaxMessages),
+		},
+		"size": map[string]interface{}{
+			"type":        "integer",
+			"description": fmt.Sprintf("Message size in bytes, at least %d for the send timestamp (at most %d)", benchStampSize, limits.MaxMessageSize),
+			"default":     min(128, limits.MaxMessageSize),
+		},
+		"publishers": map[string]interface{}{
+			"type":        "integer",
+			"description": "Number of concurrent publishers",
+			"default":     1,
+		},
+		"subscribers": map[string]interface{}{
+			"type":        "integer",
+			"description": fmt.Sprintf("Number of subscribers, responders or consumer workers; publishers and subscribers together are at most %d", limits.MaxClients),
+			"default":     1,
+		},
+		"max_duration": map[string]interface{}{
+			"type":        "string",
+			"description": fmt.Sprintf("Stop the benchmark after this long (at most %s)", limits.MaxDuration),
+			"default":     limits.MaxDuration.String(),
+		},
+		"storage": map[string]interface{}{
+			"type":        "string",
+			"enum":        []string{"memory", "file"},
+			"description": "Storage of the temporary stream of the JetStream modes",
+			"default":     "memory",
+		},
+	}
+	required := []string{"mode"}
+	if needsAccountName {
+		props["account_name"] = map[string]interface{}{
+			"type":        "string",
+			"description": "The NATS account to use (required for credentials-based authentication)",
+		}
+		required = append([]string{"account_name"}, required...)
+	}
+
+	return []Tool{
+		{
+			Tool: mcp.Tool{
+				Name: "bench",
+				Description: "Generates bounded load on private subjects and reports throughput and latency percentiles. " +
+					"JetStream modes create a temporary stream that is deleted afterwards",
+				InputSchema: mcp.ToolInputSchema{Type: "object", Properties: props, Required: required},
+			},
+			Handler: b.benchHandler(),
+		},
+	}
+}
+
+// benchParams are the validated arguments of a benchmark.
+type benchParams struct {
+	mode        string
+	messages    int
+	size        int
+	publishers  int
+	subscribers int
+	duration    time.Duration
+	storage     jetstream.StorageType
+}
+
+// benchParamsFromArgs reads the benchmark arguments and enforces limits.
+func benchParamsFromArgs(arguments map[string]interface{}, limits BenchLimits) (benchParams, error) {
+	p := benchParams{
+		messages:    min(defaultBenchMessages, limits.MaxMessages),
+		size:        min(128, limits.MaxMessageSize),
+		publishers:  1,
+		subscribers: 1,
+		duration:    limits.MaxDuration,
+		storage:     jetstream.MemoryStorage,
+	}
+	p.mode, _ = arguments["mode"].(string)
+	if !containsString(benchModes, p.mode) {
+		return p, fmt.Errorf("invalid mode %q, must be one of %s", p.mode, strings.Join(benchModes, ", "))
+	}
+	for _, arg := range []struct {
+		name  string
+		value *int
+		min   int
+		max   int
+	}{
+		{"messages", &p.messages, 1, limits.MaxMessages},
+		{"size", &p.size, benchStampSize, limits.MaxMessageSize},
+		{"publishers", &p.publishers, 1, limits.MaxClients},
+		{"subscribers", &p.subscribers, 0, limits.MaxClients},
+	} {
+		n, ok := arguments[arg.name].(float64)
+		if !ok {
+			continue
+		}
+		if n < float64(arg.min) || n > float64(arg.max) {
+			return p, fmt.Errorf("%s must be between %d and %d", arg.name, arg.min, arg.max)
+		}
+		*arg.value = int(n)
+	}
+	if p.mode == benchJSPublish {
+		// Publishes are measured by their acknowledgements alone.
+		p.subscribers = 0
+	} else if p.subscribers == 0 {
+		return p, fmt.Errorf("mode %s needs at least one subscriber", p.mode)
+	}
+	if p.publishers+p.subscribers > limits.MaxClients {
+		return p, fmt.Errorf("publishers and subscribers together must be at most %d", limits.MaxClients)
+	}
+	if d, ok := arguments["max_duration"].(string); ok && d != "" {
+		duration, err := time.ParseDuration(d)
+		if err != nil {
+			return p, fmt.Errorf("invalid max_duration: %w", err)
+		}
+		if duration <= 0 || duration > limits.MaxDuration {
+			return p, fmt.Errorf("max_duration must be positive and at most %s", limits.MaxDuration)
+		}
+		p.duration = duration
+	}
+	switch storage, _ := arguments["storage"].(string); storage {
+	case "", "memory":
+	case "file":
+		p.storage = jetstream.FileStorage
+	default:
+		return p, fmt.Errorf("invalid storage %q, must be memory or file", storage)
+	}
+	return p, nil
+}
+
+// benchRate is the throughput of one side of a benchmark.
+type benchRate struct {
+	Messages    int64   `json:"messages"`
+	Bytes       int64   `json:"bytes"`
+	MsgsPerSec  float64 `json:"msgs_per_sec"`
+	BytesPerSec float64 `json:"bytes_per_sec"`
+}
+
+// benchLatency summarises latencies in milliseconds.
+type benchLatency struct {
+	Samples int     `json:"samples"`
+	Min     float64 `json:"min"`
+	Mean    float64 `json:"mean"`
+	P50     float64 `json:"p50"`
+	P90     float64 `json:"p90"`
+	P99     float64 `json:"p99"`
+	Max     float64 `json:"max"`
+}
+
+// benchResult is the JSON result of the bench tool.
+type benchResult struct {
+	Mode        string        `json:"mode"`
+	Messages    int           `json:"messages"`
+	Size        int           `json:"size"`
+	Publishers  int           `json:"publishers"`
+	Subscribers int           `json:"subscribers"`
+	Stopped     string        `json:"stopped"`
+	DurationMS  float64       `json:"duration_ms"`
+	Publish     benchRate     `json:"publish"`
+	Receive     *benchRate    `json:"receive,omitempty"`
+	LatencyMS   *benchLatency `json:"latency_ms,omitempty"`
+	Cleanup     []string      `json:"cleanup,omitempty"`
+}
+
+// benchCollector records received messages and their latencies. It is done
+// once the expected number of messages arrived.
+type benchCollector struct {
+	expected  int64
+	mu        sync.Mutex
+	latencies []time.Duration
+	received  int64
+	bytes     int64
+	last      time.Time
+	done      chan struct{}
+}
+
+func newBenchCollector(expected int) *benchCollector {
+	return &benchCollector{
+		expected:  int64(expected),
+		latencies: make([]time.Duration, 0, expected),
+		done:      make(chan struct{}),
+	}
+}
+
+func (c *benchCollector) record(latency time.Duration, size int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.received >= c.expected {
+		return
+	}
+	c.latencies = append(c.latencies, latency)
+	c.received++
+	c.bytes += int64(size)
+	c.last = time.Now()
+	if c.received == c.expected {
+		close(c.done)
+	}
+}
+
+// recordStamped records a message whose payload starts with its send time.
+func (c *benchCollector) recordStamped(data []byte) {
+	if len(data) < benchStampSize {
+		return
+	}
+	sent := time.Unix(0, int64(binary.BigEndian.Uint64(data)))
+	c.record(time.Since(sent), len(data))
+}
+
+// wait blocks until every expected message arrived or ctx is done, and
+// reports why it stopped.
+func (c *benchCollector) wait(ctx context.Context) string {
+	select {
+	case <-c.done:
+		return "completed"
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "max_duration"
+		}
+		return stopCancelled
+	}
+}
+
+// rate returns the receive rate since start.
+func (c *benchCollector) rate(start time.Time) *benchRate {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return newBenchRate(c.received, c.bytes, c.last.Sub(start))
+}
+
+// latency summarises the recorded latencies.
+func (c *benchCollector) latency() *benchLatency {
+	c.mu.Lock()
+	latencies := append([]time.Duration(nil), c.latencies...)
+	c.mu.Unlock()
+	if len(latencies) == 0 {
+		return nil
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	ms := func(d time.Duration) float64 { return float64(d.Microseconds()) / 1000 }
+	percentile := func(p float64) float64 {
+		return ms(latencies[int(math.Ceil(p*float64(len(latencies))))-1])
+	}
+	var total time.Duration
+	for _, latency := range latencies {
+		total += latency
+	}
+	return &benchLatency{
+		Samples: len(latencies),
+		Min:     ms(latencies[0]),
+		Mean:    ms(total / time.Duration(len(latencies))),
+		P50:     percentile(0.50),
+		P90:     percentile(0.90),
+		P99:     percentile(0.99),
+		Max:     ms(latencies[len(latencies)-1]),
+	}
+}
+
+func newBenchRate(messages, bytes int64, elapsed time.Duration) *benchRate {
+	rate := &benchRate{Messages: messages, Bytes: bytes}
+	if seconds := elapsed.Seconds(); seconds > 0 {
+		rate.MsgsPerSec = math.Round(float64(messages)/seconds*100) / 100
+		rate.BytesPerSec = math.Round(float64(bytes)/seconds*100) / 100
+	}
+	return rate
+}
+
+// publishAll spreads the messages over the publisher connections, stamping
+// each payload with its send time, and returns the publish rate.
+func publishAll(ctx context.Context, conns []*nats.Conn, messages, size int, publish func(ctx context.Context, nc *nats.Conn, payload []byte) error) (*benchRate, error) {
+	var sent atomic.Int64
+	errs := make(chan error, len(conns))
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i, nc := range conns {
+		share := messages / len(conns)
+		if i < messages%len(conns) {
+			share++
+		}
+		wg.Add(1)
+		go func(nc *nats.Conn, share int) {
+			defer wg.Done()
+			payload := make([]byte, size)
+			for range share {
+				if ctx.Err() != nil {
+					return
+				}
+				binary.BigEndian.PutUint64(payload, uint64(time.Now().UnixNano()))
+				if err := publish(ctx, nc, payload); err != nil {
+					if ctx.Err() == nil {
+						errs <- err
+					}
+					return
+				}
+				sent.Add(1)
+			}
+			if err := nc.Flush(); err != nil && ctx.Err() == nil {
+				errs <- err
+			}
+		}(nc, share)
+	}
+	wg.Wait()
+	close(errs)
+	if err := <-errs; err != nil {
+		return nil, fmt.Errorf("benchmark publish failed: %w", err)
+	}
+	return newBenchRate(sent.Load(), sent.Load()*int64(size), time.Since(start)), nil
+}
+
+func (b *BenchTools) benchHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		p, err := benchParamsFromArgs(request.GetArguments(), b.nats.currentBenchLimits())
+		if err != nil {
+			return nil, err
+		}
+
+		conns := make([]*nats.Conn, 0, p.publishers+p.subscribers)
+		defer func() {
+			for _, nc := range conns {
+				nc.Close()
+			}
+		}()
+		for range p.publishers + p.subscribers {
+			nc, err := b.nats.connect(ctx, request.GetArguments())
+			if err != nil {
+				return nil, err
+			}
+			conns = append(conns, nc)
+		}
+		publishers, subscribers := conns[:p.publishers], conns[p.publishers:]
+
+		id := strings.ReplaceAll(uuid.NewString(), "-", "")
+		run := &benchRun{
+			params:      p,
+			subject:     "mcp.bench." + id,
+			stream:      "MCP_BENCH_" + id,
+			publishers:  publishers,
+			subscribers: subscribers,
+		}
+		result, err := run.run(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return jsonResult(result)
+	}
+}
+
+// benchRun is one benchmark on private subjects and, for the JetStream
+// modes, a temporary stream.
+type benchRun struct {
+	params      benchParams
+	subject     string
+	stream      string
+	publishers  []*nats.Conn
+	subscribers []*nats.Conn
+	// consumers are the consumer workers, stopped when the benchmark ends.
+	consumers []jetstream.ConsumeContext
+}
+
+func (r *benchRun) run(ctx context.Context) (*benchResult, error) {
+	p := r.params
+	result := &benchResult{
+		Mode:        p.mode,
+		Messages:    p.messages,
+		Size:        p.size,
+		Publishers:  p.publishers,
+		Subscribers: p.subscribers,
+	}
+
+	if strings.HasPrefix(p.mode, "js_") {
+		js, err := jetstream.New(r.publishers[0])
+		if err != nil {
+			return nil, err
+		}
+		deleteStream := func() error {
+			cleanupCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			return js.DeleteStream(cleanupCtx, r.stream)
+		}
+		if _, err := js.CreateStream(ctx, jetstream.StreamConfig{
+			Name:     r.stream,
+			Subjects: []string{r.subject},
+			Storage:  p.storage,
+		}); err != nil {
+			// A call cancelled while waiting for the reply may still have
+			// created the stream.
+			if ctx.Err() != nil {
+				_ = deleteStream()
+			}
+			return nil, fmt.Errorf("failed to create benchmark stream: %w", err)
+		}
+		// Delete the stream, and with it the consumer, even when the
+		// benchmark was cancelled.
+		defer func() {
+			if err := deleteStream(); err != nil {
+				result.Cleanup = append(result.Cleanup, fmt.Sprintf("failed to delete stream %s: %v", r.stream, err))
+				return
+			}
+			result.Cleanup = append(result.Cleanup, "deleted stream "+r.stream)
+		}()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.duration)
+	defer cancel()
+	collector := newBenchCollector(p.messages)
+	if p.mode == benchPubSub {
+		// Every subscriber receives every message.
+		collector = newBenchCollector(p.messages * p.subscribers)
+	}
+	if err := r.startSubscribers(ctx, collector); err != nil {
+		return nil, err
+	}
+	defer r.stopSubscribers()
+
+	start := time.Now()
+	publish, err := publishAll(ctx, r.publishers, p.messages, p.size, r.publishFunc(collector))
+	if err != nil {
+		return nil, err
+	}
+	result.Stopped = collector.wait(ctx)
+	result.DurationMS = float64(time.Since(start).Microseconds()) / 1000
+	result.Publish = *publish
+	if p.mode != benchRequest && p.mode != benchJSPublish {
+		result.Receive = collector.rate(start)
+	}
+	result.LatencyMS = collector.latency()
+	return result, nil
+}
+
+// startSubscribers sets up the receiving side of the benchmark.
+func (r *benchRun) startSubscribers(ctx context.Context, collector *benchCollector) error {
+	for _, nc := range r.subscribers {
+		var err error
+		switch r.params.mode {
+		case benchPubSub:
+			var sub *nats.Subscription
+			sub, err = nc.Subscribe(r.subject, func(msg *nats.Msg) { collector.recordStamped(msg.Data) })
+			if err == nil {
+				err = sub.SetPendingLimits(-1, -1)
+			}
+		case benchRequest:
+			_, err = nc.QueueSubscribe(r.subject, "bench", func(msg *nats.Msg) { _ = msg.Respond(msg.Data) })
+		case benchJSPull, benchJSPush:
+			err = r.startConsumer(ctx, nc, collector)
+		}
+		if err == nil {
+			err = nc.Flush()
+		}
+		if err != nil {
+			return fmt.Errorf("failed to start benchmark subscriber: %w", err)
+		}
+	}
+	return nil
+}
+
+// startConsumer starts a worker of the shared benchmark consumer, creating
+// the consumer on first use.
+func (r *benchRun) startConsumer(ctx context.Context, nc *nats.Conn, collector *benchCollector) error {
+	js, err := jetstream.New(nc)
+	if err != nil {
+		return err
+	}
+	handler := func(msg jetstream.Msg) {
+		collector.recordStamped(msg.Data())
+		_ = msg.Ack()
+	}
+	var consumeCtx jetstream.ConsumeContext
+	if r.params.mode == benchJSPull {
+		consumer, err := js.CreateOrUpdateConsumer(ctx, r.stream, jetstream.ConsumerConfig{
+			Durable:   benchConsumer,
+			AckPolicy: jetstream.AckExplicitPolicy,
+		})
+		if err != nil {
+			return err
+		}
+		consumeCtx, err = consumer.Consume(handler)
+		if err != nil {
+			return err
+		}
+	} else {
+		consumer, err := js.CreateOrUpdatePushConsumer(ctx, r.stream, jetstream.ConsumerConfig{
+			Durable:        benchConsumer,
+			DeliverSubject: r.subject + ".deliver",
+			DeliverGroup:   "bench",
+			AckPolicy:      jetstream.AckExplicitPolicy,
+		})
+		if err != nil {
+			return err
+		}
+		consumeCtx, err = consumer.Consume(handler)
+		if err != nil {
+			return err
+		}
+	}
+	r.consumers = append(r.consumers, consumeCtx)
+	return nil
+}
+
+func (r *benchRun) stopSubscribers() {
+	for _, consumeCtx := range r.consumers {
+		consumeCtx.Stop()
+	}
+}
+
+// publishFunc returns how a publisher sends one message in the benchmark
+// mode. Modes measuring round trips record their latency here.
+func (r *benchRun) publishFunc(collector *benchCollector) func(ctx context.Context, nc *nats.Conn, payload []byte) error {
+	switch r.params.mode {
+	case benchRequest:
+		return func(ctx context.Context, nc *nats.Conn, payload []byte) error {
+			start := time.Now()
+			reply, err := nc.RequestWithContext(ctx, r.subject, payload)
+			if err != nil {
+				return err
+			}
+			collector.record(time.Since(start), len(reply.Data))
+			return nil
+		}
+	case benchJSPublish, benchJSPull, benchJSPush:
+		streams := make(map[*nats.Conn]jetstream.JetStream)
+		var mu sync.Mutex
+		return func(ctx context.Context, nc *nats.Conn, payload []byte) error {
+			mu.Lock()
+			js, ok := streams[nc]
+			if !ok {
+				var err error
+				if js, err = jetstream.New(nc); err != nil {
+					mu.Unlock()
+					return err
+				}
+				streams[nc] = js
+			}
+			mu.Unlock()
+			start := time.Now()
+			if _, err := js.Publish(ctx, r.subject, payload); err != nil {
+				return err
+			}
+			if r.params.mode == benchJSPublish {
+				collector.record(time.Since(start), len(payload))
+			}
+			return nil
+		}
+	default:
+		return func(_ context.Context, nc *nats.Conn, payload []byte) error {
+			return nc.Publish(r.subject, payload)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// benchStreams returns the names of the streams left in the account.
+func benchStreams(t *testing.T, js jetstream.JetStream) []string {
+	t.Helper()
+	var names []string
+	lister := js.StreamNames(context.Background())
+	for name := range lister.Name() {
+		names = append(names, name)
+	}
+	if err := lister.Err(); err != nil {
+		t.Fatalf("list streams: %v", err)
+	}
+	return names
+}
+
+func TestBench_coreModes(t *testing.T) {
+	url, _ := startTestServer(t)
+	bench := testToolHandler(t, url, (*NATSServerTools).BenchTools, "bench")
+	run := func(args map[string]any) benchResult {
+		t.Helper()
+		out, err := bench(args)
+		if err != nil {
+			t.Fatalf("bench %v: %v", args, err)
+		}
+		var result benchResult
+		if err := json.Unmarshal([]byte(out), &result); err != nil {
+			t.Fatalf("invalid result %s: %v", out, err)
+		}
+		return result
+	}
+
+	result := run(map[string]any{"mode": "pubsub", "messages": float64(200), "size": float64(16), "publishers": float64(2), "subscribers": float64(2), "max_duration": "5s"})
+	if result.Stopped != "completed" || result.Publish.Messages != 200 || result.Receive == nil || result.Receive.Messages != 400 {
+		t.Fatalf("every subscriber should receive every message: %+v", result)
+	}
+	if result.LatencyMS == nil || result.LatencyMS.Samples != 400 || result.LatencyMS.P50 > result.LatencyMS.P99 || result.LatencyMS.P99 > result.LatencyMS.Max {
+		t.Fatalf("unexpected latency summary: %+v", result.LatencyMS)
+	}
+
+	result = run(map[string]any{"mode": "request", "messages": float64(50), "max_duration": "5s"})
+	if result.Stopped != "completed" || result.Publish.Messages != 50 || result.Receive != nil || result.LatencyMS.Samples != 50 {
+		t.Fatalf("unexpected request result: %+v", result)
+	}
+}
+
+func TestBench_jetStreamModes(t *testing.T) {
+	url, _ := startTestServer(t)
+	nc, err := nats.Connect(url)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(nc.Close)
+	js, err := jetstream.New(nc)
+	if err != nil {
+		t.Fatalf("jetstream: %v", err)
+	}
+	bench := testToolHandler(t, url, (*NATSServerTools).BenchTools, "bench")
+
+	for _, tc := range []struct {
+		mode     string
+		args     map[string]any
+		received bool
+	}{
+		{benchJSPublish, map[string]any{"publishers": float64(2)}, false},
+		{benchJSPull, map[string]any{"subscribers": float64(2), "storage": "memory"}, true},
+		{benchJSPush, map[string]any{"subscribers": float64(2)}, true},
+	} {
+		args := map[string]any{"mode": tc.mode, "messages": float64(200), "size": float64(32), "max_duration": "10s"}
+		for key, value := range tc.args {
+			args[key] = value
+		}
+		out, err := bench(args)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.mode, err)
+		}
+		var result benchResult
+		if err := json.Unmarshal([]byte(out), &result); err != nil {
+			t.Fatalf("%s: invalid result %s: %v", tc.mode, out, err)
+		}
+		if result.Stopped != "completed" || result.Publish.Messages != 200 || (result.Receive != nil) != tc.received ||
+			(tc.received && result.Receive.Messages != 200) || result.LatencyMS == nil || result.LatencyMS.Samples != 200 {
+			t.Fatalf("%s: unexpected result: %s", tc.mode, out)
+		}
+		if len(result.Cleanup) != 1 || !strings.HasPrefix(result.Cleanup[0], "deleted stream MCP_BENCH_") {
+			t.Fatalf("%s: expected the stream to be deleted: %v", tc.mode, result.Cleanup)
+		}
+		if streams := benchStreams(t, js); len(streams) != 0 {
+			t.Fatalf("%s: streams left after the run: %v", tc.mode, streams)
+		}
+	}
+}
+
+func TestBench_cancelledRunDeletesStream(t *testing.T) {
+	url, _ := startTestServer(t)
+	nc, err := nats.Connect(url)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(nc.Close)
+	js, err := jetstream.New(nc)
+	if err != nil {
+		t.Fatalf("jetstream: %v", err)
+	}
+
+	// Cancel the call as soon as the benchmark stream exists.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if _, err := nc.Subscribe("$JS.EVENT.ADVISORY.STREAM.CREATED.>", func(*nats.Msg) { cancel() }); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if err := nc.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	bench := testToolHandlerContext(t, url, (*NATSServerTools).BenchTools, "bench")
+	out, err := bench(ctx, map[string]any{"mode": benchJSPull, "messages": float64(100000), "size": float64(1024), "max_duration": "30s"})
+	if ctx.Err() == nil {
+		t.Fatalf("expected the run to be cancelled: %s, %v", out, err)
+	}
+	if err == nil && !strings.Contains(out, `"stopped": "cancelled"`) {
+		t.Fatalf("expected a cancelled run: %s", out)
+	}
+	if streams := benchStreams(t, js); len(streams) != 0 {
+		t.Fatalf("streams left after the cancelled run: %v", streams)
+	}
+}
+
+func TestBenchParams_enforceLimits(t *testing.T) {
+	limits := BenchLimits{MaxMessages: 1000, MaxMessageSize: 1024, MaxClients: 4, MaxDuration: 10 * time.Second}
+
+	p, err := benchParamsFromArgs(map[string]any{"mode": "js_publish", "subscribers": float64(3)}, limits)
+	if err != nil || p.messages != 1000 || p.size != 128 || p.subscribers != 0 || p.duration != 10*time.Second {
+		t.Fatalf("defaults = %+v, %v", p, err)
+	}
+	for _, tc := range []struct {
+		args map[string]any
+		want string
+	}{
+		{map[string]any{"mode": "flood"}, "invalid mode"},
+		{map[string]any{"mode": "pubsub", "messages": float64(1001)}, "messages must be between 1 and 1000"},
+		{map[string]any{"mode": "pubsub", "size": float64(4)}, "size must be between 8 and 1024"},
+		{map[string]any{"mode": "pubsub", "publishers": float64(3), "subscribers": float64(2)}, "together must be at most 4"},
+		{map[string]any{"mode": "js_pull", "subscribers": float64(0)}, "needs at least one subscriber"},
+		{map[string]any{"mode": "request", "max_duration": "1m"}, "at most 10s"},
+		{map[string]any{"mode": "js_push", "storage": "disk"}, "invalid storage"},
+	} {
+		if _, err := benchParamsFromArgs(tc.args, limits); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%v: error = %v, want %q", tc.args, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	mcpnats "github.com/sinadarbouy/mcp-nats"
+	"github.com/sinadarbouy/mcp-nats/tools/common"
+)
+
+// clusterArgument is the tool argument selecting a configured cluster.
+const clusterArgument = "nats_cluster"
+
+// ClusterTools represents the tools describing the configured NATS clusters
+type ClusterTools struct {
+	nats *NATSServerTools
+}
+
+// NewClusterTools creates a new ClusterTools instance
+func NewClusterTools(nats *NATSServerTools) *ClusterTools {
+	return &ClusterTools{
+		nats: nats,
+	}
+}
+
+// GetTools implements the ToolCategory interface
+func (c *ClusterTools) GetTools() []Tool {
+	return []Tool{
+		{
+			Tool: mcp.Tool{
+				Name:        "cluster_list",
+				Description: "List the NATS clusters this server is configured for; pass a name as the cluster argument of other tools to target it",
+				InputSchema: mcp.ToolInputSchema{
+					Type:       "object",
+					Properties: map[string]interface{}{},
+				},
+			},
+			Handler: c.clusterListHandler(),
+		},
+	}
+}
+
+// clusterSummary is the cluster_list view of a configured cluster. Secrets
+// are never included.
+type clusterSummary struct {
+	Name           string   `json:"name"`
+	URL            string   `json:"url"`
+	Default        bool     `json:"default"`
+	Authentication string   `json:"authentication"`
+	User           string   `json:"user,omitempty"`
+	Accounts       []string `json:"accounts,omitempty"`
+}
+
+func (c *ClusterTools) clusterListHandler() server.ToolHandlerFunc {
+	return func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		clusters := c.nats.currentClusters()
+		summaries := make([]clusterSummary, 0, len(clusters))
+		for i, cluster := range clusters {
+			summary := clusterSummary{
+				Name:           cluster.Name,
+				URL:            cluster.URL,
+				Default:        i == 0,
+				Authentication: cluster.AuthMode(),
+				Accounts:       cluster.AccountNames(),
+			}
+			if summary.Authentication == common.AuthUserPass {
+				summary.User = cluster.User
+			}
+			summaries = append(summaries, summary)
+		}
+
+		output, err := json.MarshalIndent(summaries, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode cluster list: %w", err)
+		}
+		return mcp.NewToolResultText(string(output)), nil
+	}
+}
+
+// ClusterNames returns the names of the configured clusters, the default
+// cluster first.
+func (n *NATSServerTools) ClusterNames() []string {
+	clusters := n.currentClusters()
+	names := make([]string, 0, len(clusters))
+	for _, cluster := range clusters {
+		names = append(names, cluster.Name)
+	}
+	return names
+}
+
+// selectCluster wraps a tool handler so that the NATS URL and authentication
+// in the context are those of the cluster named by the cluster argument, or
+// of the default cluster for calls without it.
+func (n *NATSServerTools) selectCluster(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if name, ok := request.GetArguments()[clusterArgument].(string); ok && name != "" {
+			ctx = mcpnats.WithClusterName(ctx, name)
+		}
+		ctx, err := n.bindCluster(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return next(ctx, request)
+	}
+}
+
+// bindCluster points the context at the current definition of the cluster
+// recorded with mcpnats.WithClusterName, or of the default cluster, so that
+// configuration reloads apply to established sessions. The X-Nats-URL header
+// still overrides the URL of the default cluster. Without configured clusters
+// the context is used as is.
+func (n *NATSServerTools) bindCluster(ctx context.Context) (context.Context, error) {
+	clusters := n.currentClusters()
+	if len(clusters) == 0 {
+		return ctx, nil
+	}
+
+	name := mcpnats.GetClusterNameFromContext(ctx)
+	for i, cluster := range clusters {
+		if name != "" && cluster.Name != name {
+			continue
+		}
+		u := cluster.URL
+		if override := mcpnats.GetNatsURLOverrideFromContext(ctx); i == 0 && override != "" {
+			u = override
+		}
+		ctx = mcpnats.WithClusterName(ctx, cluster.Name)
+		return mcpnats.WithConnection(ctx, u, cluster), nil
+	}
+	return nil, fmt.Errorf("unknown cluster %q (configured: %s)", name, strings.Join(n.ClusterNames(), ", "))
+}
+
+// addClusterArgument adds the optional cluster argument to a tool schema.
+func addClusterArgument(tool *mcp.Tool, names []string) {
+	properties := make(map[string]interface{}, len(tool.InputSchema.Properties)+1)
+	for name, property := range tool.InputSchema.Properties {
+		properties[name] = property
+	}
+	properties[clusterArgument] = map[string]interface{}{
+		"type":        "string",
+		"enum":        names,
+		"description": fmt.Sprintf("The configured NATS cluster to use (default: %s)", names[0]),
+	}
+	tool.InputSchema.Properties = properties
+}
@@ -0,0 +1,149 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	mcpnats "github.com/sinadarbouy/mcp-nats"
+	"github.com/sinadarbouy/mcp-nats/internal/logger"
+	"github.com/sinadarbouy/mcp-nats/tools/common"
+)
+
+func newClusterTestTools(t *testing.T) *NATSServerTools {
+	t.Helper()
+	logger.Initialize(logger.Config{Level: logger.LevelError})
+	n, err := NewNATSServerToolsWithClusters([]common.Connection{
+		{Name: "prod", URL: "nats://prod:4222", User: "admin", Password: "secret"},
+		{Name: "edge", URL: "nats://edge:4222", NoAuthentication: true},
+	})
+	if err != nil {
+		t.Fatalf("NewNATSServerToolsWithClusters: %v", err)
+	}
+	return n
+}
+
+func TestSelectCluster_switchesConnectionAndExecutor(t *testing.T) {
+	n := newClusterTestTools(t)
+	base := mcpnats.WithConnection(context.Background(), "nats://prod:4222", n.clusters[0])
+
+	var executors []*common.NATSExecutor
+	handler := n.selectCluster(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		account, err := mcpnats.DetermineAccountName(ctx, request.GetArguments())
+		if err != nil {
+			return nil, err
+		}
+		executor, err := n.GetExecutor(ctx, account)
+		if err != nil {
+			return nil, err
+		}
+		executors = append(executors, executor)
+		return mcp.NewToolResultText(mcpnats.GetClusterNameFromContext(ctx)), nil
+	})
+	call := func(args map[string]any) (*mcp.CallToolResult, error) {
+		return handler(base, mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}})
+	}
+
+	for _, args := range []map[string]any{nil, {"nats_cluster": "edge"}, {"nats_cluster": "edge"}, {"nats_cluster": "prod"}} {
+		if _, err := call(args); err != nil {
+			t.Fatalf("call with %v: %v", args, err)
+		}
+	}
+	if executors[0].URL != "nats://prod:4222" || executors[0].GetAccountName() != "userpass_admin" {
+		t.Fatalf("default executor = %s as %s", executors[0].URL, executors[0].GetAccountName())
+	}
+	if executors[1].URL != "nats://edge:4222" || executors[1].GetAccountName() != "anonymous" {
+		t.Fatalf("edge executor = %s as %s", executors[1].URL, executors[1].GetAccountName())
+	}
+	if executors[1] != executors[2] {
+		t.Fatalf("edge executor not reused")
+	}
+	if executors[3] != executors[0] {
+		t.Fatalf("naming the default cluster should reuse its executor")
+	}
+
+	if _, err := call(map[string]any{"nats_cluster": "staging"}); err == nil || !strings.Contains(err.Error(), "prod, edge") {
+		t.Fatalf("unknown cluster error = %v", err)
+	}
+}
+
+func TestRegisterTools_clusterArgument(t *testing.T) {
+	n := newClusterTestTools(t)
+	s := server.NewMCPServer("test", "0.0.0")
+	RegisterTools(s, n, false)
+
+	for _, name := range []string{"stream_info", "kv_add", "publish", "cluster_list"} {
+		tool := s.GetTool(name)
+		if tool == nil {
+			t.Fatalf("tool %s not registered", name)
+		}
+		property, ok := tool.Tool.InputSchema.Properties[clusterArgument].(map[string]interface{})
+		if !ok {
+			t.Fatalf("tool %s has no cluster argument", name)
+		}
+		if enum, _ := property["enum"].([]string); strings.Join(enum, ",") != "prod,edge" {
+			t.Fatalf("tool %s cluster enum = %v", name, property["enum"])
+		}
+	}
+	if property, ok := s.GetTool("kv_add").Tool.InputSchema.Properties["cluster"].(map[string]interface{}); !ok || property["enum"] != nil {
+		t.Fatalf("kv_add lost its placement cluster argument")
+	}
+
+	result, err := n.clusterTools.clusterListHandler()(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("cluster_list: %v", err)
+	}
+	var clusters []clusterSummary
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &clusters); err != nil {
+		t.Fatalf("cluster_list output: %v", err)
+	}
+	if len(clusters) != 2 || !clusters[0].Default || clusters[0].User != "admin" || clusters[1].Authentication != "anonymous" {
+		t.Fatalf("cluster_list = %+v", clusters)
+	}
+
+	single, err := NewNATSServerToolsWithConnection(common.Connection{Name: "default", NoAuthentication: true})
+	if err != nil {
+		t.Fatalf("NewNATSServerToolsWithConnection: %v", err)
+	}
+	s = server.NewMCPServer("test", "0.0.0")
+	RegisterTools(s, single, false)
+	if _, ok := s.GetTool("stream_info").Tool.InputSchema.Properties[clusterArgument]; ok {
+		t.Fatalf("cluster argument advertised with a single cluster")
+	}
+}
+
+func TestSetClusters_invalidatesStaleExecutors(t *testing.T) {
+	n := newClusterTestTools(t)
+	executor := func(cluster string) *common.NATSExecutor {
+		t.Helper()
+		ctx := mcpnats.WithClusterName(context.Background(), cluster)
+		e, err := n.GetExecutor(ctx, "A")
+		if err != nil {
+			t.Fatalf("GetExecutor(%s): %v", cluster, err)
+		}
+		return e
+	}
+	prod, edge := executor("prod"), executor("edge")
+
+	clusters := append([]common.Connection(nil), n.clusters...)
+	clusters[1].URL = "nats://edge-2:4222"
+	if err := n.SetClusters(clusters); err != nil {
+		t.Fatalf("SetClusters: %v", err)
+	}
+	if executor("prod") != prod {
+		t.Fatalf("unchanged cluster lost its executor")
+	}
+	if e := executor("edge"); e == edge || e.URL != "nats://edge-2:4222" {
+		t.Fatalf("edge executor = %s, want a new one for the new URL", e.URL)
+	}
+
+	if err := n.SetClusters(clusters[:1]); err != nil {
+		t.Fatalf("SetClusters: %v", err)
+	}
+	if _, err := n.GetExecutor(mcpnats.WithClusterName(context.Background(), "edge"), "A"); err == nil {
+		t.Fatalf("removed cluster still usable")
+	}
+}
@@ -0,0 +1,107 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Authentication modes of a Connection.
+const (
+	AuthAnonymous   = "anonymous"
+	AuthUserPass    = "userpass"
+	AuthCredentials = "credentials"
+)
+
+// Connection describes how mcp-nats reaches a NATS system: the server URL
+// and the authentication used against it.
+type Connection struct {
+	// Name identifies the connection among several configured clusters.
+	Name             string
+	URL              string
+	NoAuthentication bool
+	User             string
+	Password         string
+	// Creds holds per-account credentials for credentials-based
+	// authentication, keyed by account name.
+	Creds map[string]NATSCreds
+}
+
+// ConnectionFromEnv builds a Connection from NATS_URL, NATS_NO_AUTHENTICATION,
+// NATS_USER, NATS_PASSWORD and NATS_<ACCOUNT>_CREDS.
+func ConnectionFromEnv() Connection {
+	user, password := GetUserPassFromEnv()
+	creds, _ := GetCredsFromEnv()
+	return Connection{
+		URL:              strings.TrimSpace(os.Getenv("NATS_URL")),
+		NoAuthentication: os.Getenv("NATS_NO_AUTHENTICATION") == "true",
+		User:             user,
+		Password:         password,
+		Creds:            creds,
+	}
+}
+
+// AuthMode returns the authentication mode: anonymous when authentication is
+// disabled, userpass when both user and password are set, and credentials
+// otherwise.
+func (c Connection) AuthMode() string {
+	if c.NoAuthentication {
+		return AuthAnonymous
+	}
+	if c.User != "" && c.Password != "" {
+		return AuthUserPass
+	}
+	return AuthCredentials
+}
+
+// IsAccountNameRequired reports whether tool calls must name the account.
+// Only credentials-based authentication requires account_name.
+func (c Connection) IsAccountNameRequired() bool {
+	return c.AuthMode() == AuthCredentials
+}
+
+// AccountNames returns the sorted names of the accounts with credentials.
+func (c Connection) AccountNames() []string {
+	names := make([]string, 0, len(c.Creds))
+	for name := range c.Creds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AuthStrategy builds the authentication strategy of the connection. For
+// credentials-based authentication accountName selects the credentials.
+func (c Connection) AuthStrategy(accountName string) (NATSAuthStrategy, error) {
+	switch c.AuthMode() {
+	case AuthAnonymous:
+		return NewAnonymousAuthStrategy(), nil
+	case AuthUserPass:
+		return NewUserPassAuthStrategy(c.User, c.Password), nil
+	default:
+		cred, ok := c.Creds[accountName]
+		if !ok {
+			return nil, fmt.Errorf("no credentials found for account %s", accountName)
+		}
+		return NewCredentialsAuthStrategy(cred)
+	}
+}
+
+// DetermineAccountName determines the account a tool call runs as. For
+// credentials-based authentication it is the account_name argument; otherwise
+// it is the account implied by the authentication mode.
+func (c Connection) DetermineAccountName(requestArgs map[string]interface{}) (string, error) {
+	switch c.AuthMode() {
+	case AuthAnonymous:
+		return "anonymous", nil
+	case AuthUserPass:
+		return fmt.Sprintf("userpass_%s", c.User), nil
+	default:
+		accountName, ok := requestArgs["account_name"].(string)
+		if !ok {
+			return "", fmt.Errorf("missing account_name")
+		}
+		return accountName, nil
+	}
+}
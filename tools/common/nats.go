@@ -1,16 +1,29 @@
 package common
 
 import (
+	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/nats-io/nats.go"
 	"github.com/sinadarbouy/mcp-nats/internal/logger"
+	"github.com/sinadarbouy/mcp-nats/internal/metrics"
+	"github.com/sinadarbouy/mcp-nats/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// connectTimeout bounds the initial handshake for direct NATS connections.
+const connectTimeout = 5 * time.Second
+
 // NATSCreds represents NATS credentials for an account
 type NATSCreds struct {
 	AccountName string
@@ -20,6 +33,7 @@ type NATSCreds struct {
 // NATSAuthStrategy defines the interface for different authentication strategies
 type NATSAuthStrategy interface {
 	BuildArgs(baseURL string) []string
+	NATSOptions() []nats.Option
 	GetAccountName() string
 	Cleanup() error
 }
@@ -39,6 +53,10 @@ func (a *AnonymousAuthStrategy) BuildArgs(baseURL string) []string {
 	return []string{"-s", baseURL}
 }
 
+func (a *AnonymousAuthStrategy) NATSOptions() []nats.Option {
+	return nil
+}
+
 func (a *AnonymousAuthStrategy) GetAccountName() string {
 	return a.accountName
 }
@@ -68,6 +86,11 @@ func (u *UserPassAuthStrategy) BuildArgs(baseURL string) []string {
 	return []string{"-s", baseURL, "--user", u.user, "--password", u.password}
 }
 
+// NATSOptions returns the client options used for direct connections
+func (u *UserPassAuthStrategy) NATSOptions() []nats.Option {
+	return []nats.Option{nats.UserInfo(u.user, u.password)}
+}
+
 // GetAccountName returns the account name for this authentication strategy
 func (u *UserPassAuthStrategy) GetAccountName() string {
 	return u.accountName
@@ -93,16 +116,25 @@ func NewCredentialsAuthStrategy(creds NATSCreds) (*CredentialsAuthStrategy, erro
 		return nil, fmt.Errorf("failed to create temp directory: %v", err)
 	}
 
-	// Create temporary file for credentials
-	credsFile := filepath.Join(tmpDir, fmt.Sprintf("%s.creds", creds.AccountName))
-
 	// Decode and write base64 credentials to file
 	credsData, err := base64.StdEncoding.DecodeString(creds.Creds)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode credentials: %v", err)
 	}
 
-	if err := os.WriteFile(credsFile, credsData, 0600); err != nil {
+	// Create a temporary file per strategy, so that executors of the same
+	// account on different clusters do not share or remove each other's file
+	f, err := os.CreateTemp(tmpDir, creds.AccountName+"-*.creds")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create credentials file: %v", err)
+	}
+	credsFile := f.Name()
+	_, err = f.Write(credsData)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(credsFile)
 		return nil, fmt.Errorf("failed to write credentials file: %v", err)
 	}
 
@@ -123,6 +155,11 @@ func (c *CredentialsAuthStrategy) BuildArgs(baseURL string) []string {
 	return []string{"-s", baseURL, "--creds", c.credsFile}
 }
 
+// NATSOptions returns the client options used for direct connections
+func (c *CredentialsAuthStrategy) NATSOptions() []nats.Option {
+	return []nats.Option{nats.UserCredentials(c.credsFile)}
+}
+
 // GetAccountName returns the account name for this authentication strategy
 func (c *CredentialsAuthStrategy) GetAccountName() string {
 	return c.accountName
@@ -145,6 +182,13 @@ type NATSExecutor struct {
 	URL      string
 	Strategy NATSAuthStrategy
 	stdin    string
+
+	// mu guards the in-flight commands and connections, which keep the
+	// credentials of a retired executor until they finish.
+	mu       sync.Mutex
+	inFlight int
+	retired  bool
+	cleaned  bool
 }
 
 // NewNATSExecutor creates a new NATSExecutor instance with credentials
@@ -183,6 +227,94 @@ func (e *NATSExecutor) SetStdin(input string) {
 
 // ExecuteCommand executes a NATS CLI command with the configured authentication
 func (e *NATSExecutor) ExecuteCommand(args ...string) (string, error) {
+	return e.ExecuteCommandContext(context.Background(), args...)
+}
+
+// ExecuteCommandContext executes a NATS CLI command with the configured
+// authentication. The command is traced as a child span of ctx and killed
+// when ctx is cancelled.
+func (e *NATSExecutor) ExecuteCommandContext(ctx context.Context, args ...string) (string, error) {
+	output, _, err := e.execute(ctx, 0, args...)
+	return output, err
+}
+
+// ExecuteCheckContext executes a Nagios style `nats server check` command and
+// returns its output with the exit code, which carries the check status: 0 OK,
+// 1 WARNING, 2 CRITICAL and 3 UNKNOWN. Other exit codes are errors.
+func (e *NATSExecutor) ExecuteCheckContext(ctx context.Context, args ...string) (string, int, error) {
+	return e.execute(ctx, 3, args...)
+}
+
+// acquire marks a command or connection as using the credentials. It fails
+// once a retired executor has removed them.
+func (e *NATSExecutor) acquire() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.cleaned {
+		return fmt.Errorf("credentials of account %s were replaced by a configuration reload, retry the call", e.Strategy.GetAccountName())
+	}
+	e.inFlight++
+	return nil
+}
+
+// release ends a use of the credentials, cleaning up a retired executor
+// after its last use.
+func (e *NATSExecutor) release() {
+	e.mu.Lock()
+	e.inFlight--
+	cleanup := e.retired && e.inFlight == 0 && !e.cleaned
+	if cleanup {
+		e.cleaned = true
+	}
+	e.mu.Unlock()
+	if cleanup {
+		e.cleanupRetired()
+	}
+}
+
+// Retire removes the temporary credentials once the commands and
+// connections still using them finish, or right away when there are none.
+func (e *NATSExecutor) Retire() {
+	e.mu.Lock()
+	e.retired = true
+	cleanup := e.inFlight == 0 && !e.cleaned
+	if cleanup {
+		e.cleaned = true
+	}
+	e.mu.Unlock()
+	if cleanup {
+		e.cleanupRetired()
+	}
+}
+
+func (e *NATSExecutor) cleanupRetired() {
+	if err := e.Strategy.Cleanup(); err != nil {
+		logger.Error("Failed to cleanup executor",
+			"error", err,
+			"account", e.Strategy.GetAccountName(),
+		)
+	}
+}
+
+// execute runs a NATS CLI command. Exit codes up to maxExitCode are returned
+// with the output rather than as errors.
+func (e *NATSExecutor) execute(ctx context.Context, maxExitCode int, args ...string) (string, int, error) {
+	command := CommandName(args)
+	ctx, span := tracing.Tracer().Start(ctx, "nats "+command,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("nats.command", command),
+			attribute.String("nats.account", e.Strategy.GetAccountName()),
+		),
+	)
+	defer span.End()
+
+	if err := e.acquire(); err != nil {
+		return "", 0, err
+	}
+	defer e.release()
+
+	start := time.Now()
 	baseArgs := e.Strategy.BuildArgs(e.URL)
 	args = append(baseArgs, args...)
 
@@ -191,7 +323,7 @@ func (e *NATSExecutor) ExecuteCommand(args ...string) (string, error) {
 		"command", strings.Join(args, " "),
 	)
 
-	cmd := exec.Command("nats", args...)
+	cmd := exec.CommandContext(ctx, "nats", args...)
 
 	// If stdin is set, use it
 	if e.stdin != "" {
@@ -201,17 +333,66 @@ func (e *NATSExecutor) ExecuteCommand(args ...string) (string, error) {
 	}
 
 	output, err := cmd.CombinedOutput()
+	exitCode := 0
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 && exitErr.ExitCode() <= maxExitCode {
+		exitCode, err = exitErr.ExitCode(), nil
+	}
+	metrics.ObserveNATSCommand(command, e.Strategy.GetAccountName(), err, time.Since(start))
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		logger.Error("NATS command failed",
 			"error", err,
 			"output", string(output),
 			"account", e.Strategy.GetAccountName(),
 			"command", strings.Join(args, " "),
 		)
-		return "", fmt.Errorf("NATS command failed: %v, output: %s", err, string(output))
+		return "", 0, fmt.Errorf("NATS command failed: %v, output: %s", err, string(output))
 	}
 
-	return string(output), nil
+	return string(output), exitCode, nil
+}
+
+// commandGroups are the CLI commands whose second word is a subcommand rather
+// than a user-supplied value such as a subject.
+var commandGroups = map[string]struct{}{
+	"account":  {},
+	"consumer": {},
+	"kv":       {},
+	"micro":    {},
+	"object":   {},
+	"server":   {},
+	"stream":   {},
+}
+
+// CommandName returns the subcommand of a CLI invocation, e.g. "stream info"
+// for ["stream", "info", "ORDERS"] and "pub" for ["pub", "orders.new", "hi"].
+// It never includes user-supplied values, so it is safe as a metric label.
+func CommandName(args []string) string {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return "unknown"
+	}
+	if _, ok := commandGroups[args[0]]; ok && len(args) > 1 && !strings.HasPrefix(args[1], "-") {
+		return args[0] + " " + args[1]
+	}
+	return args[0]
+}
+
+// Connect opens a direct client connection using the executor's URL and
+// authentication strategy. Callers own the returned connection.
+// The credentials stay in place until the connection is closed, as they are
+// read again on reconnects; the connection's closed handler is taken for that.
+func (e *NATSExecutor) Connect(opts ...nats.Option) (*nats.Conn, error) {
+	if err := e.acquire(); err != nil {
+		return nil, err
+	}
+	nc, err := Connect(e.URL, e.Strategy, append(opts, nats.ClosedHandler(func(*nats.Conn) { e.release() }))...)
+	if err != nil {
+		e.release()
+		return nil, err
+	}
+	return nc, nil
 }
 
 // Cleanup removes the temporary credentials file
@@ -224,6 +405,22 @@ func (e *NATSExecutor) GetAccountName() string {
 	return e.Strategy.GetAccountName()
 }
 
+// Connect opens a NATS client connection to url authenticated with strategy.
+func Connect(url string, strategy NATSAuthStrategy, opts ...nats.Option) (*nats.Conn, error) {
+	options := []nats.Option{
+		nats.Name("mcp-nats"),
+		nats.Timeout(connectTimeout),
+	}
+	options = append(options, strategy.NATSOptions()...)
+	options = append(options, opts...)
+
+	nc, err := nats.Connect(url, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS at %s as %s: %w", url, strategy.GetAccountName(), err)
+	}
+	return nc, nil
+}
+
 // GetCredsFromEnv gets all NATS credentials from environment variables
 // Environment variables should be in the format NATS_<ACCOUNT>_CRED
 func GetCredsFromEnv() (map[string]NATSCreds, error) {
@@ -265,48 +462,22 @@ func GetUserPassFromEnv() (string, string) {
 
 // GetAuthStrategy determines the current authentication strategy
 func GetAuthStrategy() string {
-	// Check for no-authentication flag
-	if os.Getenv("NATS_NO_AUTHENTICATION") == "true" {
-		return "anonymous"
-	}
-
-	// Check for user/password authentication
-	user, password := GetUserPassFromEnv()
-	if user != "" && password != "" {
-		return "userpass"
-	}
+	return ConnectionFromEnv().AuthMode()
+}
 
-	// Default to credentials-based authentication
-	return "credentials"
+// GetAuthStrategyFromEnv builds the authentication strategy selected by the
+// environment. For credentials-based authentication accountName selects which
+// NATS_<ACCOUNT>_CREDS entry to use.
+func GetAuthStrategyFromEnv(accountName string) (NATSAuthStrategy, error) {
+	return ConnectionFromEnv().AuthStrategy(accountName)
 }
 
 // IsAccountNameRequired determines if account_name is required based on auth strategy
 func IsAccountNameRequired() bool {
-	strategy := GetAuthStrategy()
-	// Only credentials-based authentication requires account_name
-	return strategy == "credentials"
+	return ConnectionFromEnv().IsAccountNameRequired()
 }
 
 // DetermineAccountName determines the account name to use based on authentication strategy and request parameters
 func DetermineAccountName(requestArgs map[string]interface{}) (string, error) {
-	if IsAccountNameRequired() {
-		// For credentials-based auth, account_name is required from request
-		accountNameVal, ok := requestArgs["account_name"].(string)
-		if !ok {
-			return "", fmt.Errorf("missing account_name")
-		}
-		return accountNameVal, nil
-	} else {
-		// For non-credentials auth, use strategy's account name
-		strategy := GetAuthStrategy()
-		switch strategy {
-		case "anonymous":
-			return "anonymous", nil
-		case "userpass":
-			user, _ := GetUserPassFromEnv()
-			return fmt.Sprintf("userpass_%s", user), nil
-		default:
-			return "default", nil
-		}
-	}
+	return ConnectionFromEnv().DetermineAccountName(requestArgs)
 }
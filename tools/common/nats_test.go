@@ -0,0 +1,50 @@
+package common
+
+import (
+	"encoding/base64"
+	"os"
+	"testing"
+
+	"github.com/sinadarbouy/mcp-nats/internal/logger"
+)
+
+func TestCommandName(t *testing.T) {
+	cases := map[string][]string{
+		"stream info":    {"stream", "info", "ORDERS", "--json"},
+		"kv get":         {"kv", "get", "bucket", "key"},
+		"pub":            {"pub", "orders.new", "hello"},
+		"rtt":            {"rtt", "--json"},
+		"account report": {"account", "report", "connections"},
+		"unknown":        {"--version"},
+	}
+	for want, args := range cases {
+		if got := CommandName(args); got != want {
+			t.Errorf("CommandName(%v) = %q, want %q", args, got, want)
+		}
+	}
+}
+
+func TestNATSExecutor_retireWaitsForInFlightCommands(t *testing.T) {
+	logger.Initialize(logger.Config{Level: logger.LevelError})
+	creds := base64.StdEncoding.EncodeToString([]byte("creds"))
+	executor, err := NewNATSExecutor("nats://test:4222", NATSCreds{AccountName: "A", Creds: creds})
+	if err != nil {
+		t.Fatalf("NewNATSExecutor: %v", err)
+	}
+	credsFile := executor.Strategy.(*CredentialsAuthStrategy).credsFile
+
+	if err := executor.acquire(); err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	executor.Retire()
+	if _, err := os.Stat(credsFile); err != nil {
+		t.Fatalf("credentials removed while a command was running: %v", err)
+	}
+	executor.release()
+	if _, err := os.Stat(credsFile); !os.IsNotExist(err) {
+		t.Fatalf("credentials kept after the last command finished: %v", err)
+	}
+	if err := executor.acquire(); err == nil {
+		t.Fatalf("expected a retired executor to refuse new commands")
+	}
+}
@@ -0,0 +1,264 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpnats "github.com/sinadarbouy/mcp-nats"
+	"github.com/sinadarbouy/mcp-nats/internal/logger"
+)
+
+const (
+	// completionTTL is how long listed names are reused per account.
+	completionTTL = 30 * time.Second
+	// maxCompletionValues is the most values a completion result may carry.
+	maxCompletionValues = 100
+)
+
+// Kinds of names offered as completions.
+const (
+	completeAccounts      = "accounts"
+	completeStreams       = "streams"
+	completeKVBuckets     = "kv-buckets"
+	completeKVKeys        = "kv-keys"
+	completeObjectBuckets = "object-buckets"
+	completeObjects       = "objects"
+	completeServers       = "servers"
+)
+
+type completionEntry struct {
+	values  []string
+	expires time.Time
+}
+
+// CompletionTools implements completion/complete for prompt arguments and
+// resource template variables. Names are listed through the NATS CLI and
+// cached briefly per account.
+type CompletionTools struct {
+	nats *NATSServerTools
+	list func(ctx context.Context, account, kind, scope string) ([]string, error)
+	now  func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]completionEntry
+}
+
+// NewCompletionTools creates a new CompletionTools instance
+func NewCompletionTools(nats *NATSServerTools) *CompletionTools {
+	c := &CompletionTools{
+		nats:  nats,
+		now:   time.Now,
+		cache: make(map[string]completionEntry),
+	}
+	c.list = c.listNames
+	return c
+}
+
+// CompletePromptArgument implements server.PromptCompletionProvider
+func (c *CompletionTools) CompletePromptArgument(ctx context.Context, promptName string, argument mcp.CompleteArgument, completeCtx mcp.CompleteContext) (*mcp.Completion, error) {
+	var kind string
+	switch argument.Name {
+	case "account":
+		kind = completeAccounts
+	case "stream":
+		kind = completeStreams
+	case "bucket":
+		kind = completeKVBuckets
+	case "target":
+		kind = completeServers
+	}
+	return c.complete(ctx, completeCtx.Arguments["account"], kind, "", argument.Value), nil
+}
+
+// CompleteResourceArgument implements server.ResourceCompletionProvider
+func (c *CompletionTools) CompleteResourceArgument(ctx context.Context, uri string, argument mcp.CompleteArgument, completeCtx mcp.CompleteContext) (*mcp.Completion, error) {
+	// Templates look like nats://{account}/<section>/..., see GetResourceTemplates.
+	section := ""
+	if _, rest, ok := strings.Cut(strings.TrimPrefix(uri, resourceScheme), "/"); ok {
+		section, _, _ = strings.Cut(rest, "/")
+	}
+
+	var kind, scope string
+	switch {
+	case argument.Name == "account":
+		kind = completeAccounts
+	case section == "stream" && argument.Name == "name":
+		kind = completeStreams
+	case section == "kv" && argument.Name == "bucket":
+		kind = completeKVBuckets
+	case section == "kv" && argument.Name == "key":
+		kind, scope = completeKVKeys, completeCtx.Arguments["bucket"]
+	case section == "object" && argument.Name == "bucket":
+		kind = completeObjectBuckets
+	case section == "object" && argument.Name == "name":
+		kind, scope = completeObjects, completeCtx.Arguments["bucket"]
+	}
+	return c.complete(ctx, completeCtx.Arguments["account"], kind, scope, argument.Value), nil
+}
+
+// complete returns the names of the given kind starting with prefix. Listing
+// failures are logged and yield no completions.
+func (c *CompletionTools) complete(ctx context.Context, account, kind, scope, prefix string) *mcp.Completion {
+	completion := &mcp.Completion{Values: []string{}}
+	if kind == "" {
+		return completion
+	}
+
+	var names []string
+	if kind == completeAccounts {
+		names = accountNames(ctx)
+	} else {
+		if account == "" {
+			account = defaultCompletionAccount(ctx)
+		}
+		if account == "" || (scope == "" && (kind == completeKVKeys || kind == completeObjects)) {
+			return completion
+		}
+		var err error
+		names, err = c.cached(ctx, account, kind, scope)
+		if err != nil {
+			logger.Debug("Failed to list completion values", "kind", kind, "account", account, "error", err)
+			return completion
+		}
+	}
+
+	prefix = strings.ToLower(prefix)
+	for _, name := range names {
+		if !strings.HasPrefix(strings.ToLower(name), prefix) {
+			continue
+		}
+		completion.Total++
+		if len(completion.Values) < maxCompletionValues {
+			completion.Values = append(completion.Values, name)
+		}
+	}
+	completion.HasMore = completion.Total > len(completion.Values)
+	return completion
+}
+
+// cached returns listed names, reusing them for completionTTL per account,
+// kind and scope.
+func (c *CompletionTools) cached(ctx context.Context, account, kind, scope string) ([]string, error) {
+	key := strings.Join([]string{account, kind, scope}, "\x00")
+
+	c.mu.Lock()
+	entry, ok := c.cache[key]
+	c.mu.Unlock()
+	if ok && c.now().Before(entry.expires) {
+		return entry.values, nil
+	}
+
+	values, err := c.list(ctx, account, kind, scope)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(values)
+
+	c.mu.Lock()
+	c.cache[key] = completionEntry{values: values, expires: c.now().Add(completionTTL)}
+	c.mu.Unlock()
+	return values, nil
+}
+
+// accountNames returns the accounts with configured credentials, or the
+// single implicit account of anonymous and user/password authentication.
+func accountNames(ctx context.Context) []string {
+	if names := mcpnats.GetAccountNamesFromContext(ctx); len(names) > 0 {
+		return names
+	}
+	account, err := mcpnats.DetermineAccountName(ctx, map[string]interface{}{})
+	if err != nil {
+		return nil
+	}
+	return []string{account}
+}
+
+// defaultCompletionAccount returns the account to list names for when the
+// client has not chosen one, if that is unambiguous.
+func defaultCompletionAccount(ctx context.Context) string {
+	if names := accountNames(ctx); len(names) == 1 {
+		return names[0]
+	}
+	return ""
+}
+
+// listNames lists names of the given kind through the NATS CLI.
+func (c *CompletionTools) listNames(ctx context.Context, account, kind, scope string) ([]string, error) {
+	executor, err := c.nats.GetExecutor(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+
+	var args []string
+	switch kind {
+	case completeStreams:
+		args = []string{"stream", "ls", "--names"}
+	case completeKVBuckets:
+		args = []string{"kv", "ls", "--names"}
+	case completeKVKeys:
+		args = []string{"kv", "ls", scope}
+	case completeObjectBuckets:
+		args = []string{"object", "ls", "--names"}
+	case completeObjects:
+		args = []string{"object", "ls", scope, "--json"}
+	case completeServers:
+		args = []string{"server", "list", "--json"}
+	default:
+		return nil, fmt.Errorf("unknown completion kind %s", kind)
+	}
+
+	output, err := executor.ExecuteCommandContext(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	switch kind {
+	case completeObjects:
+		var objects []struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal([]byte(output), &objects); err != nil {
+			return nil, fmt.Errorf("failed to parse object list: %w", err)
+		}
+		names := make([]string, 0, len(objects))
+		for _, obj := range objects {
+			names = append(names, obj.Name)
+		}
+		return names, nil
+	case completeServers:
+		return parseServerNames(output)
+	default:
+		return outputLines(output), nil
+	}
+}
+
+// parseServerNames returns the server and cluster names in the JSON output
+// of `nats server list`.
+func parseServerNames(output string) ([]string, error) {
+	var servers []struct {
+		Server struct {
+			Name    string `json:"name"`
+			Cluster string `json:"cluster"`
+		} `json:"server"`
+	}
+	if err := json.Unmarshal([]byte(output), &servers); err != nil {
+		return nil, fmt.Errorf("failed to parse server list: %w", err)
+	}
+	seen := make(map[string]bool)
+	var names []string
+	for _, s := range servers {
+		for _, name := range []string{s.Server.Cluster, s.Server.Name} {
+			if name != "" && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,114 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpnats "github.com/sinadarbouy/mcp-nats"
+	"github.com/sinadarbouy/mcp-nats/tools/common"
+)
+
+func TestCompletionTools_completesAndCaches(t *testing.T) {
+	ctx := mcpnats.WithNatsCreds(context.Background(), map[string]common.NATSCreds{
+		"ORDERS": {}, "BILLING": {},
+	})
+
+	c := NewCompletionTools(nil)
+	now := time.Unix(0, 0)
+	c.now = func() time.Time { return now }
+	calls := 0
+	c.list = func(_ context.Context, account, kind, scope string) ([]string, error) {
+		calls++
+		switch kind {
+		case completeStreams:
+			return []string{account + "_EVENTS", account + "_AUDIT", "orders_dlq"}, nil
+		case completeKVKeys:
+			return []string{scope + ".a", scope + ".b"}, nil
+		}
+		return nil, fmt.Errorf("unexpected kind %s", kind)
+	}
+
+	complete := func(uri, arg, value string, args map[string]string) []string {
+		t.Helper()
+		got, err := c.CompleteResourceArgument(ctx, uri, mcp.CompleteArgument{Name: arg, Value: value}, mcp.CompleteContext{Arguments: args})
+		if err != nil {
+			t.Fatalf("CompleteResourceArgument: %v", err)
+		}
+		return got.Values
+	}
+
+	if got, want := complete("nats://{account}/streams", "account", "", nil), []string{"BILLING", "ORDERS"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("accounts = %v, want %v", got, want)
+	}
+	streamArgs := map[string]string{"account": "ORDERS"}
+	if got, want := complete("nats://{account}/stream/{name}", "name", "orders_", streamArgs), []string{"ORDERS_AUDIT", "ORDERS_EVENTS", "orders_dlq"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("streams = %v, want %v", got, want)
+	}
+	complete("nats://{account}/stream/{name}/msg/{seq}", "name", "", streamArgs)
+	if calls != 1 {
+		t.Fatalf("list called %d times, want 1 while cached", calls)
+	}
+	complete("nats://{account}/stream/{name}", "name", "", map[string]string{"account": "BILLING"})
+	if calls != 2 {
+		t.Fatalf("list called %d times, want a separate cache entry per account", calls)
+	}
+	now = now.Add(completionTTL + time.Second)
+	complete("nats://{account}/stream/{name}", "name", "", streamArgs)
+	if calls != 3 {
+		t.Fatalf("list called %d times, want a refresh after the TTL", calls)
+	}
+
+	if got := complete("nats://{account}/kv/{bucket}/{key}", "key", "", streamArgs); len(got) != 0 {
+		t.Fatalf("keys without a bucket = %v, want none", got)
+	}
+	keyArgs := map[string]string{"account": "ORDERS", "bucket": "cfg"}
+	if got, want := complete("nats://{account}/kv/{bucket}/{key}", "key", "cfg.b", keyArgs), []string{"cfg.b"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("keys = %v, want %v", got, want)
+	}
+	if got := complete("nats://{account}/stream/{name}/msg/{seq}", "seq", "", streamArgs); len(got) != 0 {
+		t.Fatalf("seq completions = %v, want none", got)
+	}
+
+	got, err := c.CompletePromptArgument(ctx, "diagnose_stream_lag", mcp.CompleteArgument{Name: "stream", Value: "ORDERS_E"}, mcp.CompleteContext{Arguments: streamArgs})
+	if err != nil {
+		t.Fatalf("CompletePromptArgument: %v", err)
+	}
+	if want := []string{"ORDERS_EVENTS"}; !reflect.DeepEqual(got.Values, want) {
+		t.Fatalf("prompt streams = %v, want %v", got.Values, want)
+	}
+}
+
+func TestCompletionTools_capsValues(t *testing.T) {
+	c := NewCompletionTools(nil)
+	c.list = func(context.Context, string, string, string) ([]string, error) {
+		names := make([]string, maxCompletionValues+5)
+		for i := range names {
+			names[i] = fmt.Sprintf("S%03d", i)
+		}
+		return names, nil
+	}
+
+	got := c.complete(context.Background(), "A", completeStreams, "", "")
+	if len(got.Values) != maxCompletionValues || got.Total != maxCompletionValues+5 || !got.HasMore {
+		t.Fatalf("got %d values, total %d, hasMore %v", len(got.Values), got.Total, got.HasMore)
+	}
+}
+
+func TestParseServerNames(t *testing.T) {
+	output := `[
+		{"server": {"name": "n1", "cluster": "east"}},
+		{"server": {"name": "n2", "cluster": "east"}},
+		{"server": {"name": "n3"}}
+	]`
+	got, err := parseServerNames(output)
+	if err != nil {
+		t.Fatalf("parseServerNames: %v", err)
+	}
+	if want := []string{"east", "n1", "n2", "n3"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseServerNames = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,775 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ConsumerTools represents all NATS JetStream consumer-related tools
+type ConsumerTools struct {
+	nats *NATSServerTools
+}
+
+// NewConsumerTools creates a new ConsumerTools instance
+func NewConsumerTools(nats *NATSServerTools) *ConsumerTools {
+	return &ConsumerTools{
+		nats: nats,
+	}
+}
+
+// consumerSettingProperties are the consumer settings that can be given when
+// adding a consumer and changed when editing one.
+func consumerSettingProperties() map[string]interface{} {
+	return map[string]interface{}{
+		"description": map[string]interface{}{
+			"type":        "string",
+			"description": "A description for the consumer",
+		},
+		"filter_subjects": map[string]interface{}{
+			"type":        "array",
+			"items":       map[string]interface{}{"type": "string"},
+			"description": "Only deliver messages on these subjects",
+		},
+		"ack_wait": map[string]interface{}{
+			"type":        "string",
+			"description": "How long to wait for an acknowledgement before redelivering, e.g. 30s",
+		},
+		"max_deliver": map[string]interface{}{
+			"type":        "integer",
+			"description": "Maximum number of times a message is delivered",
+		},
+		"max_ack_pending": map[string]interface{}{
+			"type":        "integer",
+			"description": "Maximum number of unacknowledged messages before delivery pauses",
+		},
+		"backoff": map[string]interface{}{
+			"type":        "string",
+			"description": "Redelivery backoff policy",
+			"enum":        []string{"none", "linear"},
+		},
+		"backoff_steps": map[string]interface{}{
+			"type":        "integer",
+			"description": "Number of backoff steps to create",
+		},
+		"backoff_min": map[string]interface{}{
+			"type":        "string",
+			"description": "Shortest backoff period, e.g. 1s",
+		},
+		"backoff_max": map[string]interface{}{
+			"type":        "string",
+			"description": "Longest backoff period, e.g. 5m",
+		},
+		"inactive_threshold": map[string]interface{}{
+			"type":        "string",
+			"description": "Remove the consumer after it has been inactive this long, e.g. 1h",
+		},
+		"heartbeat": map[string]interface{}{
+			"type":        "string",
+			"description": "Idle heartbeat interval for push consumers",
+		},
+		"max_waiting": map[string]interface{}{
+			"type":        "integer",
+			"description": "Maximum number of outstanding pull requests",
+		},
+		"max_pull_batch": map[string]interface{}{
+			"type":        "integer",
+			"description": "Maximum batch size of a single pull request",
+		},
+		"replicas": map[string]interface{}{
+			"type":        "integer",
+			"description": "Number of replicas of the consumer state",
+		},
+	}
+}
+
+// GetTools implements the ToolCategory interface
+func (c *ConsumerTools) GetTools() []Tool {
+	addProperties := consumerSettingProperties()
+	for name, property := range map[string]interface{}{
+		"account_name": map[string]interface{}{
+			"type":        "string",
+			"description": "The NATS account to use",
+		},
+		"stream": map[string]interface{}{
+			"type":        "string",
+			"description": "The stream to add the consumer to",
+		},
+		"consumer": map[string]interface{}{
+			"type":        "string",
+			"description": "The durable name of the consumer",
+		},
+		"mode": map[string]interface{}{
+			"type":        "string",
+			"description": "Pull consumers are fetched from by clients, push consumers deliver to deliver_subject",
+			"enum":        []string{"pull", "push"},
+			"default":     "pull",
+		},
+		"deliver_subject": map[string]interface{}{
+			"type":        "string",
+			"description": "Subject push consumers deliver messages to (required for push mode)",
+		},
+		"deliver_group": map[string]interface{}{
+			"type":        "string",
+			"description": "Queue group that push consumer subscribers must join",
+		},
+		"deliver_policy": map[string]interface{}{
+			"type":        "string",
+			"description": "Where to start delivering: all, last, new, last_per_subject, a start sequence or a duration such as 1h",
+		},
+		"ack_policy": map[string]interface{}{
+			"type":        "string",
+			"description": "Acknowledgement policy",
+			"enum":        []string{"explicit", "all", "none"},
+		},
+		"replay_policy": map[string]interface{}{
+			"type":        "string",
+			"description": "Replay messages as fast as possible or at the rate they were received",
+			"enum":        []string{"instant", "original"},
+		},
+		"flow_control": map[string]interface{}{
+			"type":        "boolean",
+			"description": "Enable flow control for push consumers",
+		},
+		"headers_only": map[string]interface{}{
+			"type":        "boolean",
+			"description": "Deliver only message headers, no bodies",
+		},
+		"memory": map[string]interface{}{
+			"type":        "boolean",
+			"description": "Keep the consumer state in memory rather than on disk",
+		},
+		"flags": map[string]interface{}{
+			"type":        "array",
+			"items":       map[string]interface{}{"type": "string"},
+			"description": "Optional flags to pass to the command",
+		},
+	} {
+		addProperties[name] = property
+	}
+
+	editProperties := consumerSettingProperties()
+	for name, property := range map[string]interface{}{
+		"account_name": map[string]interface{}{
+			"type":        "string",
+			"description": "The NATS account to use",
+		},
+		"stream": map[string]interface{}{
+			"type":        "string",
+			"description": "Stream name",
+		},
+		"consumer": map[string]interface{}{
+			"type":        "string",
+			"description": "Consumer name",
+		},
+		"dry_run": map[string]interface{}{
+			"type":        "boolean",
+			"description": "Only show the differences, do not edit the consumer",
+		},
+		"force": map[string]interface{}{
+			"type":        "boolean",
+			"description": "Act without confirmation",
+			"default":     false,
+		},
+		"flags": map[string]interface{}{
+			"type":        "array",
+			"items":       map[string]interface{}{"type": "string"},
+			"description": "Optional flags to pass to the command",
+		},
+	} {
+		editProperties[name] = property
+	}
+
+	return []Tool{
+		{
+			Tool: mcp.Tool{
+				Name:        "consumer_list",
+				Description: "List the consumers of a stream",
+				InputSchema: mcp.ToolInputSchema{
+					Type: "object",
+					Properties: map[string]interface{}{
+						"account_name": map[string]interface{}{
+							"type":        "string",
+							"description": "The NATS account to use",
+						},
+						"stream": map[string]interface{}{
+							"type":        "string",
+							"description": "Stream name",
+						},
+						"flags": map[string]interface{}{
+							"type":        "array",
+							"items":       map[string]interface{}{"type": "string"},
+							"description": "Optional flags to pass to the command",
+						},
+					},
+					Required: []string{"account_name", "stream"},
+				},
+			},
+			Handler: c.consumerListHandler(),
+		},
+		{
+			Tool: mcp.Tool{
+				Name:        "consumer_info",
+				Description: "Get the configuration and state of a consumer",
+				InputSchema: mcp.ToolInputSchema{
+					Type: "object",
+					Properties: map[string]interface{}{
+						"account_name": map[string]interface{}{
+							"type":        "string",
+							"description": "The NATS account to use",
+						},
+						"stream": map[string]interface{}{
+							"type":        "string",
+							"description": "Stream name",
+						},
+						"consumer": map[string]interface{}{
+							"type":        "string",
+							"description": "Consumer name",
+						},
+						"flags": map[string]interface{}{
+							"type":        "array",
+							"items":       map[string]interface{}{"type": "string"},
+							"description": "Optional flags to pass to the command",
+						},
+					},
+					Required: []string{"account_name", "stream", "consumer"},
+				},
+			},
+			Handler: c.consumerInfoHandler(),
+		},
+		{
+			Tool: mcp.Tool{
+				Name:        "consumer_report",
+				Description: "Report on the pending, unacknowledged and redelivered messages of the consumers of a stream",
+				InputSchema: mcp.ToolInputSchema{
+					Type: "object",
+					Properties: map[string]interface{}{
+						"account_name": map[string]interface{}{
+							"type":        "string",
+							"description": "The NATS account to use",
+						},
+						"stream": map[string]interface{}{
+							"type":        "string",
+							"description": "Stream name",
+						},
+						"consumer": map[string]interface{}{
+							"type":        "string",
+							"description": "Limit the report to this consumer",
+						},
+						"flags": map[string]interface{}{
+							"type":        "array",
+							"items":       map[string]interface{}{"type": "string"},
+							"description": "Optional flags to pass to the command",
+						},
+					},
+					Required: []string{"account_name", "stream"},
+				},
+			},
+			Handler: c.consumerReportHandler(),
+		},
+		{
+			Tool: mcp.Tool{
+				Name:        "consumer_add",
+				Description: "Creates a new pull or push consumer on a stream",
+				InputSchema: mcp.ToolInputSchema{
+					Type:       "object",
+					Properties: addProperties,
+					Required:   []string{"account_name", "stream", "consumer"},
+				},
+			},
+			Handler: c.consumerAddHandler(),
+		},
+		{
+			Tool: mcp.Tool{
+				Name:        "consumer_edit",
+				Description: "Changes the settings of an existing consumer",
+				InputSchema: mcp.ToolInputSchema{
+					Type:       "object",
+					Properties: editProperties,
+					Required:   []string{"account_name", "stream", "consumer"},
+				},
+			},
+			Handler: c.consumerEditHandler(),
+		},
+		{
+			Tool: mcp.Tool{
+				Name:        "consumer_rm",
+				Description: "Removes a consumer from a stream",
+				InputSchema: mcp.ToolInputSchema{
+					Type: "object",
+					Properties: map[string]interface{}{
+						"account_name": map[string]interface{}{
+							"type":        "string",
+							"description": "The NATS account to use",
+						},
+						"stream": map[string]interface{}{
+							"type":        "string",
+							"description": "Stream name",
+						},
+						"consumer": map[string]interface{}{
+							"type":        "string",
+							"description": "Consumer name",
+						},
+						"force": map[string]interface{}{
+							"type":        "boolean",
+							"description": "Act without confirmation",
+							"default":     false,
+						},
+						"flags": map[string]interface{}{
+							"type":        "array",
+							"items":       map[string]interface{}{"type": "string"},
+							"description": "Optional flags to pass to the command",
+						},
+					},
+					Required: []string{"account_name", "stream", "consumer"},
+				},
+			},
+			Handler: c.consumerRmHandler(),
+		},
+		{
+			Tool: mcp.Tool{
+				Name:        "consumer_pause",
+				Description: "Pauses message delivery of a consumer until a given time",
+				InputSchema: mcp.ToolInputSchema{
+					Type: "object",
+					Properties: map[string]interface{}{
+						"account_name": map[string]interface{}{
+							"type":        "string",
+							"description": "The NATS account to use",
+						},
+						"stream": map[string]interface{}{
+							"type":        "string",
+							"description": "Stream name",
+						},
+						"consumer": map[string]interface{}{
+							"type":        "string",
+							"description": "Consumer name",
+						},
+						"until": map[string]interface{}{
+							"type":        "string",
+							"description": "Pause until this time, as a duration such as 1h or a timestamp",
+						},
+						"force": map[string]interface{}{
+							"type":        "boolean",
+							"description": "Act without confirmation",
+							"default":     false,
+						},
+						"flags": map[string]interface{}{
+							"type":        "array",
+							"items":       map[string]interface{}{"type": "string"},
+							"description": "Optional flags to pass to the command",
+						},
+					},
+					Required: []string{"account_name", "stream", "consumer", "until"},
+				},
+			},
+			Handler: c.consumerPauseHandler(),
+		},
+		{
+			Tool: mcp.Tool{
+				Name:        "consumer_resume",
+				Description: "Resumes message delivery of a paused consumer",
+				InputSchema: mcp.ToolInputSchema{
+					Type: "object",
+					Properties: map[string]interface{}{
+						"account_name": map[string]interface{}{
+							"type":        "string",
+							"description": "The NATS account to use",
+						},
+						"stream": map[string]interface{}{
+							"type":        "string",
+							"description": "Stream name",
+						},
+						"consumer": map[string]interface{}{
+							"type":        "string",
+							"description": "Consumer name",
+						},
+						"force": map[string]interface{}{
+							"type":        "boolean",
+							"description": "Act without confirmation",
+							"default":     false,
+						},
+						"flags": map[string]interface{}{
+							"type":        "array",
+							"items":       map[string]interface{}{"type": "string"},
+							"description": "Optional flags to pass to the command",
+						},
+					},
+					Required: []string{"account_name", "stream", "consumer"},
+				},
+			},
+			Handler: c.consumerResumeHandler(),
+		},
+		{
+			Tool: mcp.Tool{
+				Name:        "consumer_next",
+				Description: "Retrieves the next messages from a pull consumer, acknowledging them unless ack is false",
+				InputSchema: mcp.ToolInputSchema{
+					Type: "object",
+					Properties: map[string]interface{}{
+						"account_name": map[string]interface{}{
+							"type":        "string",
+							"description": "The NATS account to use",
+						},
+						"stream": map[string]interface{}{
+							"type":        "string",
+							"description": "Stream name",
+						},
+						"consumer": map[string]interface{}{
+							"type":        "string",
+							"description": "Consumer name",
+						},
+						"count": map[string]interface{}{
+							"type":        "integer",
+							"description": "Number of messages to retrieve",
+							"default":     1,
+						},
+						"ack": map[string]interface{}{
+							"type":        "boolean",
+							"description": "Acknowledge the retrieved messages",
+							"default":     true,
+						},
+						"wait": map[string]interface{}{
+							"type":        "string",
+							"description": "How long to wait for messages, e.g. 5s",
+						},
+						"flags": map[string]interface{}{
+							"type":        "array",
+							"items":       map[string]interface{}{"type": "string"},
+							"description": "Optional flags to pass to the command",
+						},
+					},
+					Required: []string{"account_name", "stream", "consumer"},
+				},
+			},
+			Handler: c.consumerNextHandler(),
+		},
+	}
+}
+
+// consumerArgs returns the account, stream and, if required, consumer
+// arguments of a consumer tool call.
+func consumerArgs(request mcp.CallToolRequest, consumerRequired bool) (string, string, string, error) {
+	accountName, ok := request.GetArguments()["account_name"].(string)
+	if !ok {
+		return "", "", "", fmt.Errorf("missing account_name")
+	}
+	stream, ok := request.GetArguments()["stream"].(string)
+	if !ok {
+		return "", "", "", fmt.Errorf("missing stream")
+	}
+	consumer, ok := request.GetArguments()["consumer"].(string)
+	if !ok && consumerRequired {
+		return "", "", "", fmt.Errorf("missing consumer")
+	}
+	return accountName, stream, consumer, nil
+}
+
+// runConsumerCommand runs a nats consumer subcommand with the given
+// arguments followed by any flags of the request.
+func (c *ConsumerTools) runConsumerCommand(ctx context.Context, request mcp.CallToolRequest, accountName string, args ...string) (*mcp.CallToolResult, error) {
+	executor, err := c.nats.GetExecutor(ctx, accountName)
+	if err != nil {
+		return nil, err
+	}
+
+	args = append([]string{"consumer"}, args...)
+	if flags := getFlags(request.GetArguments()); flags != nil {
+		args = append(args, flags...)
+	}
+
+	output, err := executor.ExecuteCommandContext(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultText(output), nil
+}
+
+// consumerSettingFlags converts the consumer settings of a request into
+// nats consumer add/edit flags.
+func consumerSettingFlags(arguments map[string]interface{}) []string {
+	var args []string
+	if description, ok := arguments["description"].(string); ok {
+		args = append(args, fmt.Sprintf("--description=%s", description))
+	}
+	if filters, ok := arguments["filter_subjects"].([]interface{}); ok {
+		for _, filter := range filters {
+			if strFilter, ok := filter.(string); ok {
+				args = append(args, fmt.Sprintf("--filter=%s", strFilter))
+			}
+		}
+	}
+	if ackWait, ok := arguments["ack_wait"].(string); ok {
+		args = append(args, fmt.Sprintf("--wait=%s", ackWait))
+	}
+	if maxDeliver, ok := arguments["max_deliver"].(float64); ok {
+		args = append(args, fmt.Sprintf("--max-deliver=%d", int(maxDeliver)))
+	}
+	if maxAckPending, ok := arguments["max_ack_pending"].(float64); ok {
+		args = append(args, fmt.Sprintf("--max-pending=%d", int(maxAckPending)))
+	}
+	if backoff, ok := arguments["backoff"].(string); ok {
+		args = append(args, fmt.Sprintf("--backoff=%s", backoff))
+	}
+	if steps, ok := arguments["backoff_steps"].(float64); ok {
+		args = append(args, fmt.Sprintf("--backoff-steps=%d", int(steps)))
+	}
+	if backoffMin, ok := arguments["backoff_min"].(string); ok {
+		args = append(args, fmt.Sprintf("--backoff-min=%s", backoffMin))
+	}
+	if backoffMax, ok := arguments["backoff_max"].(string); ok {
+		args = append(args, fmt.Sprintf("--backoff-max=%s", backoffMax))
+	}
+	if threshold, ok := arguments["inactive_threshold"].(string); ok {
+		args = append(args, fmt.Sprintf("--inactive-threshold=%s", threshold))
+	}
+	if heartbeat, ok := arguments["heartbeat"].(string); ok {
+		args = append(args, fmt.Sprintf("--heartbeat=%s", heartbeat))
+	}
+	if maxWaiting, ok := arguments["max_waiting"].(float64); ok {
+		args = append(args, fmt.Sprintf("--max-waiting=%d", int(maxWaiting)))
+	}
+	if maxPullBatch, ok := arguments["max_pull_batch"].(float64); ok {
+		args = append(args, fmt.Sprintf("--max-pull-batch=%d", int(maxPullBatch)))
+	}
+	if replicas, ok := arguments["replicas"].(float64); ok {
+		args = append(args, fmt.Sprintf("--replicas=%d", int(replicas)))
+	}
+	return args
+}
+
+// nats consumer ls
+// Args:
+//
+//	[<stream>]  Stream name
+func (c *ConsumerTools) consumerListHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		accountName, stream, _, err := consumerArgs(request, false)
+		if err != nil {
+			return nil, err
+		}
+		return c.runConsumerCommand(ctx, request, accountName, "ls", stream)
+	}
+}
+
+// nats consumer info
+// Args:
+//
+//	[<stream>]    Stream name
+//	[<consumer>]  Consumer name
+func (c *ConsumerTools) consumerInfoHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		accountName, stream, consumer, err := consumerArgs(request, true)
+		if err != nil {
+			return nil, err
+		}
+		return c.runConsumerCommand(ctx, request, accountName, "info", stream, consumer)
+	}
+}
+
+// nats consumer report
+// Args:
+//
+//	[<stream>]    Stream name
+//	[<consumer>]  Limit the report to a consumer
+func (c *ConsumerTools) consumerReportHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		accountName, stream, consumer, err := consumerArgs(request, false)
+		if err != nil {
+			return nil, err
+		}
+		args := []string{"report", stream}
+		if consumer != "" {
+			args = append(args, consumer)
+		}
+		return c.runConsumerCommand(ctx, request, accountName, args...)
+	}
+}
+
+// nats consumer add
+// Args:
+//
+//	[<stream>]    Stream name
+//	[<consumer>]  Consumer name
+func (c *ConsumerTools) consumerAddHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		accountName, stream, consumer, err := consumerArgs(request, true)
+		if err != nil {
+			return nil, err
+		}
+		arguments := request.GetArguments()
+
+		args := []string{"add", stream, consumer}
+
+		mode, _ := arguments["mode"].(string)
+		deliverSubject, _ := arguments["deliver_subject"].(string)
+		switch mode {
+		case "", "pull":
+			if deliverSubject != "" {
+				return nil, fmt.Errorf("deliver_subject requires push mode")
+			}
+			args = append(args, "--pull")
+		case "push":
+			if deliverSubject == "" {
+				return nil, fmt.Errorf("missing deliver_subject for push consumer")
+			}
+			args = append(args, fmt.Sprintf("--target=%s", deliverSubject))
+			if group, ok := arguments["deliver_group"].(string); ok {
+				args = append(args, fmt.Sprintf("--deliver-group=%s", group))
+			}
+			if flowControl, ok := arguments["flow_control"].(bool); ok && flowControl {
+				args = append(args, "--flow-control")
+			}
+		default:
+			return nil, fmt.Errorf("invalid mode %q (must be pull or push)", mode)
+		}
+
+		if deliverPolicy, ok := arguments["deliver_policy"].(string); ok {
+			args = append(args, fmt.Sprintf("--deliver=%s", deliverPolicy))
+		}
+		if ackPolicy, ok := arguments["ack_policy"].(string); ok {
+			args = append(args, fmt.Sprintf("--ack=%s", ackPolicy))
+		}
+		if replayPolicy, ok := arguments["replay_policy"].(string); ok {
+			args = append(args, fmt.Sprintf("--replay=%s", replayPolicy))
+		}
+		if headersOnly, ok := arguments["headers_only"].(bool); ok && headersOnly {
+			args = append(args, "--headers-only")
+		}
+		if memory, ok := arguments["memory"].(bool); ok && memory {
+			args = append(args, "--memory")
+		}
+		args = append(args, consumerSettingFlags(arguments)...)
+
+		// Settings that were not given take their defaults instead of
+		// prompting, as there is no terminal to answer on.
+		args = append(args, "--defaults")
+
+		return c.runConsumerCommand(ctx, request, accountName, args...)
+	}
+}
+
+// nats consumer edit
+// Args:
+//
+//	[<stream>]    Stream name
+//	[<consumer>]  Consumer name
+func (c *ConsumerTools) consumerEditHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		accountName, stream, consumer, err := consumerArgs(request, true)
+		if err != nil {
+			return nil, err
+		}
+		arguments := request.GetArguments()
+
+		args := []string{"edit", stream, consumer}
+		args = append(args, consumerSettingFlags(arguments)...)
+		if dryRun, ok := arguments["dry_run"].(bool); ok && dryRun {
+			args = append(args, "--dry-run")
+		}
+		if force, ok := arguments["force"].(bool); ok && force {
+			args = append(args, "--force")
+		}
+
+		return c.runConsumerCommand(ctx, request, accountName, args...)
+	}
+}
+
+// nats consumer rm
+// Args:
+//
+//	[<stream>]    Stream name
+//	[<consumer>]  Consumer name
+func (c *ConsumerTools) consumerRmHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		accountName, stream, consumer, err := consumerArgs(request, true)
+		if err != nil {
+			return nil, err
+		}
+
+		args := []string{"rm", stream, consumer}
+		if force, ok := request.GetArguments()["force"].(bool); ok && force {
+			args = append(args, "--force")
+		}
+
+		return c.runConsumerCommand(ctx, request, accountName, args...)
+	}
+}
+
+// nats consumer pause
+// Args:
+//
+//	[<stream>]    Stream name
+//	[<consumer>]  Consumer name
+//	[<until>]     Pause until a specific time
+func (c *ConsumerTools) consumerPauseHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		accountName, stream, consumer, err := consumerArgs(request, true)
+		if err != nil {
+			return nil, err
+		}
+
+		until, ok := request.GetArguments()["until"].(string)
+		if !ok {
+			return nil, fmt.Errorf("missing until")
+		}
+
+		args := []string{"pause", stream, consumer, until}
+		if force, ok := request.GetArguments()["force"].(bool); ok && force {
+			args = append(args, "--force")
+		}
+
+		return c.runConsumerCommand(ctx, request, accountName, args...)
+	}
+}
+
+// nats consumer resume
+// Args:
+//
+//	[<stream>]    Stream name
+//	[<consumer>]  Consumer name
+func (c *ConsumerTools) consumerResumeHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		accountName, stream, consumer, err := consumerArgs(request, true)
+		if err != nil {
+			return nil, err
+		}
+
+		args := []string{"resume", stream, consumer}
+		if force, ok := request.GetArguments()["force"].(bool); ok && force {
+			args = append(args, "--force")
+		}
+
+		return c.runConsumerCommand(ctx, request, accountName, args...)
+	}
+}
+
+// nats consumer next
+// Args:
+//
+//	<stream>    Stream name
+//	<consumer>  Consumer name
+func (c *ConsumerTools) consumerNextHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		accountName, stream, consumer, err := consumerArgs(request, true)
+		if err != nil {
+			return nil, err
+		}
+		arguments := request.GetArguments()
+
+		args := []string{"next", stream, consumer}
+		if count, ok := arguments["count"].(float64); ok {
+			args = append(args, fmt.Sprintf("--count=%d", int(count)))
+		}
+		if ack, ok := arguments["ack"].(bool); ok && !ack {
+			args = append(args, "--no-ack")
+		}
+		if wait, ok := arguments["wait"].(string); ok {
+			args = append(args, fmt.Sprintf("--wait=%s", wait))
+		}
+
+		return c.runConsumerCommand(ctx, request, accountName, args...)
+	}
+}
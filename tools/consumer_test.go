@@ -0,0 +1,208 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/sinadarbouy/mcp-nats/internal/logger"
+	"github.com/sinadarbouy/mcp-nats/test/utils/containers"
+	"github.com/sinadarbouy/mcp-nats/tools/common"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestConsumerTools_commandArguments(t *testing.T) {
+	run := testCLIToolCaller(t, (*NATSServerTools).ConsumerTools, "ORDERS")
+	call := func(tool string, args map[string]any) ([]string, error) {
+		t.Helper()
+		out, err := run(tool, args)
+		if err != nil {
+			return nil, err
+		}
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		// Skip the connection arguments added by the auth strategy.
+		for i, line := range lines {
+			if line == "consumer" {
+				return lines[i:], nil
+			}
+		}
+		t.Fatalf("%s: no consumer command in %q", tool, lines)
+		return nil, nil
+	}
+	expect := func(tool string, args map[string]any, want ...string) {
+		t.Helper()
+		got, err := call(tool, args)
+		if err != nil {
+			t.Fatalf("%s: %v", tool, err)
+		}
+		if strings.Join(got, " ") != strings.Join(want, " ") {
+			t.Fatalf("%s ran\n  %q\nwant\n  %q", tool, got, want)
+		}
+	}
+
+	expect("consumer_add", map[string]any{
+		"consumer":        "PROCESSOR",
+		"filter_subjects": []any{"orders.new", "orders.paid"},
+		"ack_policy":      "explicit",
+		"backoff":         "linear",
+		"backoff_steps":   float64(5),
+		"max_deliver":     float64(10),
+	},
+		"consumer", "add", "ORDERS", "PROCESSOR", "--pull", "--ack=explicit",
+		"--filter=orders.new", "--filter=orders.paid", "--max-deliver=10",
+		"--backoff=linear", "--backoff-steps=5", "--defaults",
+	)
+	expect("consumer_add", map[string]any{
+		"consumer":        "MONITOR",
+		"mode":            "push",
+		"deliver_subject": "monitor.orders",
+		"deliver_group":   "monitors",
+		"flow_control":    true,
+		"heartbeat":       "5s",
+	},
+		"consumer", "add", "ORDERS", "MONITOR", "--target=monitor.orders",
+		"--deliver-group=monitors", "--flow-control", "--heartbeat=5s", "--defaults",
+	)
+	expect("consumer_edit", map[string]any{"consumer": "PROCESSOR", "max_ack_pending": float64(100), "dry_run": true},
+		"consumer", "edit", "ORDERS", "PROCESSOR", "--max-pending=100", "--dry-run")
+	expect("consumer_pause", map[string]any{"consumer": "PROCESSOR", "until": "1h", "force": true},
+		"consumer", "pause", "ORDERS", "PROCESSOR", "1h", "--force")
+	expect("consumer_next", map[string]any{"consumer": "PROCESSOR", "count": float64(3), "ack": false},
+		"consumer", "next", "ORDERS", "PROCESSOR", "--count=3", "--no-ack")
+	expect("consumer_report", map[string]any{}, "consumer", "report", "ORDERS")
+
+	for _, tc := range []struct {
+		args map[string]any
+		want string
+	}{
+		{map[string]any{"consumer": "C", "mode": "push"}, "missing deliver_subject"},
+		{map[string]any{"consumer": "C", "deliver_subject": "out"}, "requires push mode"},
+		{map[string]any{"consumer": "C", "mode": "fetch"}, "invalid mode"},
+		{map[string]any{}, "missing consumer"},
+	} {
+		if _, err := call("consumer_add", tc.args); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("consumer_add %v error = %v, want %q", tc.args, err, tc.want)
+		}
+	}
+}
+
+// containerSuite runs tools against a NATS container with JetStream and
+// checks their effect with a direct connection.
+type containerSuite struct {
+	suite.Suite
+	ctx           context.Context
+	natsContainer *containers.NatsContainer
+	natsTools     *NATSServerTools
+	nc            *nats.Conn
+	js            jetstream.JetStream
+}
+
+func (s *containerSuite) SetupSuite() {
+	logger.Initialize(logger.Config{Level: logger.LevelError})
+	s.ctx = context.Background()
+	s.natsContainer = containers.NewNatsContainer(s.ctx, s.T())
+	natsURL := fmt.Sprintf("nats://%s:%s", s.natsContainer.Host, s.natsContainer.Port.Port())
+
+	var err error
+	s.natsTools, err = NewNATSServerToolsWithConnection(common.Connection{Name: "default", URL: natsURL, NoAuthentication: true})
+	s.Require().NoError(err)
+	s.nc, err = nats.Connect(natsURL)
+	s.Require().NoError(err)
+	s.js, err = jetstream.New(s.nc)
+	s.Require().NoError(err)
+}
+
+func (s *containerSuite) TearDownSuite() {
+	if s.nc != nil {
+		s.nc.Close()
+	}
+	if s.natsContainer != nil {
+		_ = s.natsContainer.Container.Terminate(s.ctx)
+	}
+}
+
+// call runs the named tool of category and returns its output.
+func (s *containerSuite) call(category func(*NATSServerTools) ToolCategory, name string, args map[string]any) (string, error) {
+	args["account_name"] = "A"
+	for _, tool := range category(s.natsTools).GetTools() {
+		if tool.Tool.Name != name {
+			continue
+		}
+		result, err := s.natsTools.wrapHandler(tool.Tool, tool.Handler)(s.ctx, mcp.CallToolRequest{
+			Params: mcp.CallToolParams{Name: name, Arguments: args},
+		})
+		if err != nil {
+			return "", err
+		}
+		return result.Content[0].(mcp.TextContent).Text, nil
+	}
+	s.FailNow("no " + name + " tool")
+	return "", nil
+}
+
+// ConsumerTestSuite runs the consumer tools against a NATS container.
+type ConsumerTestSuite struct {
+	containerSuite
+}
+
+func TestConsumerSuite(t *testing.T) {
+	suite.Run(t, new(ConsumerTestSuite))
+}
+
+func (s *ConsumerTestSuite) SetupTest() {
+	_, err := s.js.CreateOrUpdateStream(s.ctx, jetstream.StreamConfig{Name: "ORDERS", Subjects: []string{"orders.>"}})
+	s.Require().NoError(err)
+}
+
+func (s *ConsumerTestSuite) TearDownTest() {
+	_ = s.js.DeleteStream(s.ctx, "ORDERS")
+}
+
+func (s *ConsumerTestSuite) TestConsumerAddAndRm() {
+	_, err := s.call((*NATSServerTools).ConsumerTools, "consumer_add", map[string]any{
+		"stream":          "ORDERS",
+		"consumer":        "PROCESSOR",
+		"filter_subjects": []any{"orders.new"},
+		"ack_policy":      "explicit",
+		"max_deliver":     float64(10),
+	})
+	s.Require().NoError(err, "consumer_add should create the consumer")
+
+	consumer, err := s.js.Consumer(s.ctx, "ORDERS", "PROCESSOR")
+	s.Require().NoError(err, "the consumer should exist after consumer_add")
+	config := consumer.CachedInfo().Config
+	s.Assert().Equal("orders.new", config.FilterSubject)
+	s.Assert().Equal(jetstream.AckExplicitPolicy, config.AckPolicy)
+	s.Assert().Equal(10, config.MaxDeliver)
+
+	_, err = s.call((*NATSServerTools).ConsumerTools, "consumer_rm", map[string]any{
+		"stream":   "ORDERS",
+		"consumer": "PROCESSOR",
+		"force":    true,
+	})
+	s.Require().NoError(err, "consumer_rm should remove the consumer")
+	_, err = s.js.Consumer(s.ctx, "ORDERS", "PROCESSOR")
+	s.Assert().ErrorIs(err, jetstream.ErrConsumerNotFound)
+}
+
+func (s *ConsumerTestSuite) TestConsumerAddPushConsumer() {
+	_, err := s.call((*NATSServerTools).ConsumerTools, "consumer_add", map[string]any{
+		"stream":          "ORDERS",
+		"consumer":        "MONITOR",
+		"mode":            "push",
+		"deliver_subject": "monitor.orders",
+		"heartbeat":       "5s",
+		"flow_control":    true,
+	})
+	s.Require().NoError(err, "consumer_add should create the push consumer")
+
+	consumer, err := s.js.Consumer(s.ctx, "ORDERS", "MONITOR")
+	s.Require().NoError(err, "the consumer should exist after consumer_add")
+	config := consumer.CachedInfo().Config
+	s.Assert().Equal("monitor.orders", config.DeliverSubject)
+	s.Assert().True(config.FlowControl)
+}
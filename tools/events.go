@@ -0,0 +1,550 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/nats-io/nats.go"
+)
+
+// Limits of the events tool.
+const (
+	defaultEventsCount    = 100
+	maxEventsCount        = 1000
+	defaultEventsDuration = 10 * time.Second
+	maxEventsDuration     = 5 * time.Minute
+	// eventsBuffer holds the events received while others are decoded,
+	// including those the filters discard.
+	eventsBuffer = 8192
+)
+
+// stopMaxEvents is reported when an events capture reached max_events.
+const stopMaxEvents = "max_events"
+
+// eventSource is a group of event subjects the events tool can listen to.
+type eventSource struct {
+	name        string
+	subjects    []string
+	description string
+}
+
+// eventSources are the event subjects by source, in the order they are
+// subscribed. %s in connection subjects is replaced by the account filter.
+var eventSources = []eventSource{
+	{
+		name:        "advisories",
+		subjects:    []string{"$JS.EVENT.ADVISORY.>"},
+		description: "JetStream advisories of the account: max deliveries, terminations, naks, stream and consumer actions, leader elections, quorum loss and API audits",
+	},
+	{
+		name:        "metrics",
+		subjects:    []string{"$JS.EVENT.METRIC.>"},
+		description: "JetStream metrics of the account, such as sampled consumer acknowledgements",
+	},
+	{
+		name:        "connections",
+		subjects:    []string{"$SYS.ACCOUNT.%s.CONNECT", "$SYS.ACCOUNT.%s.DISCONNECT", "$SYS.ACCOUNT.%s.LEAFNODE.CONNECT"},
+		description: "Client connects and disconnects and leafnode connects (system account)",
+	},
+	{
+		name:        "auth",
+		subjects:    []string{"$SYS.SERVER.*.CLIENT.AUTH.ERR"},
+		description: "Authentication and authorization failures (system account)",
+	},
+	{
+		name:        "server",
+		subjects:    []string{"$SYS.SERVER.*.SHUTDOWN", "$SYS.SERVER.*.LAMEDUCK"},
+		description: "Servers shutting down or entering lame duck mode (system account)",
+	},
+}
+
+// eventKinds are the kinds of the decoded event schemas.
+var eventKinds = []string{
+	"max_deliver", "terminated", "nak",
+	"stream_action", "consumer_action",
+	"stream_leader_elected", "consumer_leader_elected",
+	"stream_quorum_lost", "consumer_quorum_lost",
+	"api_audit", "consumer_ack",
+	"client_connect", "client_disconnect", "leafnode_connect",
+	"auth_error", "server_shutdown", "server_lameduck",
+}
+
+// eventsDescription lists the sources for the events tool description.
+func eventsDescription() string {
+	var sources []string
+	for _, source := range eventSources {
+		sources = append(sources, source.name+" ("+source.description+")")
+	}
+	return "Captures NATS events for a bounded time and decodes the known advisory schemas into structured entries, " +
+		"e.g. to find consumers that hit max deliveries or clients that just disconnected. Sources: " + strings.Join(sources, "; ")
+}
+
+// eventsTool returns the tool capturing JetStream advisories and system events.
+func (s *SubscribeTools) eventsTool() Tool {
+	props := map[string]interface{}{
+		"sources": map[string]interface{}{
+			"type":        "array",
+			"items":       map[string]interface{}{"type": "string", "enum": eventSourceNames()},
+			"description": "Event sources to listen to, defaults to all of them",
+		},
+		"kinds": map[string]interface{}{
+			"type":        "array",
+			"items":       map[string]interface{}{"type": "string", "enum": eventKinds},
+			"description": "Only return events of these kinds",
+		},
+		"stream": map[string]interface{}{
+			"type":        "string",
+			"description": "Only return events about this stream",
+		},
+		"consumer": map[string]interface{}{
+			"type":        "string",
+			"description": "Only return events about this consumer",
+		},
+		"account": map[string]interface{}{
+			"type":        "string",
+			"description": "Only listen to connection events of this account",
+		},
+		"max_events": map[string]interface{}{
+			"type":        "integer",
+			"description": fmt.Sprintf("Stop after this many events (at most %d)", maxEventsCount),
+			"default":     defaultEventsCount,
+		},
+		"duration": map[string]interface{}{
+			"type":        "string",
+			"description": fmt.Sprintf("Stop after this long, e.g. 1m (at most %s)", maxEventsDuration),
+			"default":     defaultEventsDuration.String(),
+		},
+	}
+	var required []string
+	if s.nats.accountNameRequired() {
+		props["account_name"] = map[string]interface{}{
+			"type":        "string",
+			"description": "The NATS account to use (required for credentials-based authentication)",
+		}
+		required = []string{"account_name"}
+	}
+
+	return Tool{
+		Tool: mcp.Tool{
+			Name:        "events",
+			Description: eventsDescription(),
+			InputSchema: mcp.ToolInputSchema{Type: "object", Properties: props, Required: required},
+		},
+		Handler: s.eventsHandler(),
+	}
+}
+
+// eventsOptions are the validated arguments of an events capture.
+type eventsOptions struct {
+	subjects  []string
+	kinds     []string
+	stream    string
+	consumer  string
+	maxEvents int
+	duration  time.Duration
+}
+
+// eventsOptionsFromArgs reads and validates the arguments of an events capture.
+func eventsOptionsFromArgs(arguments map[string]interface{}) (eventsOptions, error) {
+	opts := eventsOptions{maxEvents: defaultEventsCount, duration: defaultEventsDuration}
+	opts.stream, _ = arguments["stream"].(string)
+	opts.consumer, _ = arguments["consumer"].(string)
+
+	account, _ := arguments["account"].(string)
+	if account == "" {
+		account = "*"
+	} else if strings.ContainsAny(account, ".*> ") {
+		return opts, fmt.Errorf("invalid account %q", account)
+	}
+
+	selected := map[string]bool{}
+	values, _ := arguments["sources"].([]interface{})
+	for _, value := range values {
+		name, _ := value.(string)
+		if !containsString(eventSourceNames(), name) {
+			return opts, fmt.Errorf("invalid source %q, must be one of %s", name, strings.Join(eventSourceNames(), ", "))
+		}
+		selected[name] = true
+	}
+	for _, source := range eventSources {
+		if len(selected) > 0 && !selected[source.name] {
+			continue
+		}
+		for _, subject := range source.subjects {
+			if strings.Contains(subject, "%s") {
+				subject = fmt.Sprintf(subject, account)
+			}
+			opts.subjects = append(opts.subjects, subject)
+		}
+	}
+
+	values, _ = arguments["kinds"].([]interface{})
+	for _, value := range values {
+		kind, _ := value.(string)
+		if !containsString(eventKinds, kind) {
+			return opts, fmt.Errorf("invalid kind %q", kind)
+		}
+		opts.kinds = append(opts.kinds, kind)
+	}
+
+	if n, ok := arguments["max_events"].(float64); ok {
+		if n < 1 || n > maxEventsCount {
+			return opts, fmt.Errorf("max_events must be between 1 and %d", maxEventsCount)
+		}
+		opts.maxEvents = int(n)
+	}
+	if d, ok := arguments["duration"].(string); ok && d != "" {
+		duration, err := time.ParseDuration(d)
+		if err != nil {
+			return opts, fmt.Errorf("invalid duration: %w", err)
+		}
+		if duration <= 0 || duration > maxEventsDuration {
+			return opts, fmt.Errorf("duration must be positive and at most %s", maxEventsDuration)
+		}
+		opts.duration = duration
+	}
+	return opts, nil
+}
+
+func eventSourceNames() []string {
+	names := make([]string, 0, len(eventSources))
+	for _, source := range eventSources {
+		names = append(names, source.name)
+	}
+	return names
+}
+
+// matches reports whether a decoded event passes the filters.
+func (o eventsOptions) matches(event eventEntry) bool {
+	if len(o.kinds) > 0 && !containsString(o.kinds, event.Kind) {
+		return false
+	}
+	if o.stream != "" && event.Stream != o.stream {
+		return false
+	}
+	if o.consumer != "" && event.Consumer != o.consumer {
+		return false
+	}
+	return true
+}
+
+// eventsResult is the JSON result of the events tool.
+type eventsResult struct {
+	Subjects []string `json:"subjects"`
+	Stopped  string   `json:"stopped"`
+	// Received counts every event, including those the filters discarded.
+	Received int `json:"received"`
+	Count    int `json:"count"`
+	// Dropped counts events lost because they arrived faster than decoded.
+	Dropped int          `json:"dropped,omitempty"`
+	Warning string       `json:"warning,omitempty"`
+	Events  []eventEntry `json:"events"`
+}
+
+func (s *SubscribeTools) eventsHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		opts, err := eventsOptionsFromArgs(request.GetArguments())
+		if err != nil {
+			return nil, err
+		}
+
+		nc, err := s.nats.connect(ctx, request.GetArguments())
+		if err != nil {
+			return nil, err
+		}
+		defer nc.Close()
+
+		msgs := make(chan *nats.Msg, eventsBuffer)
+		var subs []*nats.Subscription
+		defer func() {
+			for _, sub := range subs {
+				_ = sub.Unsubscribe()
+			}
+		}()
+		for _, subject := range opts.subjects {
+			sub, err := nc.ChanSubscribe(subject, msgs)
+			if err != nil {
+				return nil, fmt.Errorf("failed to subscribe to %s: %w", subject, err)
+			}
+			subs = append(subs, sub)
+		}
+		if err := nc.Flush(); err != nil {
+			return nil, fmt.Errorf("failed to subscribe to events: %w", err)
+		}
+
+		result := collectEvents(ctx, msgs, opts)
+		result.Subjects = opts.subjects
+		// Denied subscriptions do not fail, the server reports them
+		// asynchronously before answering the flush.
+		if err := nc.LastError(); errors.Is(err, nats.ErrPermissionViolation) {
+			result.Warning = err.Error()
+		}
+		for _, sub := range subs {
+			if dropped, err := sub.Dropped(); err == nil {
+				result.Dropped += dropped
+			}
+		}
+		return jsonResult(result)
+	}
+}
+
+// collectEvents decodes events from msgs until max_events matching events
+// were collected, the duration elapsed or ctx is done.
+func collectEvents(ctx context.Context, msgs <-chan *nats.Msg, opts eventsOptions) eventsResult {
+	result := eventsResult{Events: []eventEntry{}}
+	timer := time.NewTimer(opts.duration)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			result.Stopped = stopCancelled
+			return result
+		case <-timer.C:
+			result.Stopped = stopDuration
+			return result
+		case msg := <-msgs:
+			result.Received++
+			event := decodeEvent(msg)
+			if !opts.matches(event) {
+				continue
+			}
+			result.Events = append(result.Events, event)
+			result.Count++
+			if result.Count >= opts.maxEvents {
+				result.Stopped = stopMaxEvents
+				return result
+			}
+		}
+	}
+}
+
+// eventEntry is a decoded event. Only the fields of its kind are set.
+type eventEntry struct {
+	Subject     string         `json:"subject"`
+	Kind        string         `json:"kind"`
+	Type        string         `json:"type,omitempty"`
+	Time        time.Time      `json:"time"`
+	Summary     string         `json:"summary,omitempty"`
+	Server      string         `json:"server,omitempty"`
+	Domain      string         `json:"domain,omitempty"`
+	Account     string         `json:"account,omitempty"`
+	Stream      string         `json:"stream,omitempty"`
+	Consumer    string         `json:"consumer,omitempty"`
+	StreamSeq   uint64         `json:"stream_seq,omitempty"`
+	ConsumerSeq uint64         `json:"consumer_seq,omitempty"`
+	Deliveries  uint64         `json:"deliveries,omitempty"`
+	Action      string         `json:"action,omitempty"`
+	Reason      string         `json:"reason,omitempty"`
+	Leader      string         `json:"leader,omitempty"`
+	Replicas    []eventReplica `json:"replicas,omitempty"`
+	AckTimeMS   float64        `json:"ack_time_ms,omitempty"`
+	APISubject  string         `json:"api_subject,omitempty"`
+	Client      *eventClient   `json:"client,omitempty"`
+	Sent        *eventTraffic  `json:"sent,omitempty"`
+	Received    *eventTraffic  `json:"received,omitempty"`
+	// Event is the raw event when its schema is not known, Payload the
+	// message when it is not JSON.
+	Event   json.RawMessage `json:"event,omitempty"`
+	Payload string          `json:"payload,omitempty"`
+}
+
+// eventReplica is a stream or consumer peer in a cluster advisory.
+type eventReplica struct {
+	Name    string `json:"name"`
+	Current bool   `json:"current"`
+	Offline bool   `json:"offline,omitempty"`
+	Lag     uint64 `json:"lag,omitempty"`
+}
+
+// eventClient is the client an event is about, with the server's field names.
+type eventClient struct {
+	ID      uint64 `json:"id,omitempty"`
+	Kind    string `json:"kind,omitempty"`
+	Account string `json:"acc,omitempty"`
+	User    string `json:"user,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Host    string `json:"host,omitempty"`
+	Lang    string `json:"lang,omitempty"`
+	Version string `json:"ver,omitempty"`
+	Server  string `json:"server,omitempty"`
+	Cluster string `json:"cluster,omitempty"`
+}
+
+// eventTraffic counts the messages and bytes of a closed connection.
+type eventTraffic struct {
+	Msgs  int64 `json:"msgs"`
+	Bytes int64 `json:"bytes"`
+}
+
+// eventServer is the server info attached to system events.
+type eventServer struct {
+	Name string    `json:"name"`
+	ID   string    `json:"id"`
+	Time time.Time `json:"time"`
+}
+
+// advisory holds the fields of every decoded event schema.
+type advisory struct {
+	Type        string         `json:"type"`
+	Time        time.Time      `json:"timestamp"`
+	Domain      string         `json:"domain"`
+	Stream      string         `json:"stream"`
+	Consumer    string         `json:"consumer"`
+	StreamSeq   uint64         `json:"stream_seq"`
+	ConsumerSeq uint64         `json:"consumer_seq"`
+	Deliveries  uint64         `json:"deliveries"`
+	Action      string         `json:"action"`
+	Reason      string         `json:"reason"`
+	Leader      string         `json:"leader"`
+	Replicas    []eventReplica `json:"replicas"`
+	AckTime     int64          `json:"ack_time"`
+	Subject     string         `json:"subject"`
+	Account     string         `json:"account"`
+	// Server is a server info object in system events and the server name
+	// in API audits.
+	Server   json.RawMessage `json:"server"`
+	Client   *eventClient    `json:"client"`
+	Sent     *eventTraffic   `json:"sent"`
+	Received *eventTraffic   `json:"received"`
+}
+
+// decodeEvent decodes msg into an event entry. Events that cannot be
+// decoded keep their raw payload.
+func decodeEvent(msg *nats.Msg) eventEntry {
+	event := eventEntry{Subject: msg.Subject, Time: time.Now().UTC()}
+
+	switch {
+	case strings.HasSuffix(msg.Subject, ".SHUTDOWN"), strings.HasSuffix(msg.Subject, ".LAMEDUCK"):
+		event.Kind = "server_shutdown"
+		if strings.HasSuffix(msg.Subject, ".LAMEDUCK") {
+			event.Kind = "server_lameduck"
+		}
+		var info eventServer
+		if json.Unmarshal(msg.Data, &info) == nil {
+			event.Server = info.Name
+			if !info.Time.IsZero() {
+				event.Time = info.Time
+			}
+		}
+		event.Summary = eventSummary(event)
+		return event
+	}
+
+	var adv advisory
+	if err := json.Unmarshal(msg.Data, &adv); err != nil {
+		event.Kind = "unknown"
+		event.Payload = sampleMessage(msg).Payload
+		return event
+	}
+	event.Type = adv.Type
+	event.Kind = adv.Type[strings.LastIndex(adv.Type, ".")+1:]
+	if strings.HasSuffix(msg.Subject, ".CLIENT.AUTH.ERR") {
+		event.Kind = "auth_error"
+	}
+	if !containsString(eventKinds, event.Kind) {
+		event.Kind = "unknown"
+		event.Event = json.RawMessage(msg.Data)
+		return event
+	}
+	if !adv.Time.IsZero() {
+		event.Time = adv.Time
+	}
+
+	var serverInfo eventServer
+	if json.Unmarshal(adv.Server, &serverInfo) == nil {
+		event.Server = serverInfo.Name
+	} else {
+		_ = json.Unmarshal(adv.Server, &event.Server)
+	}
+	event.Domain = adv.Domain
+	event.Account = adv.Account
+	event.Stream = adv.Stream
+	event.Consumer = adv.Consumer
+	event.StreamSeq = adv.StreamSeq
+	event.ConsumerSeq = adv.ConsumerSeq
+	event.Deliveries = adv.Deliveries
+	event.Action = adv.Action
+	event.Reason = adv.Reason
+	event.Leader = adv.Leader
+	event.Replicas = adv.Replicas
+	event.AckTimeMS = float64(adv.AckTime) / float64(time.Millisecond)
+	event.APISubject = adv.Subject
+	event.Client = adv.Client
+	event.Sent = adv.Sent
+	event.Received = adv.Received
+	if event.Account == "" && event.Client != nil {
+		event.Account = event.Client.Account
+	}
+	event.Summary = eventSummary(event)
+	return event
+}
+
+// eventSummary describes an event in one sentence.
+func eventSummary(e eventEntry) string {
+	target := e.Stream
+	if e.Consumer != "" {
+		target += " > " + e.Consumer
+	}
+	switch e.Kind {
+	case "max_deliver":
+		return fmt.Sprintf("message %d on %s reached the maximum of %d deliveries", e.StreamSeq, target, e.Deliveries)
+	case "terminated":
+		return strings.TrimSuffix(fmt.Sprintf("message %d on %s was terminated after %d deliveries: %s", e.StreamSeq, target, e.Deliveries, e.Reason), ": ")
+	case "nak":
+		return fmt.Sprintf("message %d on %s was negatively acknowledged after %d deliveries", e.StreamSeq, target, e.Deliveries)
+	case "stream_action":
+		return fmt.Sprintf("stream %s: %s", target, e.Action)
+	case "consumer_action":
+		return fmt.Sprintf("consumer %s: %s", target, e.Action)
+	case "stream_leader_elected":
+		return fmt.Sprintf("%s was elected leader of stream %s", e.Leader, target)
+	case "consumer_leader_elected":
+		return fmt.Sprintf("%s was elected leader of consumer %s", e.Leader, target)
+	case "stream_quorum_lost":
+		return fmt.Sprintf("stream %s lost quorum", target)
+	case "consumer_quorum_lost":
+		return fmt.Sprintf("consumer %s lost quorum", target)
+	case "api_audit":
+		return fmt.Sprintf("JetStream API call to %s by %s", e.APISubject, clientSummary(e.Client))
+	case "consumer_ack":
+		return fmt.Sprintf("message %d on %s was acknowledged after %.3fms", e.StreamSeq, target, e.AckTimeMS)
+	case "client_connect":
+		return fmt.Sprintf("%s connected to account %s on %s", clientSummary(e.Client), e.Account, e.Server)
+	case "client_disconnect":
+		return strings.TrimSuffix(fmt.Sprintf("%s disconnected from account %s on %s: %s", clientSummary(e.Client), e.Account, e.Server, e.Reason), ": ")
+	case "leafnode_connect":
+		return fmt.Sprintf("leafnode connected to account %s on %s", e.Account, e.Server)
+	case "auth_error":
+		return strings.TrimSuffix(fmt.Sprintf("%s failed to authenticate on %s: %s", clientSummary(e.Client), e.Server, e.Reason), ": ")
+	case "server_shutdown":
+		return fmt.Sprintf("server %s is shutting down", e.Server)
+	case "server_lameduck":
+		return fmt.Sprintf("server %s entered lame duck mode", e.Server)
+	}
+	return ""
+}
+
+// clientSummary names a client by its connection ID, name, user and host.
+func clientSummary(c *eventClient) string {
+	if c == nil {
+		return "unknown client"
+	}
+	summary := fmt.Sprintf("client %d", c.ID)
+	if c.Name != "" {
+		summary += fmt.Sprintf(" (%s)", c.Name)
+	}
+	if c.User != "" {
+		summary += " user " + c.User
+	}
+	if c.Host != "" {
+		summary += " from " + c.Host
+	}
+	return summary
+}
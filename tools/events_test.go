@@ -0,0 +1,143 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// repeatUntil runs action every 50ms until the returned stop function is
+// called, since the events tool subscribes only once it is called.
+func repeatUntil(action func()) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(50 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				action()
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+func TestEventsTool_advisories(t *testing.T) {
+	url, _ := startTestServer(t)
+	nc, err := nats.Connect(url)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(nc.Close)
+	js, err := jetstream.New(nc)
+	if err != nil {
+		t.Fatalf("jetstream: %v", err)
+	}
+
+	stop := repeatUntil(func() {
+		for _, name := range []string{"OTHER", "ORDERS"} {
+			_, _ = js.CreateStream(context.Background(), jetstream.StreamConfig{Name: name, Storage: jetstream.MemoryStorage})
+			_ = js.DeleteStream(context.Background(), name)
+		}
+	})
+	events := testToolHandler(t, url, (*NATSServerTools).SubscribeTools, "events")
+	out, err := events(map[string]any{
+		"sources":    []any{"advisories"},
+		"kinds":      []any{"stream_action"},
+		"stream":     "ORDERS",
+		"max_events": float64(2),
+		"duration":   "5s",
+	})
+	stop()
+	if err != nil {
+		t.Fatalf("events: %v", err)
+	}
+
+	var result eventsResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid result %s: %v", out, err)
+	}
+	if strings.Join(result.Subjects, " ") != "$JS.EVENT.ADVISORY.>" || result.Stopped != stopMaxEvents || result.Count != 2 || result.Received <= 2 {
+		t.Fatalf("expected the API audits and OTHER events to be filtered out: %s", out)
+	}
+	for _, event := range result.Events {
+		if event.Kind != "stream_action" || event.Stream != "ORDERS" || event.Time.IsZero() ||
+			(event.Summary != "stream ORDERS: create" && event.Summary != "stream ORDERS: delete") {
+			t.Fatalf("unexpected stream_action event: %+v", event)
+		}
+	}
+
+	if _, err := events(map[string]any{"sources": []any{"logs"}}); err == nil || !strings.Contains(err.Error(), "invalid source") {
+		t.Fatalf("expected an unknown source to be rejected, got %v", err)
+	}
+}
+
+func TestEventsTool_connections(t *testing.T) {
+	url, sysURL := startTestServer(t)
+	stop := repeatUntil(func() {
+		if nc, err := nats.Connect(url, nats.Name("worker")); err == nil {
+			nc.Close()
+		}
+	})
+	events := testToolHandler(t, sysURL, (*NATSServerTools).SubscribeTools, "events")
+	out, err := events(map[string]any{
+		"sources":    []any{"connections"},
+		"kinds":      []any{"client_disconnect"},
+		"account":    "APP",
+		"max_events": float64(1),
+		"duration":   "5s",
+	})
+	stop()
+	if err != nil {
+		t.Fatalf("events: %v", err)
+	}
+
+	var result eventsResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid result %s: %v", out, err)
+	}
+	if strings.Join(result.Subjects, " ") != "$SYS.ACCOUNT.APP.CONNECT $SYS.ACCOUNT.APP.DISCONNECT $SYS.ACCOUNT.APP.LEAFNODE.CONNECT" {
+		t.Fatalf("unexpected subjects %v", result.Subjects)
+	}
+	if result.Stopped != stopMaxEvents || result.Count != 1 {
+		t.Fatalf("expected one disconnect: %s", out)
+	}
+	disconnect := result.Events[0]
+	if disconnect.Kind != "client_disconnect" || disconnect.Account != "APP" || disconnect.Server != "test" ||
+		!strings.HasSuffix(disconnect.Summary, "(worker) user app from 127.0.0.1 disconnected from account APP on test: Client Closed") {
+		t.Fatalf("unexpected client_disconnect event: %+v", disconnect)
+	}
+}
+
+func TestDecodeEvent_systemAndUnknown(t *testing.T) {
+	for _, tc := range []struct {
+		subject, data, kind, server, summary string
+	}{
+		{"$SYS.SERVER.NABC.CLIENT.AUTH.ERR",
+			`{"type":"io.nats.server.advisory.v1.client_disconnect","server":{"name":"n2"},"client":{"id":9,"user":"eve"},"reason":"Authentication Failure"}`,
+			"auth_error", "n2", "client 9 user eve failed to authenticate on n2: Authentication Failure"},
+		{"$JS.EVENT.ADVISORY.CONSUMER.MAX_DELIVERIES.ORDERS.PROCESSOR",
+			`{"type":"io.nats.jetstream.advisory.v1.max_deliver","timestamp":"2026-10-18T10:00:00Z","server":"n1","stream":"ORDERS","consumer":"PROCESSOR","stream_seq":42,"deliveries":5}`,
+			"max_deliver", "n1", "message 42 on ORDERS > PROCESSOR reached the maximum of 5 deliveries"},
+		{"$SYS.SERVER.NABC.LAMEDUCK", `{"name":"n3","id":"NABC"}`, "server_lameduck", "n3", "server n3 entered lame duck mode"},
+		{"$JS.EVENT.ADVISORY.API", `{"type":"io.nats.jetstream.advisory.v1.api_audit","server":"n1","subject":"$JS.API.STREAM.INFO.ORDERS","client":{"id":3}}`,
+			"api_audit", "n1", "JetStream API call to $JS.API.STREAM.INFO.ORDERS by client 3"},
+		{"$JS.EVENT.ADVISORY.FUTURE", `{"type":"io.nats.jetstream.advisory.v2.future"}`, "unknown", "", ""},
+	} {
+		event := decodeEvent(&nats.Msg{Subject: tc.subject, Data: []byte(tc.data)})
+		if event.Kind != tc.kind || event.Server != tc.server || event.Summary != tc.summary {
+			t.Fatalf("%s: unexpected event %+v", tc.subject, event)
+		}
+		if tc.kind == "unknown" && string(event.Event) != tc.data {
+			t.Fatalf("expected the raw event of an unknown schema, got %s", event.Event)
+		}
+	}
+}
@@ -413,7 +413,7 @@ func (k *KVTools) GetTools() []Tool {
 		{
 			Tool: mcp.Tool{
 				Name:        "kv_watch",
-				Description: "Watch the bucket or a specific key for updated",
+				Description: "Watch the bucket or a specific key for updates. Blocks until the command is stopped; subscribe to the nats://{account}/kv/{bucket} or nats://{account}/kv/{bucket}/{key} resource to be notified of changes instead",
 				InputSchema: mcp.ToolInputSchema{
 					Type: "object",
 					Properties: map[string]interface{}{
@@ -587,7 +587,7 @@ func (k *KVTools) kvAddHandler() server.ToolHandlerFunc {
 			args = append(args, flags...)
 		}
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -632,7 +632,7 @@ func (k *KVTools) kvPutHandler() server.ToolHandlerFunc {
 			args = append(args, flags...)
 		}
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -679,7 +679,7 @@ func (k *KVTools) kvGetHandler() server.ToolHandlerFunc {
 			args = append(args, flags...)
 		}
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -724,7 +724,7 @@ func (k *KVTools) kvCreateHandler() server.ToolHandlerFunc {
 			args = append(args, flags...)
 		}
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -774,7 +774,7 @@ func (k *KVTools) kvUpdateHandler() server.ToolHandlerFunc {
 			args = append(args, flags...)
 		}
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -816,7 +816,7 @@ func (k *KVTools) kvDelHandler() server.ToolHandlerFunc {
 			args = append(args, flags...)
 		}
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -858,7 +858,7 @@ func (k *KVTools) kvPurgeHandler() server.ToolHandlerFunc {
 			args = append(args, flags...)
 		}
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -893,7 +893,7 @@ func (k *KVTools) kvHistoryHandler() server.ToolHandlerFunc {
 			args = append(args, flags...)
 		}
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -940,7 +940,7 @@ func (k *KVTools) kvLsHandler() server.ToolHandlerFunc {
 			args = append(args, flags...)
 		}
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -973,7 +973,7 @@ func (k *KVTools) kvWatchHandler() server.ToolHandlerFunc {
 			args = append(args, flags...)
 		}
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -1005,7 +1005,7 @@ func (k *KVTools) kvInfoHandler() server.ToolHandlerFunc {
 			args = append(args, flags...)
 		}
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -1042,7 +1042,7 @@ func (k *KVTools) kvCompactHandler() server.ToolHandlerFunc {
 			args = append(args, flags...)
 		}
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,33 @@
+package tools
+
+import (
+	"context"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sinadarbouy/mcp-nats/internal/metrics"
+)
+
+// MetricsMiddleware records call counts, errors and latency of the wrapped
+// tool in the Prometheus metrics. Calls are labelled with the resolved
+// account so that clients cannot create arbitrary series.
+func MetricsMiddleware() Middleware {
+	return func(tool mcp.Tool, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			start := time.Now()
+			result, err := next(ctx, request)
+
+			outcome := "success"
+			switch {
+			case err != nil:
+				outcome = "error"
+			case result != nil && result.IsError:
+				outcome = "tool_error"
+			}
+			metrics.ObserveToolCall(tool.Name, resolvedAccountName(ctx, request), outcome, time.Since(start))
+
+			return result, err
+		}
+	}
+}
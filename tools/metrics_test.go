@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sinadarbouy/mcp-nats/internal/logger"
+	"github.com/sinadarbouy/mcp-nats/internal/metrics"
+	"github.com/sinadarbouy/mcp-nats/tools/common"
+)
+
+func TestMetricsMiddleware_labelsResolvedAccount(t *testing.T) {
+	logger.Initialize(logger.Config{Level: logger.LevelError})
+	creds := base64.StdEncoding.EncodeToString([]byte("creds"))
+	for _, tc := range []struct {
+		name string
+		conn common.Connection
+		args map[string]any
+		want string
+	}{
+		{"configured", common.Connection{Creds: map[string]common.NATSCreds{"A": {AccountName: "A", Creds: creds}}}, map[string]any{"account_name": "A"}, "A"},
+		{"unconfigured", common.Connection{Creds: map[string]common.NATSCreds{"A": {AccountName: "A", Creds: creds}}}, map[string]any{"account_name": "bogus"}, "unknown"},
+		{"anonymous", common.Connection{NoAuthentication: true}, map[string]any{"account_name": "bogus"}, "anonymous"},
+	} {
+		tc.conn.Name, tc.conn.URL = "default", "nats://test:4222"
+		n, err := NewNATSServerToolsWithConnection(tc.conn)
+		if err != nil {
+			t.Fatalf("%s: NewNATSServerToolsWithConnection: %v", tc.name, err)
+		}
+		n.Use(MetricsMiddleware())
+		tool := "metrics_test_" + tc.name
+		handler := n.wrapHandler(mcp.Tool{Name: tool}, func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("ok"), nil
+		})
+		if _, err := handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: tc.args}}); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+
+		rec := httptest.NewRecorder()
+		metrics.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+		body, _ := io.ReadAll(rec.Body)
+		if !strings.Contains(string(body), `account="`+tc.want+`",outcome="success",tool="`+tool+`"`) || strings.Contains(string(body), `account="bogus"`) {
+			t.Fatalf("%s: expected the call to be labelled with account %s", tc.name, tc.want)
+		}
+	}
+}
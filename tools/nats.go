@@ -3,54 +3,241 @@ package tools
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"sync"
 
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/nats-io/nats.go"
 	mcpnats "github.com/sinadarbouy/mcp-nats"
+	"github.com/sinadarbouy/mcp-nats/internal/audit"
 	"github.com/sinadarbouy/mcp-nats/internal/logger"
+	"github.com/sinadarbouy/mcp-nats/internal/metrics"
 	"github.com/sinadarbouy/mcp-nats/tools/common"
 )
 
 // NATSServerTools contains all NATS server-related tool definitions
 type NATSServerTools struct {
-	executors    map[string]*common.NATSExecutor
-	serverTools  *ServerTools
-	streamTools  *StreamTools
-	kvTools      *KVTools
-	publishTools *PublishTools
-	accountTools *AccountTools
-	rttTools     *RTTTools
-	objectTools  *ObjectTools
+	mu sync.Mutex
+	// clusters are the configured NATS systems, the default one first.
+	clusters  []common.Connection
+	executors map[executorKey]*common.NATSExecutor
+
+	serverTools    *ServerTools
+	streamTools    *StreamTools
+	consumerTools  *ConsumerTools
+	kvTools        *KVTools
+	publishTools   *PublishTools
+	subscribeTools *SubscribeTools
+	requestTools   *RequestTools
+	serviceTools   *ServiceTools
+	benchTools     *BenchTools
+	accountTools   *AccountTools
+	rttTools       *RTTTools
+	objectTools    *ObjectTools
+	clusterTools   *ClusterTools
+	auditTools     *AuditTools
+
+	resourceTools *ResourceTools
+	promptTools   *PromptTools
+	completions   *CompletionTools
+
+	middlewares    []Middleware
+	benchLimits    BenchLimits
+	resourceLimits ResourceLimits
 }
 
-// NewNATSServerTools creates a new instance of NATSServerTools
+// executorKey identifies a cached executor.
+type executorKey struct {
+	cluster string
+	url     string
+	account string
+}
+
+// NewNATSServerTools creates a new instance of NATSServerTools configured
+// from the environment.
 func NewNATSServerTools() (*NATSServerTools, error) {
+	return NewNATSServerToolsWithConnection(common.ConnectionFromEnv())
+}
+
+// NewNATSServerToolsWithConnection creates a new instance of NATSServerTools
+// whose tool schemas follow the authentication mode of conn.
+func NewNATSServerToolsWithConnection(conn common.Connection) (*NATSServerTools, error) {
+	return NewNATSServerToolsWithClusters([]common.Connection{conn})
+}
+
+// NewNATSServerToolsWithClusters creates a new instance of NATSServerTools for
+// several named clusters. The first cluster is the default; when there is more
+// than one, every tool accepts a cluster argument selecting another.
+func NewNATSServerToolsWithClusters(clusters []common.Connection) (*NATSServerTools, error) {
+	if len(clusters) == 0 {
+		return nil, fmt.Errorf("no NATS clusters configured")
+	}
 	n := &NATSServerTools{
-		executors: make(map[string]*common.NATSExecutor),
+		clusters:       clusters,
+		executors:      make(map[executorKey]*common.NATSExecutor),
+		benchLimits:    DefaultBenchLimits,
+		resourceLimits: DefaultResourceLimits,
 	}
 
 	// Initialize tool categories
 	n.serverTools = NewServerTools(n)
 	n.streamTools = NewStreamTools(n)
+	n.consumerTools = NewConsumerTools(n)
 	n.kvTools = NewKVTools(n)
 	n.publishTools = NewPublishTools(n)
+	n.subscribeTools = NewSubscribeTools(n)
+	n.requestTools = NewRequestTools(n)
+	n.serviceTools = NewServiceTools(n)
+	n.benchTools = NewBenchTools(n)
 	n.accountTools = NewAccountTools(n)
 	n.rttTools = NewRTTTools(n)
 	n.objectTools = NewObjectTools(n)
+	n.clusterTools = NewClusterTools(n)
+	n.resourceTools = NewResourceTools(n)
+	n.promptTools = NewPromptTools(n)
+	n.completions = NewCompletionTools(n)
 	logger.Info("Initialized NATS server tools")
 
 	return n, nil
 }
 
-// GetExecutor returns the executor for the specified account
-func (n *NATSServerTools) GetExecutor(ctx context.Context, accountName string) (*common.NATSExecutor, error) {
-	// Try to get existing executor
-	if executor, ok := n.executors[accountName]; ok {
-		return executor, nil
+// Use installs middlewares applied to every tool handler at registration time.
+// The first middleware is the outermost one.
+func (n *NATSServerTools) Use(middlewares ...Middleware) {
+	n.middlewares = append(n.middlewares, middlewares...)
+}
+
+// ResetMiddlewares removes the installed middlewares and disables auditing,
+// so that policies can be installed again before the tools are re-registered.
+func (n *NATSServerTools) ResetMiddlewares() {
+	n.middlewares = nil
+	n.auditTools = nil
+}
+
+// SetClusters replaces the configured clusters. Cached executors of clusters
+// that were removed or whose definition changed are closed, so later calls
+// connect with the new URL and credentials.
+func (n *NATSServerTools) SetClusters(clusters []common.Connection) error {
+	if len(clusters) == 0 {
+		return fmt.Errorf("no NATS clusters configured")
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	current := make(map[string]common.Connection, len(clusters))
+	for _, cluster := range clusters {
+		current[cluster.Name] = cluster
+	}
+	stale := make(map[string]bool)
+	for _, old := range n.clusters {
+		if cluster, ok := current[old.Name]; !ok || !reflect.DeepEqual(cluster, old) {
+			stale[old.Name] = true
+		}
+	}
+	for key, executor := range n.executors {
+		if !stale[key.cluster] {
+			continue
+		}
+		// Commands still running with the executor keep its credentials
+		// until they finish.
+		executor.Retire()
+		delete(n.executors, key)
+	}
+	metrics.SetExecutorCacheSize(len(n.executors))
+
+	n.clusters = clusters
+	return nil
+}
+
+// currentClusters returns the configured clusters, the default one first.
+func (n *NATSServerTools) currentClusters() []common.Connection {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.clusters
+}
+
+// EnableAudit records every tool invocation with rec and adds the audit_query
+// tool to the catalog.
+func (n *NATSServerTools) EnableAudit(rec *audit.Recorder) {
+	n.auditTools = NewAuditTools(rec)
+	n.Use(AuditMiddleware(rec))
+}
+
+// SetBenchLimits replaces the caps of the bench tool. Tool schemas show the
+// new caps once the tools are registered again.
+func (n *NATSServerTools) SetBenchLimits(limits BenchLimits) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.benchLimits = limits
+}
+
+// currentBenchLimits returns the caps of the bench tool.
+func (n *NATSServerTools) currentBenchLimits() BenchLimits {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.benchLimits
+}
+
+// SetResourceLimits replaces the limits of resource reads. They apply to the
+// next read.
+func (n *NATSServerTools) SetResourceLimits(limits ResourceLimits) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.resourceLimits = limits
+}
+
+// currentResourceLimits returns the limits of resource reads.
+func (n *NATSServerTools) currentResourceLimits() ResourceLimits {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.resourceLimits
+}
+
+// wrapHandler applies the installed middlewares to a tool handler.
+func (n *NATSServerTools) wrapHandler(tool mcp.Tool, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	for i := len(n.middlewares) - 1; i >= 0; i-- {
+		handler = n.middlewares[i](tool, handler)
+	}
+	return n.selectCluster(handler)
+}
+
+// accountNameRequired reports whether account_name is required, which is the
+// case when any configured cluster uses credentials-based authentication.
+func (n *NATSServerTools) accountNameRequired() bool {
+	for _, cluster := range n.currentClusters() {
+		if cluster.IsAccountNameRequired() {
+			return true
+		}
 	}
+	return false
+}
 
+// GetExecutor returns the executor for the specified account on the cluster
+// in the context. Executors are cached per cluster, URL and account.
+func (n *NATSServerTools) GetExecutor(ctx context.Context, accountName string) (*common.NATSExecutor, error) {
+	ctx, err := n.bindCluster(ctx)
+	if err != nil {
+		return nil, err
+	}
 	natsURL, err := mcpnats.GetNatsURLFromContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get NATS URL: %w", err)
 	}
+	key := executorKey{
+		cluster: mcpnats.GetClusterNameFromContext(ctx),
+		url:     natsURL,
+		account: accountName,
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	// Try to get existing executor
+	if executor, ok := n.executors[key]; ok {
+		return executor, nil
+	}
 
 	// Try to get authentication strategy first (for anonymous/user-pass auth)
 	authStrategy, err := mcpnats.GetAuthStrategyFromContext(ctx)
@@ -62,7 +249,8 @@ func (n *NATSServerTools) GetExecutor(ctx context.Context, accountName string) (
 		}
 
 		// Cache the executor
-		n.executors[accountName] = executor
+		n.executors[key] = executor
+		metrics.SetExecutorCacheSize(len(n.executors))
 		return executor, nil
 	}
 
@@ -79,12 +267,29 @@ func (n *NATSServerTools) GetExecutor(ctx context.Context, accountName string) (
 	}
 
 	// Cache the executor
-	n.executors[accountName] = executor
+	n.executors[key] = executor
+	metrics.SetExecutorCacheSize(len(n.executors))
 	return executor, nil
 }
 
+// connect opens a direct NATS connection for the account in the tool
+// arguments. The caller closes it.
+func (n *NATSServerTools) connect(ctx context.Context, arguments map[string]interface{}) (*nats.Conn, error) {
+	accountName, err := mcpnats.DetermineAccountName(ctx, arguments)
+	if err != nil {
+		return nil, err
+	}
+	executor, err := n.GetExecutor(ctx, accountName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get NATS executor: %w", err)
+	}
+	return executor.Connect()
+}
+
 // Cleanup removes all temporary credential files
 func (n *NATSServerTools) Cleanup() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
 	for _, executor := range n.executors {
 		if err := executor.Cleanup(); err != nil {
 			logger.Error("Failed to cleanup executor",
@@ -105,6 +310,11 @@ func (n *NATSServerTools) StreamTools() ToolCategory {
 	return n.streamTools
 }
 
+// ConsumerTools returns the consumer tools category
+func (n *NATSServerTools) ConsumerTools() ToolCategory {
+	return n.consumerTools
+}
+
 // KVTools returns the KV tools category
 func (n *NATSServerTools) KVTools() ToolCategory {
 	return n.kvTools
@@ -115,6 +325,26 @@ func (n *NATSServerTools) PublishTools() ToolCategory {
 	return n.publishTools
 }
 
+// SubscribeTools returns the subscribe tools category
+func (n *NATSServerTools) SubscribeTools() ToolCategory {
+	return n.subscribeTools
+}
+
+// RequestTools returns the request tools category
+func (n *NATSServerTools) RequestTools() ToolCategory {
+	return n.requestTools
+}
+
+// ServiceTools returns the micro service tools category
+func (n *NATSServerTools) ServiceTools() ToolCategory {
+	return n.serviceTools
+}
+
+// BenchTools returns the load generation tools category
+func (n *NATSServerTools) BenchTools() ToolCategory {
+	return n.benchTools
+}
+
 // AccountTools returns the account tools category
 func (n *NATSServerTools) AccountTools() ToolCategory {
 	return n.accountTools
@@ -130,17 +360,56 @@ func (n *NATSServerTools) ObjectTools() ToolCategory {
 	return n.objectTools
 }
 
+// ClusterTools returns the cluster tools category
+func (n *NATSServerTools) ClusterTools() ToolCategory {
+	return n.clusterTools
+}
+
+// ResourceTools returns the JetStream resource templates
+func (n *NATSServerTools) ResourceTools() ResourceCategory {
+	return n.resourceTools
+}
+
+// PromptTools returns the operational workflow prompts
+func (n *NATSServerTools) PromptTools() PromptCategory {
+	return n.promptTools
+}
+
+// CompletionTools returns the completion provider for prompt arguments and
+// resource template variables
+func (n *NATSServerTools) CompletionTools() *CompletionTools {
+	return n.completions
+}
+
+// AuditTools returns the audit tools category, or nil when auditing is disabled
+func (n *NATSServerTools) AuditTools() ToolCategory {
+	if n.auditTools == nil {
+		return nil
+	}
+	return n.auditTools
+}
+
 // toolCategories returns all tool categories in registration order.
 func (n *NATSServerTools) toolCategories() []ToolCategory {
-	return []ToolCategory{
+	categories := []ToolCategory{
 		n.ServerTools(),
 		n.StreamTools(),
+		n.ConsumerTools(),
 		n.KVTools(),
 		n.PublishTools(),
+		n.SubscribeTools(),
+		n.RequestTools(),
+		n.ServiceTools(),
+		n.BenchTools(),
 		n.AccountTools(),
 		n.RTTTools(),
 		n.ObjectTools(),
+		n.ClusterTools(),
+	}
+	if audit := n.AuditTools(); audit != nil {
+		categories = append(categories, audit)
 	}
+	return categories
 }
 
 // ToolCount returns how many tools would be registered for the given readOnly flag.
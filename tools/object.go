@@ -309,7 +309,7 @@ func (o *ObjectTools) GetTools() []Tool {
 		{
 			Tool: mcp.Tool{
 				Name:        "object_watch",
-				Description: "Watch a bucket for changes",
+				Description: "Watch a bucket for changes. Blocks until the command is stopped; subscribe to the nats://{account}/object/{bucket} resource to be notified of changes instead",
 				InputSchema: mcp.ToolInputSchema{
 					Type: "object",
 					Properties: map[string]interface{}{
@@ -407,7 +407,7 @@ func (o *ObjectTools) objectAddHandler() server.ToolHandlerFunc {
 			"bucket", bucket,
 		)
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -476,7 +476,7 @@ func (o *ObjectTools) objectPutHandler() server.ToolHandlerFunc {
 			"file", file,
 		)
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -530,7 +530,7 @@ func (o *ObjectTools) objectGetHandler() server.ToolHandlerFunc {
 			"file", file,
 		)
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -577,7 +577,7 @@ func (o *ObjectTools) objectDelHandler() server.ToolHandlerFunc {
 			"bucket", bucket,
 		)
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -621,7 +621,7 @@ func (o *ObjectTools) objectInfoHandler() server.ToolHandlerFunc {
 			"bucket", bucket,
 		)
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -665,7 +665,7 @@ func (o *ObjectTools) objectLsHandler() server.ToolHandlerFunc {
 			"bucket", bucket,
 		)
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -707,7 +707,7 @@ func (o *ObjectTools) objectSealHandler() server.ToolHandlerFunc {
 			"bucket", bucket,
 		)
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -739,7 +739,7 @@ func (o *ObjectTools) objectWatchHandler() server.ToolHandlerFunc {
 			"bucket", bucket,
 		)
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
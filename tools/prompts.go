@@ -0,0 +1,251 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// PromptCategory represents a group of related MCP prompts
+type PromptCategory interface {
+	GetPrompts() []Prompt
+}
+
+// Prompt combines an MCP prompt definition with its handler
+type Prompt struct {
+	Prompt  mcp.Prompt
+	Handler server.PromptHandlerFunc
+}
+
+// Register registers a single prompt with the MCP server
+func (p *Prompt) Register(mcp *server.MCPServer) {
+	mcp.AddPrompt(p.Prompt, p.Handler)
+}
+
+// RegisterPrompts registers all prompts with the MCP server.
+func RegisterPrompts(mcp *server.MCPServer, n *NATSServerTools) {
+	for _, prompt := range n.PromptTools().GetPrompts() {
+		prompt.Register(mcp)
+	}
+}
+
+// PromptTools provides prompts for common NATS operational workflows. Each
+// prompt walks the model through the mcp-nats tools relevant to the task.
+type PromptTools struct {
+	nats *NATSServerTools
+}
+
+// NewPromptTools creates a new PromptTools instance
+func NewPromptTools(nats *NATSServerTools) *PromptTools {
+	return &PromptTools{
+		nats: nats,
+	}
+}
+
+func accountArgument() mcp.PromptOption {
+	return mcp.WithArgument("account",
+		mcp.ArgumentDescription("The NATS account to inspect; omit to use the default account"),
+	)
+}
+
+func streamArgument(required bool) mcp.PromptOption {
+	opts := []mcp.ArgumentOption{mcp.ArgumentDescription("The JetStream stream name")}
+	if required {
+		opts = append(opts, mcp.RequiredArgument())
+	}
+	return mcp.WithArgument("stream", opts...)
+}
+
+// GetPrompts implements the PromptCategory interface
+func (p *PromptTools) GetPrompts() []Prompt {
+	return []Prompt{
+		{
+			Prompt: mcp.NewPrompt("diagnose_stream_lag",
+				mcp.WithPromptDescription("Diagnose why a stream or its consumers are falling behind"),
+				accountArgument(),
+				streamArgument(true),
+			),
+			Handler: p.diagnoseStreamLagHandler(),
+		},
+		{
+			Prompt: mcp.NewPrompt("audit_kv_bucket",
+				mcp.WithPromptDescription("Audit the configuration of a KV bucket against common best practices"),
+				accountArgument(),
+				mcp.WithArgument("bucket",
+					mcp.ArgumentDescription("The KV bucket name"),
+					mcp.RequiredArgument(),
+				),
+			),
+			Handler: p.auditKVBucketHandler(),
+		},
+		{
+			Prompt: mcp.NewPrompt("investigate_slow_consumers",
+				mcp.WithPromptDescription("Find slow consumers and clients and explain what is holding them back"),
+				accountArgument(),
+				streamArgument(false),
+			),
+			Handler: p.investigateSlowConsumersHandler(),
+		},
+		{
+			Prompt: mcp.NewPrompt("review_account_limits",
+				mcp.WithPromptDescription("Review JetStream and connection limits of an account against current usage"),
+				accountArgument(),
+			),
+			Handler: p.reviewAccountLimitsHandler(),
+		},
+		{
+			Prompt: mcp.NewPrompt("prepare_stream_migration",
+				mcp.WithPromptDescription("Prepare a plan to migrate a stream, e.g. to another cluster, placement or configuration"),
+				accountArgument(),
+				streamArgument(true),
+				mcp.WithArgument("target",
+					mcp.ArgumentDescription("Where the stream should end up, e.g. a cluster name or the configuration change to apply"),
+				),
+			),
+			Handler: p.prepareStreamMigrationHandler(),
+		},
+	}
+}
+
+// promptArgs reads prompt arguments, failing when a required one is missing.
+func promptArgs(request mcp.GetPromptRequest, required ...string) (map[string]string, error) {
+	args := make(map[string]string, len(request.Params.Arguments))
+	for name, value := range request.Params.Arguments {
+		args[name] = strings.TrimSpace(value)
+	}
+	for _, name := range required {
+		if args[name] == "" {
+			return nil, fmt.Errorf("missing %s", name)
+		}
+	}
+	return args, nil
+}
+
+// accountClause describes which account_name to pass to tools.
+func accountClause(account string) string {
+	if account == "" {
+		return "Use the default account (omit account_name, or pass the account you are asked about)."
+	}
+	return fmt.Sprintf("Pass account_name %q to every tool call.", account)
+}
+
+func promptResult(description string, lines ...string) *mcp.GetPromptResult {
+	return mcp.NewGetPromptResult(description, []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(strings.Join(lines, "\n"))),
+	})
+}
+
+func (p *PromptTools) diagnoseStreamLagHandler() server.PromptHandlerFunc {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		args, err := promptArgs(request, "stream")
+		if err != nil {
+			return nil, err
+		}
+		stream := args["stream"]
+		return promptResult(fmt.Sprintf("Diagnose lag on stream %s", stream),
+			fmt.Sprintf("Diagnose why stream %q, or the consumers reading from it, are falling behind.", stream),
+			accountClause(args["account"]),
+			"",
+			"1. Call `stream_info` for the stream to get its configuration, message and byte counts, first/last sequences and cluster state. Note replicas that are not current or are lagging the leader.",
+			"2. Call `stream_report` to compare this stream with the rest of the account, and `consumer_report` for the stream to see how far behind each consumer is.",
+			"3. Call `stream_state` to check how fast messages arrive and whether limits (max_msgs, max_bytes, max_age) are discarding data.",
+			"4. Call `stream_subjects` to see which subjects dominate the traffic.",
+			"5. Call `server_list` to check the health and load of the servers hosting the stream.",
+			"",
+			"Summarise where the lag comes from (publishers, storage, replication or consumers), quote the numbers you relied on, and propose concrete fixes ordered by impact.",
+		), nil
+	}
+}
+
+func (p *PromptTools) auditKVBucketHandler() server.PromptHandlerFunc {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		args, err := promptArgs(request, "bucket")
+		if err != nil {
+			return nil, err
+		}
+		bucket := args["bucket"]
+		return promptResult(fmt.Sprintf("Audit KV bucket %s", bucket),
+			fmt.Sprintf("Audit the configuration of KV bucket %q.", bucket),
+			accountClause(args["account"]),
+			"",
+			"1. Call `kv_info` for the bucket to read its history, TTL, maximum value size, storage type, replicas and compression.",
+			"2. Call `kv_ls` for the bucket to see how many keys it holds and how they are named.",
+			"3. Pick a few representative keys and call `kv_history` to check how often they change and whether the history depth is actually used.",
+			"",
+			"Check for: a single replica on production data, file storage where memory would do (or the reverse), unbounded bucket size or value size, a TTL that does not match how keys are used, history deeper than needed, and deleted keys that `kv_compact` would reclaim.",
+			"Report each finding with its current value, the risk and the recommended setting. Do not change the bucket.",
+		), nil
+	}
+}
+
+func (p *PromptTools) investigateSlowConsumersHandler() server.PromptHandlerFunc {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		args, err := promptArgs(request)
+		if err != nil {
+			return nil, err
+		}
+		scope := "in the account"
+		if stream := args["stream"]; stream != "" {
+			scope = fmt.Sprintf("of stream %q", stream)
+		}
+		return promptResult("Investigate slow consumers",
+			fmt.Sprintf("Investigate slow consumers %s.", scope),
+			accountClause(args["account"]),
+			"",
+			"1. Call `account_report_connections` sorted by out-bytes to find busy core NATS clients, and note their subscriptions, pending data and RTT.",
+			"2. Call `stream_report`, then `consumer_report` for the busiest streams, to find JetStream consumers with large numbers of pending or unacknowledged messages or redeliveries.",
+			"3. For the affected consumers call `consumer_info` to check their ack policy, ack wait, max ack pending and backoff, and for their streams call `stream_info` to check retention, limits and whether the stream is waiting on a consumer under work-queue or interest retention.",
+			"4. Call `server_list` to rule out overloaded servers or slow routes.",
+			"",
+			"Explain for each slow consumer whether the bottleneck is the client (processing speed, max ack pending, flow control), the network or the server, and suggest the fix.",
+		), nil
+	}
+}
+
+func (p *PromptTools) reviewAccountLimitsHandler() server.PromptHandlerFunc {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		args, err := promptArgs(request)
+		if err != nil {
+			return nil, err
+		}
+		return promptResult("Review account limits",
+			"Review the limits of the NATS account against its current usage.",
+			accountClause(args["account"]),
+			"",
+			"1. Call `account_info` to read connection, subscription, payload and JetStream limits (memory, storage, streams, consumers) and current usage.",
+			"2. Call `account_report_statistics` to see traffic and connection counts per server.",
+			"3. Call `stream_report` to see which streams use the most storage.",
+			"",
+			"Flag every limit above 80% utilisation, limits that are unset (unlimited) where a bound would be safer, and limits far above any realistic usage. Give the current usage, the limit and a recommendation for each.",
+		), nil
+	}
+}
+
+func (p *PromptTools) prepareStreamMigrationHandler() server.PromptHandlerFunc {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		args, err := promptArgs(request, "stream")
+		if err != nil {
+			return nil, err
+		}
+		stream := args["stream"]
+		target := "the target the operator describes"
+		if args["target"] != "" {
+			target = args["target"]
+		}
+		return promptResult(fmt.Sprintf("Prepare migration of stream %s", stream),
+			fmt.Sprintf("Prepare a migration plan for stream %q to %s.", stream, target),
+			accountClause(args["account"]),
+			"",
+			"1. Call `stream_info` to capture the current configuration, placement, replicas, mirrors and sources.",
+			"2. Call `stream_state` and `stream_subjects` to size the data that has to move.",
+			"3. Call `stream_report` to list the consumers that must be recreated or will be affected.",
+			"4. Call `server_list` to confirm the target has the capacity and JetStream resources for the stream.",
+			"5. Call `account_info` to check that account limits allow a temporary second copy of the stream.",
+			"",
+			"Produce a step-by-step plan: how data is copied (mirror or source, or `account_backup` and `account_restore`), how publishers and consumers are switched over, how to verify the result and how to roll back. Do not perform any changes; only plan them.",
+		), nil
+	}
+}
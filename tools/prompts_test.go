@@ -0,0 +1,65 @@
+package tools
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sinadarbouy/mcp-nats/internal/logger"
+)
+
+var promptToolRef = regexp.MustCompile("`([a-z_]+)`")
+
+func TestPrompts_referenceRegisteredTools(t *testing.T) {
+	logger.Initialize(logger.Config{Level: logger.LevelError})
+	n, err := NewNATSServerTools()
+	if err != nil {
+		t.Fatalf("NewNATSServerTools: %v", err)
+	}
+	known := map[string]bool{}
+	for _, category := range n.toolCategories() {
+		for _, tool := range category.GetTools() {
+			known[tool.Tool.Name] = true
+		}
+	}
+
+	for _, prompt := range n.PromptTools().GetPrompts() {
+		request := mcp.GetPromptRequest{}
+		request.Params.Name = prompt.Prompt.Name
+		request.Params.Arguments = map[string]string{}
+		for _, arg := range prompt.Prompt.Arguments {
+			request.Params.Arguments[arg.Name] = "TEST"
+		}
+
+		result, err := prompt.Handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("%s: %v", prompt.Prompt.Name, err)
+		}
+		text := result.Messages[0].Content.(mcp.TextContent).Text
+		refs := promptToolRef.FindAllStringSubmatch(text, -1)
+		if len(refs) == 0 {
+			t.Fatalf("%s: prompt does not reference any tool", prompt.Prompt.Name)
+		}
+		for _, ref := range refs {
+			if !known[ref[1]] {
+				t.Fatalf("%s: references unknown tool %q", prompt.Prompt.Name, ref[1])
+			}
+		}
+	}
+}
+
+func TestPrompts_requireArguments(t *testing.T) {
+	for _, prompt := range NewPromptTools(nil).GetPrompts() {
+		for _, arg := range prompt.Prompt.Arguments {
+			if !arg.Required {
+				continue
+			}
+			request := mcp.GetPromptRequest{}
+			request.Params.Name = prompt.Prompt.Name
+			if _, err := prompt.Handler(context.Background(), request); err == nil {
+				t.Fatalf("%s: expected error without required argument %s", prompt.Prompt.Name, arg.Name)
+			}
+		}
+	}
+}
@@ -11,7 +11,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
-	"github.com/sinadarbouy/mcp-nats/tools/common"
+	mcpnats "github.com/sinadarbouy/mcp-nats"
+	"github.com/sinadarbouy/mcp-nats/internal/tracing"
 )
 
 // PublishTools represents all NATS publish-related tools
@@ -28,7 +29,7 @@ func NewPublishTools(nats *NATSServerTools) *PublishTools {
 
 // isAccountNameRequired determines if account_name is required based on auth strategy
 func (p *PublishTools) isAccountNameRequired() bool {
-	return common.IsAccountNameRequired()
+	return p.nats.accountNameRequired()
 }
 
 // GetTools implements the ToolCategory interface
@@ -120,7 +121,7 @@ func generateRandomString(min, max int) string {
 
 func (p *PublishTools) publishHandler() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		accountName, err := common.DetermineAccountName(request.GetArguments())
+		accountName, err := mcpnats.DetermineAccountName(ctx, request.GetArguments())
 		if err != nil {
 			return nil, err
 		}
@@ -197,11 +198,16 @@ func (p *PublishTools) publishHandler() server.ToolHandlerFunc {
 				}
 			}
 
+			// Propagate the trace context of this tool call to subscribers
+			for _, header := range tracing.HeaderArgs(ctx) {
+				args = append(args, "--header", header)
+			}
+
 			// Add subject and message
 			args = append(args, subject, msg)
 
 			// Execute the command
-			if _, err := executor.ExecuteCommand(args...); err != nil {
+			if _, err := executor.ExecuteCommandContext(ctx, args...); err != nil {
 				return nil, fmt.Errorf("failed to publish message: %w", err)
 			}
 
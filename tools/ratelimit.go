@@ -0,0 +1,105 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	mcpnats "github.com/sinadarbouy/mcp-nats"
+	"github.com/sinadarbouy/mcp-nats/internal/ratelimit"
+)
+
+// messageCounters return how many messages a call of the tool will publish,
+// for tools that publish.
+var messageCounters = map[string]func(args map[string]interface{}) int{
+	"publish": func(args map[string]interface{}) int {
+		if c, ok := args["count"].(float64); ok && c > 0 {
+			return int(c)
+		}
+		return 1
+	},
+	// A benchmark spreads its messages over the publishers.
+	"bench": func(args map[string]interface{}) int {
+		if m, ok := args["messages"].(float64); ok && m > 0 {
+			return int(m)
+		}
+		return defaultBenchMessages
+	},
+	"request":      oneMessage,
+	"service_call": oneMessage,
+	"trace":        oneMessage,
+}
+
+// oneMessage counts the single message of tools such as request.
+func oneMessage(map[string]interface{}) int { return 1 }
+
+// messageCount returns how many messages the call will publish.
+func messageCount(tool string, args map[string]interface{}) int {
+	if counter, ok := messageCounters[tool]; ok {
+		return counter(args)
+	}
+	return 0
+}
+
+// rateLimitError is the structured content returned to throttled callers.
+type rateLimitError struct {
+	Error        string  `json:"error"`
+	Tool         string  `json:"tool"`
+	Scope        string  `json:"scope"`
+	Limit        string  `json:"limit"`
+	PerSecond    float64 `json:"per_second"`
+	RetryAfterMS int64   `json:"retry_after_ms,omitempty"`
+	Reason       string  `json:"reason,omitempty"`
+}
+
+// RateLimitMiddleware rejects calls exceeding the limits of l. Calls are
+// charged to the MCP session and to the inbound identity.
+func RateLimitMiddleware(l *ratelimit.Limiter) Middleware {
+	return func(tool mcp.Tool, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			decision := l.Allow(tool.Name, rateLimitKeys(ctx), messageCount(tool.Name, request.GetArguments()))
+			if decision.Allowed {
+				return next(ctx, request)
+			}
+
+			text, retryAfterMS := rateLimitMessage(tool.Name, decision)
+			result := mcp.NewToolResultStructured(rateLimitError{
+				Error:        "rate_limited",
+				Tool:         tool.Name,
+				Scope:        decision.Scope,
+				Limit:        decision.Kind,
+				PerSecond:    decision.Limit,
+				RetryAfterMS: retryAfterMS,
+				Reason:       decision.Reason,
+			}, text)
+			result.IsError = true
+			return result, nil
+		}
+	}
+}
+
+// rateLimitKeys returns who a request is charged to: the MCP session and
+// the inbound identity.
+func rateLimitKeys(ctx context.Context) []ratelimit.Key {
+	var keys []ratelimit.Key
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		keys = append(keys, ratelimit.Key{Scope: "session", ID: session.SessionID()})
+	}
+	if identity := mcpnats.GetInboundIdentityFromContext(ctx); identity != "" {
+		keys = append(keys, ratelimit.Key{Scope: "identity", ID: identity})
+	}
+	return keys
+}
+
+// rateLimitMessage describes a rejected request of name, and returns when it
+// can be retried unless it can never be satisfied.
+func rateLimitMessage(name string, decision ratelimit.Decision) (string, int64) {
+	text := fmt.Sprintf("rate limit exceeded for %s (%s per %s)", name, decision.Kind, decision.Scope)
+	if decision.Reason != "" {
+		return text + ": " + decision.Reason, 0
+	}
+	retryAfterMS := int64(math.Ceil(float64(decision.RetryAfter.Microseconds()) / 1000))
+	return text + fmt.Sprintf("; retry after %dms", retryAfterMS), retryAfterMS
+}
@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpnats "github.com/sinadarbouy/mcp-nats"
+	"github.com/sinadarbouy/mcp-nats/internal/ratelimit"
+)
+
+// rateLimitedTool wraps a handler counting its calls in RateLimitMiddleware.
+func rateLimitedTool(t *testing.T, cfg ratelimit.Config, name string, calls *int) func(args map[string]any) *mcp.CallToolResult {
+	handler := RateLimitMiddleware(ratelimit.New(cfg))(mcp.Tool{Name: name}, func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		*calls++
+		return mcp.NewToolResultText("ok"), nil
+	})
+	ctx := mcpnats.WithInboundIdentity(context.Background(), "alice")
+	return func(args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		result, err := handler(ctx, mcp.CallToolRequest{Params: mcp.CallToolParams{Name: name, Arguments: args}})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		return result
+	}
+}
+
+func TestRateLimitMiddleware_chargesBenchMessages(t *testing.T) {
+	var calls int
+	bench := rateLimitedTool(t, ratelimit.Config{Default: ratelimit.Limit{MessagesPerSecond: 1, MessageBurst: 1000}}, "bench", &calls)
+
+	if result := bench(map[string]any{"mode": "pubsub", "messages": float64(800), "publishers": float64(4)}); result.IsError {
+		t.Fatalf("expected the first benchmark to run: %v", result.Content)
+	}
+	if result := bench(map[string]any{"mode": "pubsub", "messages": float64(800)}); !result.IsError {
+		t.Fatalf("expected the second benchmark to be throttled")
+	}
+	// Without messages the benchmark publishes the default of 10000.
+	result := bench(map[string]any{"mode": "pubsub"})
+	if !result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "request needs 10000 messages") {
+		t.Fatalf("expected the default benchmark to exceed the burst: %v", result.Content)
+	}
+	if calls != 1 {
+		t.Fatalf("bench ran %d times, want 1", calls)
+	}
+
+	for _, tool := range []string{"request", "service_call", "trace"} {
+		if messageCount(tool, map[string]any{}) != 1 {
+			t.Fatalf("%s should be charged one message", tool)
+		}
+	}
+}
+
+func TestRateLimitMiddleware_throttledResult(t *testing.T) {
+	var calls int
+	publish := rateLimitedTool(t, ratelimit.Config{Default: ratelimit.Limit{CallsPerSecond: 0.5, CallBurst: 1}}, "publish", &calls)
+	if result := publish(map[string]any{"subject": "a"}); result.IsError {
+		t.Fatalf("expected the first call to run: %v", result.Content)
+	}
+
+	result := publish(map[string]any{"subject": "a"})
+	if !result.IsError || calls != 1 {
+		t.Fatalf("expected the second call to be throttled, ran %d times", calls)
+	}
+	payload, ok := result.StructuredContent.(rateLimitError)
+	if !ok {
+		t.Fatalf("unexpected structured content %#v", result.StructuredContent)
+	}
+	if payload.Error != "rate_limited" || payload.Tool != "publish" || payload.Scope != "identity" || payload.Limit != ratelimit.KindCalls ||
+		payload.PerSecond != 0.5 || payload.RetryAfterMS <= 1000 || payload.RetryAfterMS > 2000 || payload.Reason != "" {
+		t.Fatalf("unexpected throttle payload %+v", payload)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	if !strings.HasPrefix(text, "rate limit exceeded for publish (calls per identity); retry after ") {
+		t.Fatalf("unexpected throttle message %q", text)
+	}
+	// The client receives the payload as structured content of the error.
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("marshal result: %v", err)
+	}
+	if !strings.Contains(string(data), `"isError":true`) || !strings.Contains(string(data), `"structuredContent":{"error":"rate_limited","tool":"publish","scope":"identity","limit":"calls","per_second":0.5,"retry_after_ms":`) {
+		t.Fatalf("unexpected result sent to the client: %s", data)
+	}
+}
@@ -1,29 +1,149 @@
 package tools
 
-// Mutating tools change JetStream/KV/object state, publish messages, or perform
-// account backup/restore (filesystem / cluster mutation). When adding a new
-// tool that performs writes, register its name here and keep mutatingToolNames
-// in sync.
-var mutatingToolNames = map[string]struct{}{
-	"publish": {},
-
-	"kv_add":     {},
-	"kv_put":     {},
-	"kv_create":  {},
-	"kv_update":  {},
-	"kv_del":     {},
-	"kv_purge":   {},
-	"kv_compact": {},
-
-	"object_add":  {},
-	"object_put":  {},
-	"object_del":  {},
-	"object_seal": {},
-
-	"account_backup":  {},
-	"account_restore": {},
+import "github.com/mark3labs/mcp-go/mcp"
+
+// toolClass classifies a tool for read-only mode and MCP tool annotations.
+// Mutating tools change JetStream/KV/object state, publish messages, or
+// perform account backup/restore (filesystem / cluster mutation).
+type toolClass struct {
+	Title string
+	// Mutating tools are omitted in read-only mode.
+	Mutating bool
+	// Destructive tools may overwrite or remove existing data. Only
+	// meaningful for mutating tools.
+	Destructive bool
+	// Idempotent tools have no additional effect when repeated with the same
+	// arguments.
+	Idempotent bool
+	// OpenWorld tools talk to the NATS system rather than only local state.
+	OpenWorld bool
+}
+
+// readTool classifies a read-only tool that queries NATS.
+func readTool(title string) toolClass {
+	return toolClass{Title: title, Idempotent: true, OpenWorld: true}
 }
 
+// toolClasses is the single source of truth for how every tool is classified.
+// Every tool in every ToolCategory must have an entry here; when adding a tool
+// that performs writes, mark it Mutating.
+var toolClasses = map[string]toolClass{
+	"server_list": readTool("List Servers"),
+	"server_info": readTool("Server Info"),
+	"server_ping": readTool("Ping Servers"),
+
+	"server_report_connections":   readTool("Server Connections Report"),
+	"server_report_subscriptions": readTool("Server Subscriptions Report"),
+	"server_report_jetstream":     readTool("Server JetStream Report"),
+	"server_report_routes":        readTool("Server Routes Report"),
+	"server_report_gateways":      readTool("Server Gateways Report"),
+	"server_report_leafnodes":     readTool("Server Leafnodes Report"),
+	"server_report_accounts":      readTool("Server Accounts Report"),
+	"server_report_health":        readTool("Server Health Report"),
+
+	"server_check_connection": readTool("Check Connection Health"),
+	"server_check_stream":     readTool("Check Stream Health"),
+	"server_check_consumer":   readTool("Check Consumer Health"),
+	"server_check_kv":         readTool("Check KV Bucket Health"),
+	"server_check_jetstream":  readTool("Check JetStream Account Limits"),
+	"server_check_meta":       readTool("Check JetStream Meta Cluster"),
+	"server_check_message":    readTool("Check Stream Message"),
+
+	"stream_info":     readTool("Stream Info"),
+	"stream_list":     readTool("List Streams"),
+	"stream_report":   readTool("Stream Report"),
+	"stream_find":     readTool("Find Streams"),
+	"stream_state":    readTool("Stream State"),
+	"stream_subjects": readTool("Stream Subjects"),
+	"stream_view":     readTool("View Stream Messages"),
+	"stream_get":      readTool("Get Stream Message"),
+	"stream_add":      {Title: "Add Stream", Mutating: true, Idempotent: true, OpenWorld: true},
+	"stream_edit":     {Title: "Edit Stream", Mutating: true, Destructive: true, Idempotent: true, OpenWorld: true},
+	"stream_rm":       {Title: "Remove Stream", Mutating: true, Destructive: true, Idempotent: true, OpenWorld: true},
+	"stream_purge":    {Title: "Purge Stream", Mutating: true, Destructive: true, Idempotent: true, OpenWorld: true},
+	"stream_rmm":      {Title: "Remove Stream Message", Mutating: true, Destructive: true, Idempotent: true, OpenWorld: true},
+	"stream_seal":     {Title: "Seal Stream", Mutating: true, Destructive: true, Idempotent: true, OpenWorld: true},
+	"stream_copy":     {Title: "Copy Stream Configuration", Mutating: true, OpenWorld: true},
+
+	"consumer_list":   readTool("List Consumers"),
+	"consumer_info":   readTool("Consumer Info"),
+	"consumer_report": readTool("Consumer Report"),
+	"consumer_add":    {Title: "Add Consumer", Mutating: true, Idempotent: true, OpenWorld: true},
+	"consumer_edit":   {Title: "Edit Consumer", Mutating: true, Destructive: true, Idempotent: true, OpenWorld: true},
+	"consumer_rm":     {Title: "Remove Consumer", Mutating: true, Destructive: true, Idempotent: true, OpenWorld: true},
+	"consumer_pause":  {Title: "Pause Consumer", Mutating: true, Idempotent: true, OpenWorld: true},
+	"consumer_resume": {Title: "Resume Consumer", Mutating: true, Idempotent: true, OpenWorld: true},
+	// consumer_next acknowledges what it fetches, which removes messages
+	// from work-queue streams.
+	"consumer_next": {Title: "Fetch Next Consumer Messages", Mutating: true, Destructive: true, OpenWorld: true},
+
+	"kv_get":     readTool("Get KV Value"),
+	"kv_history": readTool("KV Key History"),
+	"kv_ls":      readTool("List KV Buckets or Keys"),
+	"kv_watch":   readTool("Watch KV Bucket"),
+	"kv_info":    readTool("KV Bucket Info"),
+	"kv_add":     {Title: "Add KV Bucket", Mutating: true, Idempotent: true, OpenWorld: true},
+	"kv_put":     {Title: "Put KV Value", Mutating: true, Destructive: true, Idempotent: true, OpenWorld: true},
+	"kv_create":  {Title: "Create KV Key", Mutating: true, OpenWorld: true},
+	"kv_update":  {Title: "Update KV Key", Mutating: true, Destructive: true, OpenWorld: true},
+	"kv_del":     {Title: "Delete KV Key or Bucket", Mutating: true, Destructive: true, Idempotent: true, OpenWorld: true},
+	"kv_purge":   {Title: "Purge KV Key", Mutating: true, Destructive: true, Idempotent: true, OpenWorld: true},
+	"kv_compact": {Title: "Compact KV Bucket", Mutating: true, Destructive: true, Idempotent: true, OpenWorld: true},
+
+	"object_get":   readTool("Get Object"),
+	"object_info":  readTool("Object Info"),
+	"object_ls":    readTool("List Objects"),
+	"object_watch": readTool("Watch Object Store"),
+	"object_add":   {Title: "Add Object Store Bucket", Mutating: true, Idempotent: true, OpenWorld: true},
+	"object_put":   {Title: "Put Object", Mutating: true, Destructive: true, Idempotent: true, OpenWorld: true},
+	"object_del":   {Title: "Delete Object or Bucket", Mutating: true, Destructive: true, Idempotent: true, OpenWorld: true},
+	"object_seal":  {Title: "Seal Object Store Bucket", Mutating: true, Destructive: true, Idempotent: true, OpenWorld: true},
+
+	"publish": {Title: "Publish Messages", Mutating: true, OpenWorld: true},
+	// subscribe only observes traffic, but every call sees different messages.
+	"subscribe": {Title: "Sample Subject Traffic", OpenWorld: true},
+	// subscribe_queue takes messages away from the queue group's members.
+	"subscribe_queue": {Title: "Sample Queue Group Traffic", Mutating: true, OpenWorld: true},
+	"events":          {Title: "Capture NATS Events", OpenWorld: true},
+	// request reaches services that may act on it.
+	"request": {Title: "Request Reply", Mutating: true, OpenWorld: true},
+	// trace publishes the message, and delivers it when asked to.
+	"trace": {Title: "Trace Message Path", Mutating: true, OpenWorld: true},
+
+	"service_list":  readTool("List Services"),
+	"service_info":  readTool("Service Info"),
+	"service_stats": readTool("Service Statistics"),
+	"service_ping":  readTool("Ping Services"),
+	"service_call":  {Title: "Call Service Endpoint", Mutating: true, OpenWorld: true},
+
+	// bench publishes load and creates a temporary stream for JetStream modes.
+	"bench": {Title: "Benchmark NATS", Mutating: true, OpenWorld: true},
+
+	"account_info":               readTool("Account Info"),
+	"account_report_connections": readTool("Account Connections Report"),
+	"account_report_statistics":  readTool("Account Statistics Report"),
+	"account_tls":                readTool("Server TLS Chain"),
+	"account_backup":             {Title: "Back Up Account", Mutating: true, OpenWorld: true},
+	"account_restore":            {Title: "Restore Account", Mutating: true, OpenWorld: true},
+
+	"rtt": readTool("Round-Trip Time"),
+
+	"cluster_list": {Title: "List Clusters", Idempotent: true},
+
+	"audit_query": {Title: "Query Audit Log", Idempotent: true},
+}
+
+// mutatingToolNames is derived from toolClasses.
+var mutatingToolNames = func() map[string]struct{} {
+	names := make(map[string]struct{})
+	for name, class := range toolClasses {
+		if class.Mutating {
+			names[name] = struct{}{}
+		}
+	}
+	return names
+}()
+
 // IsMutatingTool reports whether the named MCP tool mutates NATS/JetStream
 // state, publishes data, or writes local backup output.
 func IsMutatingTool(name string) bool {
@@ -35,3 +155,19 @@ func IsMutatingTool(name string) bool {
 func MutatingToolCount() int {
 	return len(mutatingToolNames)
 }
+
+// toolAnnotations returns the MCP annotations for the named tool, and false
+// when the tool is not classified.
+func toolAnnotations(name string) (mcp.ToolAnnotation, bool) {
+	class, ok := toolClasses[name]
+	if !ok {
+		return mcp.ToolAnnotation{}, false
+	}
+	return mcp.ToolAnnotation{
+		Title:           class.Title,
+		ReadOnlyHint:    mcp.ToBoolPtr(!class.Mutating),
+		DestructiveHint: mcp.ToBoolPtr(class.Mutating && class.Destructive),
+		IdempotentHint:  mcp.ToBoolPtr(class.Idempotent),
+		OpenWorldHint:   mcp.ToBoolPtr(class.OpenWorld),
+	}, true
+}
@@ -1,6 +1,12 @@
 package tools
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sinadarbouy/mcp-nats/internal/audit"
+	"github.com/sinadarbouy/mcp-nats/internal/logger"
+)
 
 func TestIsMutatingTool_mutatingSet(t *testing.T) {
 	for name := range mutatingToolNames {
@@ -56,3 +62,58 @@ func TestToolCount_readOnlySkipsMutating(t *testing.T) {
 		}
 	}
 }
+
+func TestToolClasses_coverCatalog(t *testing.T) {
+	logger.Initialize(logger.Config{Level: logger.LevelError})
+	n, err := NewNATSServerTools()
+	if err != nil {
+		t.Fatalf("NewNATSServerTools: %v", err)
+	}
+	rec := audit.NewRecorder(1)
+	defer rec.Close()
+	n.EnableAudit(rec)
+
+	catalog := make(map[string]bool)
+	for _, cat := range n.toolCategories() {
+		for _, tool := range cat.GetTools() {
+			catalog[tool.Tool.Name] = true
+			if _, ok := toolClasses[tool.Tool.Name]; !ok {
+				t.Errorf("tool %q has no entry in toolClasses", tool.Tool.Name)
+			}
+		}
+	}
+	for name := range toolClasses {
+		if !catalog[name] {
+			t.Errorf("toolClasses entry %q does not match any tool", name)
+		}
+	}
+}
+
+func TestRegisterTools_annotations(t *testing.T) {
+	logger.Initialize(logger.Config{Level: logger.LevelError})
+	n, err := NewNATSServerTools()
+	if err != nil {
+		t.Fatalf("NewNATSServerTools: %v", err)
+	}
+	s := server.NewMCPServer("test", "0.0.0")
+	RegisterTools(s, n, false)
+
+	for name, tool := range s.ListTools() {
+		a := tool.Tool.Annotations
+		if a.Title == "" || a.ReadOnlyHint == nil || a.DestructiveHint == nil || a.IdempotentHint == nil || a.OpenWorldHint == nil {
+			t.Fatalf("tool %q is missing annotations: %+v", name, a)
+		}
+		if *a.ReadOnlyHint == IsMutatingTool(name) {
+			t.Errorf("tool %q: readOnlyHint = %v, IsMutatingTool = %v", name, *a.ReadOnlyHint, IsMutatingTool(name))
+		}
+		if *a.DestructiveHint && *a.ReadOnlyHint {
+			t.Errorf("tool %q is both read-only and destructive", name)
+		}
+	}
+	if a := s.GetTool("kv_purge").Tool.Annotations; !*a.DestructiveHint {
+		t.Errorf("kv_purge: destructiveHint = false, want true")
+	}
+	if a := s.GetTool("stream_info").Tool.Annotations; !*a.ReadOnlyHint {
+		t.Errorf("stream_info: readOnlyHint = false, want true")
+	}
+}
@@ -0,0 +1,340 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/nats-io/nats.go"
+	"github.com/sinadarbouy/mcp-nats/internal/tracing"
+)
+
+// Limits of the request tool.
+const (
+	defaultRequestTimeout = 5 * time.Second
+	maxRequestTimeout     = time.Minute
+	defaultRequestStall   = 300 * time.Millisecond
+	maxRequestReplies     = 1000
+)
+
+// Request modes.
+const (
+	requestModeSingle  = "single"
+	requestModeReplies = "replies"
+)
+
+// Reasons a replies mode request stopped collecting.
+const (
+	stopMaxReplies   = "max_replies"
+	stopTimeout      = "timeout"
+	stopStall        = "stall"
+	stopNoResponders = "no_responders"
+)
+
+// RequestTools represents the tools that call NATS services with
+// request/reply
+type RequestTools struct {
+	nats *NATSServerTools
+}
+
+// NewRequestTools creates a new RequestTools instance
+func NewRequestTools(nats *NATSServerTools) *RequestTools {
+	return &RequestTools{
+		nats: nats,
+	}
+}
+
+// GetTools implements the ToolCategory interface
+func (r *RequestTools) GetTools() []Tool {
+	needsAccountName := r.nats.accountNameRequired()
+
+	return []Tool{
+		{
+			Tool: mcp.Tool{
+				Name: "request",
+				Description: "Sends a request and waits for the reply. " +
+					"In replies mode it gathers the replies of every responder until max_replies, the timeout, or a stall between replies, and reports the latency of each",
+				InputSchema: mcp.ToolInputSchema{
+					Type: "object",
+					Properties: func() map[string]interface{} {
+						props := map[string]interface{}{
+							"subject": map[string]interface{}{
+								"type":        "string",
+								"description": "Subject to send the request to",
+							},
+							"body": map[string]interface{}{
+								"type":        "string",
+								"description": "Request body",
+							},
+							"header": map[string]interface{}{
+								"type":        "array",
+								"items":       map[string]interface{}{"type": "string"},
+								"description": "Adds headers to the request, as Name:value",
+							},
+							"timeout": map[string]interface{}{
+								"type":        "string",
+								"description": fmt.Sprintf("How long to wait for replies (at most %s)", maxRequestTimeout),
+								"default":     defaultRequestTimeout.String(),
+							},
+							"mode": map[string]interface{}{
+								"type":        "string",
+								"enum":        []string{requestModeSingle, requestModeReplies},
+								"description": "single returns the first reply; replies gathers the replies of every responder",
+								"default":     requestModeSingle,
+							},
+							"max_replies": map[string]interface{}{
+								"type":        "integer",
+								"description": fmt.Sprintf("In replies mode, stop after this many replies; 0 waits for the timeout or a stall (at most %d)", maxRequestReplies),
+								"default":     0,
+							},
+							"stall": map[string]interface{}{
+								"type":        "string",
+								"description": "In replies mode, stop when no further reply arrives within this interval after the last one",
+								"default":     defaultRequestStall.String(),
+							},
+						}
+						if needsAccountName {
+							props["account_name"] = map[string]interface{}{
+								"type":        "string",
+								"description": "The NATS account to use (required for credentials-based authentication)",
+							}
+						}
+						return props
+					}(),
+					Required: func() []string {
+						if needsAccountName {
+							return []string{"account_name", "subject"}
+						}
+						return []string{"subject"}
+					}(),
+				},
+			},
+			Handler: r.requestHandler(),
+		},
+		r.traceTool(),
+	}
+}
+
+// requestOptions are the validated arguments of a request.
+type requestOptions struct {
+	mode       string
+	timeout    time.Duration
+	stall      time.Duration
+	maxReplies int
+}
+
+// receivedReply is a reply as returned by the request tool.
+type receivedReply struct {
+	sampledMessage
+	LatencyMS float64 `json:"latency_ms"`
+}
+
+// requestResult is the JSON result of the request tool.
+type requestResult struct {
+	Subject string          `json:"subject"`
+	Mode    string          `json:"mode"`
+	Stopped string          `json:"stopped,omitempty"`
+	Count   int             `json:"count"`
+	Replies []receivedReply `json:"replies"`
+}
+
+// requestOptionsFromArgs reads and validates the options of a request.
+func requestOptionsFromArgs(arguments map[string]interface{}) (requestOptions, error) {
+	opts := requestOptions{
+		mode:    requestModeSingle,
+		timeout: defaultRequestTimeout,
+		stall:   defaultRequestStall,
+	}
+	if mode, ok := arguments["mode"].(string); ok && mode != "" {
+		if mode != requestModeSingle && mode != requestModeReplies {
+			return opts, fmt.Errorf("invalid mode %q, must be %s or %s", mode, requestModeSingle, requestModeReplies)
+		}
+		opts.mode = mode
+	}
+	if t, ok := arguments["timeout"].(string); ok && t != "" {
+		timeout, err := time.ParseDuration(t)
+		if err != nil {
+			return opts, fmt.Errorf("invalid timeout: %w", err)
+		}
+		if timeout <= 0 || timeout > maxRequestTimeout {
+			return opts, fmt.Errorf("timeout must be positive and at most %s", maxRequestTimeout)
+		}
+		opts.timeout = timeout
+	}
+	if s, ok := arguments["stall"].(string); ok && s != "" {
+		stall, err := time.ParseDuration(s)
+		if err != nil {
+			return opts, fmt.Errorf("invalid stall: %w", err)
+		}
+		if stall <= 0 {
+			return opts, fmt.Errorf("stall must be positive")
+		}
+		opts.stall = stall
+	}
+	if n, ok := arguments["max_replies"].(float64); ok {
+		if n < 0 || n > maxRequestReplies {
+			return opts, fmt.Errorf("max_replies must be between 0 and %d", maxRequestReplies)
+		}
+		opts.maxReplies = int(n)
+	}
+	return opts, nil
+}
+
+// messageHeaders builds the headers of an outgoing message from its "Name:value"
+// header arguments and the trace context of ctx.
+func messageHeaders(ctx context.Context, arguments map[string]interface{}) (nats.Header, error) {
+	headers := nats.Header{}
+	values, _ := arguments["header"].([]interface{})
+	for _, value := range values {
+		header, _ := value.(string)
+		name, val, ok := strings.Cut(header, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid header %q, expected Name:value", header)
+		}
+		headers.Add(strings.TrimSpace(name), strings.TrimSpace(val))
+	}
+	for _, header := range tracing.HeaderArgs(ctx) {
+		name, val, _ := strings.Cut(header, ":")
+		headers.Set(name, val)
+	}
+	return headers, nil
+}
+
+func (r *RequestTools) requestHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		subject, ok := request.GetArguments()["subject"].(string)
+		if !ok || subject == "" {
+			return nil, fmt.Errorf("missing subject")
+		}
+		body, _ := request.GetArguments()["body"].(string)
+		opts, err := requestOptionsFromArgs(request.GetArguments())
+		if err != nil {
+			return nil, err
+		}
+		headers, err := messageHeaders(ctx, request.GetArguments())
+		if err != nil {
+			return nil, err
+		}
+
+		nc, err := r.nats.connect(ctx, request.GetArguments())
+		if err != nil {
+			return nil, err
+		}
+		defer nc.Close()
+
+		msg := &nats.Msg{Subject: subject, Data: []byte(body), Header: headers}
+		var result requestResult
+		if opts.mode == requestModeReplies {
+			result, err = gatherReplies(ctx, nc, msg, opts)
+		} else {
+			result, err = singleRequest(ctx, nc, msg, opts.timeout)
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Subject = subject
+		result.Mode = opts.mode
+
+		out, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode replies: %w", err)
+		}
+		return mcp.NewToolResultText(string(out)), nil
+	}
+}
+
+// singleRequest sends msg and waits up to timeout for the first reply.
+func singleRequest(ctx context.Context, nc *nats.Conn, msg *nats.Msg, timeout time.Duration) (requestResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	reply, err := nc.RequestMsgWithContext(ctx, msg)
+	switch {
+	case errors.Is(err, nats.ErrNoResponders):
+		return requestResult{}, fmt.Errorf("no responders on %s", msg.Subject)
+	case errors.Is(err, context.DeadlineExceeded):
+		return requestResult{}, fmt.Errorf("no reply on %s within %s", msg.Subject, timeout)
+	case err != nil:
+		return requestResult{}, fmt.Errorf("request to %s failed: %w", msg.Subject, err)
+	}
+	return requestResult{
+		Count:   1,
+		Replies: []receivedReply{newReceivedReply(reply, start)},
+	}, nil
+}
+
+// gatherReplies sends msg with a private reply subject and collects the
+// replies of every responder.
+func gatherReplies(ctx context.Context, nc *nats.Conn, msg *nats.Msg, opts requestOptions) (requestResult, error) {
+	msg.Reply = nc.NewRespInbox()
+	replies := make(chan *nats.Msg, maxRequestReplies+1)
+	sub, err := nc.ChanSubscribe(msg.Reply, replies)
+	if err != nil {
+		return requestResult{}, fmt.Errorf("failed to subscribe for replies: %w", err)
+	}
+	defer func() { _ = sub.Unsubscribe() }()
+
+	start := time.Now()
+	if err := nc.PublishMsg(msg); err != nil {
+		return requestResult{}, fmt.Errorf("request to %s failed: %w", msg.Subject, err)
+	}
+	if err := nc.Flush(); err != nil {
+		return requestResult{}, fmt.Errorf("request to %s failed: %w", msg.Subject, err)
+	}
+	return collectReplies(ctx, replies, start, opts), nil
+}
+
+// collectReplies reads replies until max_replies, the timeout or a stall
+// after the last reply, or until ctx is done.
+func collectReplies(ctx context.Context, replies <-chan *nats.Msg, start time.Time, opts requestOptions) requestResult {
+	result := requestResult{Replies: []receivedReply{}}
+	timeout := time.NewTimer(opts.timeout - time.Since(start))
+	defer timeout.Stop()
+	// The stall interval only starts with the first reply.
+	var stall <-chan time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			result.Stopped = stopCancelled
+			return result
+		case <-timeout.C:
+			result.Stopped = stopTimeout
+			return result
+		case <-stall:
+			result.Stopped = stopStall
+			return result
+		case reply := <-replies:
+			if isNoResponders(reply) {
+				result.Stopped = stopNoResponders
+				return result
+			}
+			result.Replies = append(result.Replies, newReceivedReply(reply, start))
+			result.Count++
+			if opts.maxReplies > 0 && result.Count >= opts.maxReplies {
+				result.Stopped = stopMaxReplies
+				return result
+			}
+			stall = time.After(opts.stall)
+		}
+	}
+}
+
+// isNoResponders reports whether msg is the server's status message for a
+// request that had no subscribers.
+func isNoResponders(msg *nats.Msg) bool {
+	return len(msg.Data) == 0 && msg.Header.Get("Status") == "503"
+}
+
+func newReceivedReply(msg *nats.Msg, start time.Time) receivedReply {
+	return receivedReply{
+		sampledMessage: sampleMessage(msg),
+		LatencyMS:      float64(time.Since(start).Microseconds()) / 1000,
+	}
+}
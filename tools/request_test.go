@@ -0,0 +1,141 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/nats-io/nats.go"
+	"github.com/sinadarbouy/mcp-nats/internal/logger"
+	"github.com/sinadarbouy/mcp-nats/tools/common"
+)
+
+// testToolHandler returns the wrapped handler of the named tool for a server
+// without authentication at url.
+func testToolHandler(t *testing.T, url string, category func(*NATSServerTools) ToolCategory, name string) func(map[string]any) (string, error) {
+	t.Helper()
+	handler := testToolHandlerContext(t, url, category, name)
+	return func(args map[string]any) (string, error) {
+		return handler(context.Background(), args)
+	}
+}
+
+// testToolHandlerContext is testToolHandler for calls that need a context,
+// such as cancelled calls.
+func testToolHandlerContext(t *testing.T, url string, category func(*NATSServerTools) ToolCategory, name string) func(context.Context, map[string]any) (string, error) {
+	t.Helper()
+	logger.Initialize(logger.Config{Level: logger.LevelError})
+	n, err := NewNATSServerToolsWithConnection(common.Connection{Name: "default", URL: url, NoAuthentication: true})
+	if err != nil {
+		t.Fatalf("NewNATSServerToolsWithConnection: %v", err)
+	}
+	for _, tool := range category(n).GetTools() {
+		if tool.Tool.Name != name {
+			continue
+		}
+		handler := n.wrapHandler(tool.Tool, tool.Handler)
+		return func(ctx context.Context, args map[string]any) (string, error) {
+			result, err := handler(ctx, mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}})
+			if err != nil {
+				return "", err
+			}
+			return result.Content[0].(mcp.TextContent).Text, nil
+		}
+	}
+	t.Fatalf("no %s tool", name)
+	return nil
+}
+
+// fakeNATSCLI puts a nats executable on PATH that prints its arguments, one
+// per line.
+func fakeNATSCLI(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	script := "#!/bin/sh\nfor arg in \"$@\"; do echo \"$arg\"; done\n"
+	if err := os.WriteFile(filepath.Join(dir, "nats"), []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write fake nats CLI: %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// testCLIToolCaller puts the fake nats CLI on PATH and returns a caller of
+// the tools of category that adds account_name and stream to the arguments
+// and returns the printed command.
+func testCLIToolCaller(t *testing.T, category func(*NATSServerTools) ToolCategory, stream string) func(tool string, args map[string]any) (string, error) {
+	t.Helper()
+	fakeNATSCLI(t)
+	return func(tool string, args map[string]any) (string, error) {
+		t.Helper()
+		args["account_name"], args["stream"] = "A", stream
+		return testToolHandler(t, "nats://test:4222", category, tool)(args)
+	}
+}
+
+func TestRequestTool_singleAndReplies(t *testing.T) {
+	url, _ := startTestServer(t)
+	for _, name := range []string{"east", "west"} {
+		nc, err := nats.Connect(url)
+		if err != nil {
+			t.Fatalf("connect responder: %v", err)
+		}
+		t.Cleanup(nc.Close)
+		name := name
+		if _, err := nc.Subscribe("svc.echo", func(msg *nats.Msg) {
+			reply := nats.NewMsg(msg.Reply)
+			reply.Header.Set("Responder", name)
+			reply.Data = []byte(msg.Header.Get("Greeting") + " " + string(msg.Data))
+			_ = msg.RespondMsg(reply)
+		}); err != nil {
+			t.Fatalf("subscribe responder: %v", err)
+		}
+		if err := nc.Flush(); err != nil {
+			t.Fatalf("flush responder: %v", err)
+		}
+	}
+	call := testToolHandler(t, url, (*NATSServerTools).RequestTools, "request")
+	decode := func(out string) requestResult {
+		t.Helper()
+		var result requestResult
+		if err := json.Unmarshal([]byte(out), &result); err != nil {
+			t.Fatalf("invalid result %q: %v", out, err)
+		}
+		return result
+	}
+
+	out, err := call(map[string]any{"subject": "svc.echo", "body": "world", "header": []any{"Greeting: hello"}})
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	result := decode(out)
+	if result.Count != 1 || result.Replies[0].Payload != "hello world" || result.Replies[0].Headers["Responder"] == nil {
+		t.Fatalf("unexpected single reply: %s", out)
+	}
+
+	out, err = call(map[string]any{"subject": "svc.echo", "mode": "replies", "stall": "200ms"})
+	if err != nil {
+		t.Fatalf("request replies: %v", err)
+	}
+	result = decode(out)
+	if result.Count != 2 || result.Stopped != stopStall {
+		t.Fatalf("expected both responders before the stall: %s", out)
+	}
+	out, err = call(map[string]any{"subject": "svc.echo", "mode": "replies", "max_replies": float64(1)})
+	if err != nil || decode(out).Stopped != stopMaxReplies {
+		t.Fatalf("expected max_replies to stop the request: %s, %v", out, err)
+	}
+
+	if _, err := call(map[string]any{"subject": "svc.missing"}); err == nil || !strings.Contains(err.Error(), "no responders") {
+		t.Fatalf("expected no responders, got %v", err)
+	}
+	out, err = call(map[string]any{"subject": "svc.missing", "mode": "replies"})
+	if err != nil || decode(out).Stopped != stopNoResponders {
+		t.Fatalf("expected replies mode to report no responders: %s, %v", out, err)
+	}
+	if _, err := call(map[string]any{"subject": "svc.echo", "header": []any{"nocolon"}}); err == nil {
+		t.Fatalf("expected an invalid header to be rejected")
+	}
+}
@@ -0,0 +1,473 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/sinadarbouy/mcp-nats/internal/ratelimit"
+)
+
+const resourceScheme = "nats://"
+
+// ResourceLimits guard resources/read, which is not covered by the tool
+// middlewares. They are set by the operator.
+type ResourceLimits struct {
+	// MaxObjectSize is the largest object in bytes an object resource
+	// returns; larger objects are refused.
+	MaxObjectSize int
+	// Limiter charges every read as a call of the resources/read tool; nil
+	// disables rate limiting.
+	Limiter *ratelimit.Limiter
+	// Timeout cancels reads running longer; 0 disables it.
+	Timeout time.Duration
+}
+
+// DefaultResourceLimits are the resource limits unless configured otherwise.
+var DefaultResourceLimits = ResourceLimits{
+	MaxObjectSize: 8 * 1024 * 1024,
+}
+
+// ResourceCategory represents a group of related MCP resource templates
+type ResourceCategory interface {
+	GetResourceTemplates() []ResourceTemplate
+}
+
+// ResourceTemplate combines an MCP resource template with its handler
+type ResourceTemplate struct {
+	Template mcp.ResourceTemplate
+	Handler  server.ResourceTemplateHandlerFunc
+}
+
+// Register registers a single resource template with the MCP server
+func (r *ResourceTemplate) Register(mcp *server.MCPServer) {
+	mcp.AddResourceTemplate(r.Template, r.Handler)
+}
+
+// RegisterResources registers all resource templates with the MCP server.
+// Reads are subject to the resource limits of n.
+func RegisterResources(mcp *server.MCPServer, n *NATSServerTools) {
+	for _, template := range n.ResourceTools().GetResourceTemplates() {
+		template.Handler = n.limitResourceHandler(template.Handler)
+		template.Register(mcp)
+	}
+}
+
+// limitResourceHandler applies the rate limit and timeout of the current
+// resource limits to a resource handler.
+func (n *NATSServerTools) limitResourceHandler(next server.ResourceTemplateHandlerFunc) server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		limits := n.currentResourceLimits()
+		if limits.Limiter != nil {
+			method := string(mcp.MethodResourcesRead)
+			if decision := limits.Limiter.Allow(method, rateLimitKeys(ctx), 0); !decision.Allowed {
+				text, _ := rateLimitMessage(method, decision)
+				return nil, errors.New(text)
+			}
+		}
+		if limits.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, limits.Timeout)
+			defer cancel()
+		}
+		return next(ctx, request)
+	}
+}
+
+// ResourceTools exposes JetStream assets (streams, stream messages, KV entries
+// and objects) as MCP resources addressed by nats:// URIs.
+type ResourceTools struct {
+	nats *NATSServerTools
+}
+
+// NewResourceTools creates a new ResourceTools instance
+func NewResourceTools(nats *NATSServerTools) *ResourceTools {
+	return &ResourceTools{
+		nats: nats,
+	}
+}
+
+// Resource URIs for the assets exposed by ResourceTools.
+func streamURI(account, stream string) string {
+	return fmt.Sprintf("%s%s/stream/%s", resourceScheme, url.PathEscape(account), url.PathEscape(stream))
+}
+
+func kvBucketURI(account, bucket string) string {
+	return fmt.Sprintf("%s%s/kv/%s", resourceScheme, url.PathEscape(account), url.PathEscape(bucket))
+}
+
+func kvKeyURI(account, bucket, key string) string {
+	return kvBucketURI(account, bucket) + "/" + url.PathEscape(key)
+}
+
+func objectBucketURI(account, bucket string) string {
+	return fmt.Sprintf("%s%s/object/%s", resourceScheme, url.PathEscape(account), url.PathEscape(bucket))
+}
+
+func objectURI(account, bucket, name string) string {
+	return objectBucketURI(account, bucket) + "/" + url.PathEscape(name)
+}
+
+// GetResourceTemplates implements the ResourceCategory interface
+func (r *ResourceTools) GetResourceTemplates() []ResourceTemplate {
+	return []ResourceTemplate{
+		{
+			Template: mcp.NewResourceTemplate(
+				resourceScheme+"{account}/streams",
+				"streams",
+				mcp.WithTemplateDescription("Index of the streams in an account, as a JSON list of stream resource URIs"),
+				mcp.WithTemplateMIMEType("application/json"),
+			),
+			Handler: r.streamIndexHandler(),
+		},
+		{
+			Template: mcp.NewResourceTemplate(
+				resourceScheme+"{account}/stream/{name}",
+				"stream",
+				mcp.WithTemplateDescription("Configuration and state of a JetStream stream"),
+				mcp.WithTemplateMIMEType("application/json"),
+			),
+			Handler: r.streamHandler(),
+		},
+		{
+			Template: mcp.NewResourceTemplate(
+				resourceScheme+"{account}/stream/{name}/msg/{seq}",
+				"stream-message",
+				mcp.WithTemplateDescription("A single message stored in a stream, by sequence"),
+				mcp.WithTemplateMIMEType("application/json"),
+			),
+			Handler: r.streamMessageHandler(),
+		},
+		{
+			Template: mcp.NewResourceTemplate(
+				resourceScheme+"{account}/kv",
+				"kv-buckets",
+				mcp.WithTemplateDescription("Index of the KV buckets in an account, as a JSON list of bucket resource URIs"),
+				mcp.WithTemplateMIMEType("application/json"),
+			),
+			Handler: r.kvBucketIndexHandler(),
+		},
+		{
+			Template: mcp.NewResourceTemplate(
+				resourceScheme+"{account}/kv/{bucket}",
+				"kv-bucket",
+				mcp.WithTemplateDescription("Index of the keys in a KV bucket, as a JSON list of key resource URIs"),
+				mcp.WithTemplateMIMEType("application/json"),
+			),
+			Handler: r.kvKeyIndexHandler(),
+		},
+		{
+			Template: mcp.NewResourceTemplate(
+				resourceScheme+"{account}/kv/{bucket}/{key}",
+				"kv-entry",
+				mcp.WithTemplateDescription("The current value of a key in a KV bucket"),
+			),
+			Handler: r.kvEntryHandler(),
+		},
+		{
+			Template: mcp.NewResourceTemplate(
+				resourceScheme+"{account}/object",
+				"object-buckets",
+				mcp.WithTemplateDescription("Index of the object store buckets in an account, as a JSON list of bucket resource URIs"),
+				mcp.WithTemplateMIMEType("application/json"),
+			),
+			Handler: r.objectBucketIndexHandler(),
+		},
+		{
+			Template: mcp.NewResourceTemplate(
+				resourceScheme+"{account}/object/{bucket}",
+				"object-bucket",
+				mcp.WithTemplateDescription("Index of the objects in an object store bucket, as a JSON list of object resource URIs"),
+				mcp.WithTemplateMIMEType("application/json"),
+			),
+			Handler: r.objectIndexHandler(),
+		},
+		{
+			Template: mcp.NewResourceTemplate(
+				resourceScheme+"{account}/object/{bucket}/{name}",
+				"object",
+				mcp.WithTemplateDescription("The content of an object in an object store bucket"),
+			),
+			Handler: r.objectHandler(),
+		},
+	}
+}
+
+// resourceArg returns a URI template variable. The template matcher has
+// already percent-decoded it.
+func resourceArg(request mcp.ReadResourceRequest, name string) (string, error) {
+	var value string
+	switch v := request.Params.Arguments[name].(type) {
+	case string:
+		value = v
+	case []string:
+		if len(v) > 0 {
+			value = v[0]
+		}
+	}
+	if value == "" {
+		return "", fmt.Errorf("missing %s in resource URI %s", name, request.Params.URI)
+	}
+	return value, nil
+}
+
+// runResourceCommand runs a NATS CLI command for the account named in the
+// resource URI.
+func (r *ResourceTools) runResourceCommand(ctx context.Context, request mcp.ReadResourceRequest, args ...string) (string, string, error) {
+	account, err := resourceArg(request, "account")
+	if err != nil {
+		return "", "", err
+	}
+	executor, err := r.nats.GetExecutor(ctx, account)
+	if err != nil {
+		return "", "", err
+	}
+	output, err := executor.ExecuteCommandContext(ctx, args...)
+	return account, output, err
+}
+
+// outputLines returns the non-empty trimmed lines of CLI output.
+func outputLines(output string) []string {
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func jsonContents(uri string, v any) ([]mcp.ResourceContents, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{URI: uri, MIMEType: "application/json", Text: string(data)},
+	}, nil
+}
+
+func (r *ResourceTools) streamIndexHandler() server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		account, output, err := r.runResourceCommand(ctx, request, "stream", "ls", "--names")
+		if err != nil {
+			return nil, err
+		}
+		uris := []string{}
+		for _, name := range outputLines(output) {
+			uris = append(uris, streamURI(account, name))
+		}
+		return jsonContents(request.Params.URI, uris)
+	}
+}
+
+func (r *ResourceTools) streamHandler() server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		stream, err := resourceArg(request, "name")
+		if err != nil {
+			return nil, err
+		}
+		_, output, err := r.runResourceCommand(ctx, request, "stream", "info", stream, "--json")
+		if err != nil {
+			return nil, err
+		}
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{URI: request.Params.URI, MIMEType: "application/json", Text: output},
+		}, nil
+	}
+}
+
+func (r *ResourceTools) streamMessageHandler() server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		stream, err := resourceArg(request, "name")
+		if err != nil {
+			return nil, err
+		}
+		seq, err := resourceArg(request, "seq")
+		if err != nil {
+			return nil, err
+		}
+		_, output, err := r.runResourceCommand(ctx, request, "stream", "get", stream, seq, "--json")
+		if err != nil {
+			return nil, err
+		}
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{URI: request.Params.URI, MIMEType: "application/json", Text: output},
+		}, nil
+	}
+}
+
+func (r *ResourceTools) kvBucketIndexHandler() server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		account, output, err := r.runResourceCommand(ctx, request, "kv", "ls", "--names")
+		if err != nil {
+			return nil, err
+		}
+		uris := []string{}
+		for _, bucket := range outputLines(output) {
+			uris = append(uris, kvBucketURI(account, bucket))
+		}
+		return jsonContents(request.Params.URI, uris)
+	}
+}
+
+func (r *ResourceTools) kvKeyIndexHandler() server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		bucket, err := resourceArg(request, "bucket")
+		if err != nil {
+			return nil, err
+		}
+		account, output, err := r.runResourceCommand(ctx, request, "kv", "ls", bucket)
+		if err != nil {
+			return nil, err
+		}
+		uris := []string{}
+		for _, key := range outputLines(output) {
+			uris = append(uris, kvKeyURI(account, bucket, key))
+		}
+		return jsonContents(request.Params.URI, uris)
+	}
+}
+
+func (r *ResourceTools) kvEntryHandler() server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		bucket, err := resourceArg(request, "bucket")
+		if err != nil {
+			return nil, err
+		}
+		key, err := resourceArg(request, "key")
+		if err != nil {
+			return nil, err
+		}
+		_, output, err := r.runResourceCommand(ctx, request, "kv", "get", bucket, key, "--raw")
+		if err != nil {
+			return nil, err
+		}
+		return []mcp.ResourceContents{binaryOrText(request.Params.URI, "", []byte(output))}, nil
+	}
+}
+
+func (r *ResourceTools) objectBucketIndexHandler() server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		account, output, err := r.runResourceCommand(ctx, request, "object", "ls", "--names")
+		if err != nil {
+			return nil, err
+		}
+		uris := []string{}
+		for _, bucket := range outputLines(output) {
+			uris = append(uris, objectBucketURI(account, bucket))
+		}
+		return jsonContents(request.Params.URI, uris)
+	}
+}
+
+func (r *ResourceTools) objectIndexHandler() server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		bucket, err := resourceArg(request, "bucket")
+		if err != nil {
+			return nil, err
+		}
+		account, output, err := r.runResourceCommand(ctx, request, "object", "ls", bucket, "--json")
+		if err != nil {
+			return nil, err
+		}
+		var objects []struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal([]byte(output), &objects); err != nil {
+			return nil, fmt.Errorf("failed to parse object list: %w", err)
+		}
+		uris := []string{}
+		for _, obj := range objects {
+			uris = append(uris, objectURI(account, bucket, obj.Name))
+		}
+		return jsonContents(request.Params.URI, uris)
+	}
+}
+
+func (r *ResourceTools) objectHandler() server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		account, err := resourceArg(request, "account")
+		if err != nil {
+			return nil, err
+		}
+		bucket, err := resourceArg(request, "bucket")
+		if err != nil {
+			return nil, err
+		}
+		name, err := resourceArg(request, "name")
+		if err != nil {
+			return nil, err
+		}
+
+		executor, err := r.nats.GetExecutor(ctx, account)
+		if err != nil {
+			return nil, err
+		}
+		nc, err := executor.Connect()
+		if err != nil {
+			return nil, err
+		}
+		defer nc.Close()
+		js, err := jetstream.New(nc)
+		if err != nil {
+			return nil, err
+		}
+		store, err := js.ObjectStore(ctx, bucket)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open object store %s: %w", bucket, err)
+		}
+		// Objects are returned in one message, so large ones are refused
+		// before they are downloaded.
+		maxSize := r.nats.currentResourceLimits().MaxObjectSize
+		info, err := store.GetInfo(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get object %s: %w", name, err)
+		}
+		if info.Size > uint64(maxSize) {
+			return nil, fmt.Errorf("object %s is %d bytes, above the %d byte limit of object resources", name, info.Size, maxSize)
+		}
+
+		result, err := store.Get(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get object %s: %w", name, err)
+		}
+		defer func() { _ = result.Close() }()
+		// The object may have been replaced since its info was read.
+		data, err := io.ReadAll(io.LimitReader(result, int64(maxSize)+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read object %s: %w", name, err)
+		}
+		if len(data) > maxSize {
+			return nil, fmt.Errorf("object %s is above the %d byte limit of object resources", name, maxSize)
+		}
+		return []mcp.ResourceContents{binaryOrText(request.Params.URI, mime.TypeByExtension(path.Ext(name)), data)}, nil
+	}
+}
+
+// binaryOrText returns UTF-8 data as text contents and anything else as a
+// base64 blob.
+func binaryOrText(uri, mimeType string, data []byte) mcp.ResourceContents {
+	if utf8.Valid(data) {
+		if mimeType == "" {
+			mimeType = "text/plain"
+		}
+		return mcp.TextResourceContents{URI: uri, MIMEType: mimeType, Text: string(data)}
+	}
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	return mcp.BlobResourceContents{URI: uri, MIMEType: mimeType, Blob: base64.StdEncoding.EncodeToString(data)}
+}
@@ -0,0 +1,138 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/sinadarbouy/mcp-nats/internal/logger"
+	"github.com/sinadarbouy/mcp-nats/internal/ratelimit"
+	"github.com/sinadarbouy/mcp-nats/tools/common"
+)
+
+func TestResourceTemplates_matchExactlyOne(t *testing.T) {
+	templates := NewResourceTools(nil).GetResourceTemplates()
+
+	cases := map[string]string{
+		"nats://A/streams":                 "streams",
+		"nats://A/stream/ORDERS":           "stream",
+		"nats://A/stream/ORDERS/msg/42":    "stream-message",
+		"nats://A/kv":                      "kv-buckets",
+		"nats://A/kv/config":               "kv-bucket",
+		"nats://A/kv/config/app.timeout":   "kv-entry",
+		"nats://A/object":                  "object-buckets",
+		"nats://A/object/files":            "object-bucket",
+		"nats://A/object/files/report.pdf": "object",
+		kvKeyURI("A", "config", "a/b"):     "kv-entry",
+	}
+	for uri, want := range cases {
+		var matched []string
+		for _, tmpl := range templates {
+			if tmpl.Template.URITemplate.Regexp().MatchString(uri) {
+				matched = append(matched, tmpl.Template.Name)
+			}
+		}
+		if len(matched) != 1 || matched[0] != want {
+			t.Errorf("%s matched %v, want [%s]", uri, matched, want)
+		}
+	}
+}
+
+func TestResourceArg_roundTripsEscapedValues(t *testing.T) {
+	uri := objectURI("A", "files", "reports/2024 q1%.pdf")
+	var tmpl mcp.ResourceTemplate
+	for _, rt := range NewResourceTools(nil).GetResourceTemplates() {
+		if rt.Template.Name == "object" {
+			tmpl = rt.Template
+		}
+	}
+
+	request := mcp.ReadResourceRequest{}
+	request.Params.URI = uri
+	request.Params.Arguments = map[string]any{}
+	for name, value := range tmpl.URITemplate.Match(uri) {
+		request.Params.Arguments[name] = value.V
+	}
+
+	got, err := resourceArg(request, "name")
+	if err != nil {
+		t.Fatalf("resourceArg: %v", err)
+	}
+	if got != "reports/2024 q1%.pdf" {
+		t.Fatalf("resourceArg(name) = %q, want %q", got, "reports/2024 q1%.pdf")
+	}
+}
+
+func TestObjectResource_limits(t *testing.T) {
+	url, _ := startTestServer(t)
+	logger.Initialize(logger.Config{Level: logger.LevelError})
+	n, err := NewNATSServerToolsWithConnection(common.Connection{Name: "default", URL: url, NoAuthentication: true})
+	if err != nil {
+		t.Fatalf("NewNATSServerToolsWithConnection: %v", err)
+	}
+
+	nc, err := nats.Connect(url)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer nc.Close()
+	js, err := jetstream.New(nc)
+	if err != nil {
+		t.Fatalf("jetstream: %v", err)
+	}
+	store, err := js.CreateObjectStore(context.Background(), jetstream.ObjectStoreConfig{Bucket: "files"})
+	if err != nil {
+		t.Fatalf("create object store: %v", err)
+	}
+	if _, err := store.PutBytes(context.Background(), "report.txt", []byte("quarterly numbers")); err != nil {
+		t.Fatalf("put object: %v", err)
+	}
+
+	var handler server.ResourceTemplateHandlerFunc
+	for _, rt := range n.ResourceTools().GetResourceTemplates() {
+		if rt.Template.Name == "object" {
+			handler = n.limitResourceHandler(rt.Handler)
+		}
+	}
+	s := server.NewMCPServer("test", "0.0.0")
+	ctx := s.WithContext(context.Background(), &testSession{id: "s1"})
+	read := func() ([]mcp.ResourceContents, error) {
+		request := mcp.ReadResourceRequest{}
+		request.Params.URI = objectURI("A", "files", "report.txt")
+		request.Params.Arguments = map[string]any{"account": "A", "bucket": "files", "name": "report.txt"}
+		return handler(ctx, request)
+	}
+
+	contents, err := read()
+	if err != nil {
+		t.Fatalf("read object: %v", err)
+	}
+	if text, ok := contents[0].(mcp.TextResourceContents); !ok || text.Text != "quarterly numbers" {
+		t.Fatalf("unexpected contents %#v", contents[0])
+	}
+
+	n.SetResourceLimits(ResourceLimits{MaxObjectSize: 8})
+	if _, err := read(); err == nil || !strings.Contains(err.Error(), "17 bytes, above the 8 byte limit") {
+		t.Fatalf("expected the object to be refused, got %v", err)
+	}
+
+	n.SetResourceLimits(ResourceLimits{MaxObjectSize: 1024, Timeout: time.Nanosecond})
+	if _, err := read(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the read to time out, got %v", err)
+	}
+
+	limiter := ratelimit.New(ratelimit.Config{Default: ratelimit.Limit{CallsPerSecond: 0.001, CallBurst: 1}})
+	n.SetResourceLimits(ResourceLimits{MaxObjectSize: 1024, Limiter: limiter})
+	if _, err := read(); err != nil {
+		t.Fatalf("first read: %v", err)
+	}
+	if _, err := read(); err == nil || !strings.Contains(err.Error(), "rate limit exceeded for resources/read (calls per session)") {
+		t.Fatalf("expected the second read to be throttled, got %v", err)
+	}
+}
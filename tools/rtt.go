@@ -77,7 +77,7 @@ func (r *RTTTools) rttHandler() server.ToolHandlerFunc {
 			args = append(args, strconv.Itoa(iterations))
 		}
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
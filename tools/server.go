@@ -6,7 +6,7 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
-	"github.com/sinadarbouy/mcp-nats/tools/common"
+	mcpnats "github.com/sinadarbouy/mcp-nats"
 )
 
 // ServerTools represents all NATS server-related tools
@@ -23,7 +23,7 @@ func NewServerTools(nats *NATSServerTools) *ServerTools {
 
 // isAccountNameRequired determines if account_name is required based on auth strategy
 func (s *ServerTools) isAccountNameRequired() bool {
-	return common.IsAccountNameRequired()
+	return s.nats.accountNameRequired()
 }
 
 // GetTools implements the ToolCategory interface
@@ -31,7 +31,7 @@ func (s *ServerTools) GetTools() []Tool {
 	// Determine if we need account_name based on authentication strategy
 	needsAccountName := s.isAccountNameRequired()
 
-	return []Tool{
+	return append([]Tool{
 		{
 			Tool: mcp.Tool{
 				Name:        "server_list",
@@ -125,7 +125,7 @@ func (s *ServerTools) GetTools() []Tool {
 			},
 			Handler: s.serverPingHandler(),
 		},
-	}
+	}, append(s.reportTools(), s.checkTools()...)...)
 }
 
 // nats server list
@@ -135,7 +135,7 @@ func (s *ServerTools) GetTools() []Tool {
 //	[<expect>]  How many servers to expect
 func (s *ServerTools) serverListHandler() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		accountName, err := common.DetermineAccountName(request.GetArguments())
+		accountName, err := mcpnats.DetermineAccountName(ctx, request.GetArguments())
 		if err != nil {
 			return nil, err
 		}
@@ -152,7 +152,7 @@ func (s *ServerTools) serverListHandler() server.ToolHandlerFunc {
 			args = append(args, strconv.Itoa(expect))
 		}
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -167,7 +167,7 @@ func (s *ServerTools) serverListHandler() server.ToolHandlerFunc {
 //	[<server>]  Server ID or Name to inspect
 func (s *ServerTools) serverInfoHandler() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		accountName, err := common.DetermineAccountName(request.GetArguments())
+		accountName, err := mcpnats.DetermineAccountName(ctx, request.GetArguments())
 		if err != nil {
 			return nil, err
 		}
@@ -183,7 +183,7 @@ func (s *ServerTools) serverInfoHandler() server.ToolHandlerFunc {
 		if server, ok := request.GetArguments()["server"].(string); ok {
 			args = append(args, server)
 		}
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -198,7 +198,7 @@ func (s *ServerTools) serverInfoHandler() server.ToolHandlerFunc {
 //	[<expect>]  How many servers to expect
 func (s *ServerTools) serverPingHandler() server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		accountName, err := common.DetermineAccountName(request.GetArguments())
+		accountName, err := mcpnats.DetermineAccountName(ctx, request.GetArguments())
 		if err != nil {
 			return nil, err
 		}
@@ -214,7 +214,7 @@ func (s *ServerTools) serverPingHandler() server.ToolHandlerFunc {
 		if expect, ok := request.GetArguments()["expect"].(string); ok {
 			args = append(args, expect)
 		}
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,263 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	mcpnats "github.com/sinadarbouy/mcp-nats"
+)
+
+// checkStatuses are the Nagios statuses by `nats server check` exit code.
+var checkStatuses = []string{"OK", "WARNING", "CRITICAL", "UNKNOWN"}
+
+// checkOption is a threshold or selector of a check, passed to the CLI as
+// the flag named like the argument with dashes.
+type checkOption struct {
+	arg         string
+	kind        string
+	description string
+	// negatable boolean options are passed as --no-<flag> when false.
+	negatable bool
+}
+
+// serverCheck describes a tool wrapping one `nats server check` suite.
+type serverCheck struct {
+	name        string
+	suite       string
+	description string
+	options     []checkOption
+	required    []string
+}
+
+var serverChecks = []serverCheck{
+	{
+		name:        "server_check_connection",
+		suite:       "connection",
+		description: "Checks connecting, round trip and request times to the NATS server",
+		options: []checkOption{
+			{arg: "connect_warn", kind: "string", description: "Warning threshold for establishing the connection, e.g. 500ms"},
+			{arg: "connect_critical", kind: "string", description: "Critical threshold for establishing the connection"},
+			{arg: "rtt_warn", kind: "string", description: "Warning threshold for the round trip time"},
+			{arg: "rtt_critical", kind: "string", description: "Critical threshold for the round trip time"},
+			{arg: "req_warn", kind: "string", description: "Warning threshold for a full request round trip"},
+			{arg: "req_critical", kind: "string", description: "Critical threshold for a full request round trip"},
+		},
+	},
+	{
+		name:        "server_check_stream",
+		suite:       "stream",
+		description: "Checks the replicas, sources, mirror lag and message counts of a stream",
+		options: []checkOption{
+			{arg: "stream", kind: "string", description: "The stream to check"},
+			{arg: "peer_expect", kind: "integer", description: "Number of replicas to expect"},
+			{arg: "peer_lag_critical", kind: "integer", description: "Critical threshold for replica lag, in operations"},
+			{arg: "peer_seen_critical", kind: "string", description: "Critical threshold for how long ago a replica was seen, e.g. 10s"},
+			{arg: "lag_critical", kind: "integer", description: "Critical threshold for source or mirror lag, in operations"},
+			{arg: "seen_critical", kind: "string", description: "Critical threshold for how long ago a source or mirror was seen"},
+			{arg: "min_sources", kind: "integer", description: "Minimum number of sources to expect"},
+			{arg: "max_sources", kind: "integer", description: "Maximum number of sources to expect"},
+			{arg: "msgs_warn", kind: "integer", description: "Warn when the stream holds fewer messages"},
+			{arg: "msgs_critical", kind: "integer", description: "Critical when the stream holds fewer messages"},
+			{arg: "subjects_warn", kind: "integer", description: "Warning threshold for the number of subjects"},
+			{arg: "subjects_critical", kind: "integer", description: "Critical threshold for the number of subjects"},
+		},
+		required: []string{"stream", "peer_expect"},
+	},
+	{
+		name:        "server_check_consumer",
+		suite:       "consumer",
+		description: "Checks the outstanding acks, waiting pulls, pending messages and delivery activity of a consumer",
+		options: []checkOption{
+			{arg: "stream", kind: "string", description: "The stream of the consumer"},
+			{arg: "consumer", kind: "string", description: "The consumer to check"},
+			{arg: "outstanding_ack_critical", kind: "integer", description: "Maximum number of outstanding acks"},
+			{arg: "waiting_critical", kind: "integer", description: "Maximum number of waiting pulls"},
+			{arg: "unprocessed_critical", kind: "integer", description: "Maximum number of unprocessed messages"},
+			{arg: "last_delivery_critical", kind: "string", description: "Maximum time since the last delivery, e.g. 5m"},
+			{arg: "last_ack_critical", kind: "string", description: "Maximum time since the last acknowledgement"},
+			{arg: "redelivery_critical", kind: "integer", description: "Maximum number of redeliveries"},
+			{arg: "pinned", kind: "boolean", description: "Require a pinned client for every priority group"},
+		},
+		required: []string{"stream", "consumer"},
+	},
+	{
+		name:        "server_check_kv",
+		suite:       "kv",
+		description: "Checks a KV bucket exists, its number of values and the presence of a key",
+		options: []checkOption{
+			{arg: "bucket", kind: "string", description: "The bucket to check"},
+			{arg: "values_warn", kind: "integer", description: "Warning threshold for the number of values"},
+			{arg: "values_critical", kind: "integer", description: "Critical threshold for the number of values"},
+			{arg: "key", kind: "string", description: "Require this key to have a value"},
+		},
+		required: []string{"bucket"},
+	},
+	{
+		name:        "server_check_jetstream",
+		suite:       "jetstream",
+		description: "Checks the JetStream usage of the account against its limits, in percent",
+		options: []checkOption{
+			{arg: "mem_warn", kind: "integer", description: "Warning threshold for memory storage usage"},
+			{arg: "mem_critical", kind: "integer", description: "Critical threshold for memory storage usage"},
+			{arg: "store_warn", kind: "integer", description: "Warning threshold for file storage usage"},
+			{arg: "store_critical", kind: "integer", description: "Critical threshold for file storage usage"},
+			{arg: "streams_warn", kind: "integer", description: "Warning threshold for the number of streams"},
+			{arg: "streams_critical", kind: "integer", description: "Critical threshold for the number of streams"},
+			{arg: "consumers_warn", kind: "integer", description: "Warning threshold for the number of consumers"},
+			{arg: "consumers_critical", kind: "integer", description: "Critical threshold for the number of consumers"},
+			{arg: "replicas", kind: "boolean", description: "Check that every stream has healthy replicas", negatable: true},
+		},
+	},
+	{
+		name:        "server_check_meta",
+		suite:       "meta",
+		description: "Checks the JetStream meta cluster has a leader and healthy peers. Requires system account permissions",
+		options: []checkOption{
+			{arg: "expect", kind: "integer", description: "Number of servers to expect in the meta cluster"},
+			{arg: "lag_critical", kind: "integer", description: "Critical threshold for peer lag, in operations"},
+			{arg: "seen_critical", kind: "string", description: "Critical threshold for how long ago a peer was seen, e.g. 10s"},
+		},
+		required: []string{"expect", "lag_critical", "seen_critical"},
+	},
+	{
+		name:        "server_check_message",
+		suite:       "message",
+		description: "Checks the last message on a stream subject is present, recent enough and matches the expected content",
+		options: []checkOption{
+			{arg: "stream", kind: "string", description: "The stream holding the message"},
+			{arg: "subject", kind: "string", description: "The subject of the message, defaults to any subject"},
+			{arg: "age_warn", kind: "string", description: "Warning threshold for the message age, e.g. 1m"},
+			{arg: "age_critical", kind: "string", description: "Critical threshold for the message age"},
+			{arg: "content", kind: "string", description: "Regular expression the message body must match"},
+			{arg: "body_timestamp", kind: "boolean", description: "Read the message age from a unix timestamp in the body"},
+		},
+		required: []string{"stream"},
+	},
+}
+
+// checkTools returns the health check tools.
+func (s *ServerTools) checkTools() []Tool {
+	needsAccountName := s.isAccountNameRequired()
+
+	tools := make([]Tool, 0, len(serverChecks))
+	for _, check := range serverChecks {
+		props := map[string]interface{}{}
+		for _, option := range check.options {
+			props[option.arg] = map[string]interface{}{
+				"type":        option.kind,
+				"description": option.description,
+			}
+		}
+		required := append([]string{}, check.required...)
+		if needsAccountName {
+			props["account_name"] = map[string]interface{}{
+				"type":        "string",
+				"description": "The NATS account to use (required for credentials-based authentication)",
+			}
+			required = append([]string{"account_name"}, required...)
+		}
+		tools = append(tools, Tool{
+			Tool: mcp.Tool{
+				Name:        check.name,
+				Description: check.description + ". Returns an OK, WARNING, CRITICAL or UNKNOWN status with performance data",
+				InputSchema: mcp.ToolInputSchema{Type: "object", Properties: props, Required: required},
+			},
+			Handler: s.checkHandler(check),
+		})
+	}
+	return tools
+}
+
+// checkArgs builds the `nats server check` arguments from the tool arguments.
+func (c serverCheck) checkArgs(arguments map[string]interface{}) ([]string, error) {
+	for _, arg := range c.required {
+		if value, ok := arguments[arg]; !ok || value == "" {
+			return nil, fmt.Errorf("missing %s", arg)
+		}
+	}
+	args := []string{"server", "check", c.suite}
+	for _, option := range c.options {
+		flag := "--" + strings.ReplaceAll(option.arg, "_", "-")
+		switch value := arguments[option.arg].(type) {
+		case string:
+			if value != "" {
+				args = append(args, flag+"="+value)
+			}
+		case float64:
+			args = append(args, flag+"="+strconv.FormatInt(int64(value), 10))
+		case bool:
+			if value {
+				args = append(args, flag)
+			} else if option.negatable {
+				args = append(args, "--no-"+strings.TrimPrefix(flag, "--"))
+			}
+		}
+	}
+	return append(args, "--format=json"), nil
+}
+
+// checkResult is the structured result of a check.
+type checkResult struct {
+	Status    string          `json:"status"`
+	Suite     string          `json:"check_suite"`
+	Name      string          `json:"check_name,omitempty"`
+	Criticals []string        `json:"critical,omitempty"`
+	Warnings  []string        `json:"warning,omitempty"`
+	OKs       []string        `json:"ok,omitempty"`
+	PerfData  []checkPerfData `json:"perf_data"`
+	// Output is the CLI output when it could not be parsed.
+	Output string `json:"output,omitempty"`
+}
+
+// checkPerfData is a Nagios performance data item.
+type checkPerfData struct {
+	Name     string  `json:"name"`
+	Value    float64 `json:"value"`
+	Warning  float64 `json:"warning"`
+	Critical float64 `json:"critical"`
+	Unit     string  `json:"unit,omitempty"`
+}
+
+// parseCheckOutput decodes the JSON rendering of a check. The status comes
+// from the exit code, which the CLI derives from the check results.
+func parseCheckOutput(suite, output string, exitCode int) checkResult {
+	status := checkStatuses[len(checkStatuses)-1]
+	if exitCode < len(checkStatuses) {
+		status = checkStatuses[exitCode]
+	}
+	result := checkResult{Status: status, Suite: suite}
+	start := strings.Index(output, "{")
+	if start < 0 || json.Unmarshal([]byte(output[start:]), &result) != nil {
+		return checkResult{Status: status, Suite: suite, Output: strings.TrimSpace(output)}
+	}
+	result.Status = status
+	return result
+}
+
+func (s *ServerTools) checkHandler(check serverCheck) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		accountName, err := mcpnats.DetermineAccountName(ctx, request.GetArguments())
+		if err != nil {
+			return nil, err
+		}
+		args, err := check.checkArgs(request.GetArguments())
+		if err != nil {
+			return nil, err
+		}
+
+		executor, err := s.nats.GetExecutor(ctx, accountName)
+		if err != nil {
+			return nil, err
+		}
+		output, exitCode, err := executor.ExecuteCheckContext(ctx, args...)
+		if err != nil {
+			return nil, err
+		}
+		return jsonResult(parseCheckOutput(check.suite, output, exitCode))
+	}
+}
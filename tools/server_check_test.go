@@ -0,0 +1,77 @@
+package tools
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestServerChecks_statusAndPerfData(t *testing.T) {
+	dir := t.TempDir()
+	argsFile := filepath.Join(dir, "args")
+	script := "#!/bin/sh\necho \"$@\" > \"$CHECK_ARGS\"\necho \"$CHECK_OUTPUT\"\nexit \"$CHECK_EXIT\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "nats"), []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write fake nats CLI: %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("CHECK_ARGS", argsFile)
+
+	consumer := testToolHandler(t, "nats://test:4222", (*NATSServerTools).ServerTools, "server_check_consumer")
+	run := func(output, exitCode string, args map[string]any) (checkResult, string, error) {
+		t.Helper()
+		t.Setenv("CHECK_OUTPUT", output)
+		t.Setenv("CHECK_EXIT", exitCode)
+		out, err := consumer(args)
+		if err != nil {
+			return checkResult{}, "", err
+		}
+		var result checkResult
+		if err := json.Unmarshal([]byte(out), &result); err != nil {
+			t.Fatalf("invalid result %s: %v", out, err)
+		}
+		ran, _ := os.ReadFile(argsFile)
+		return result, string(ran), nil
+	}
+
+	output := `{"check_suite":"consumer","check_name":"ORDERS_PROCESSOR","critical":["Ack Pending: 1200"],"ok":["Waiting Pulls: 0"],` +
+		`"perf_data":[{"name":"ack_pending","value":1200,"warning":0,"critical":1000}]}`
+	result, ran, err := run(output, "2", map[string]any{
+		"stream":                   "ORDERS",
+		"consumer":                 "PROCESSOR",
+		"outstanding_ack_critical": float64(1000),
+		"last_delivery_critical":   "5m",
+	})
+	if err != nil {
+		t.Fatalf("server_check_consumer: %v", err)
+	}
+	if !strings.Contains(ran, "server check consumer --stream=ORDERS --consumer=PROCESSOR --outstanding-ack-critical=1000 --last-delivery-critical=5m --format=json") {
+		t.Fatalf("unexpected command: %s", ran)
+	}
+	if result.Status != "CRITICAL" || result.Name != "ORDERS_PROCESSOR" || len(result.Criticals) != 1 ||
+		len(result.PerfData) != 1 || result.PerfData[0].Value != 1200 || result.PerfData[0].Critical != 1000 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	result, _, err = run("nats: error: connection refused", "3", map[string]any{"stream": "ORDERS", "consumer": "PROCESSOR"})
+	if err != nil || result.Status != "UNKNOWN" || result.Output != "nats: error: connection refused" {
+		t.Fatalf("expected an UNKNOWN status with the raw output: %+v, %v", result, err)
+	}
+	if _, _, err := run("", "4", map[string]any{"stream": "ORDERS", "consumer": "PROCESSOR"}); err == nil {
+		t.Fatalf("expected exit codes beyond UNKNOWN to fail")
+	}
+	if _, _, err := run("", "0", map[string]any{"stream": "ORDERS"}); err == nil || !strings.Contains(err.Error(), "missing consumer") {
+		t.Fatalf("expected the missing consumer to be reported, got %v", err)
+	}
+
+	jetstream := testToolHandler(t, "nats://test:4222", (*NATSServerTools).ServerTools, "server_check_jetstream")
+	t.Setenv("CHECK_OUTPUT", `{"check_suite":"jetstream"}`)
+	t.Setenv("CHECK_EXIT", "0")
+	if _, err := jetstream(map[string]any{"replicas": false, "mem_warn": float64(80)}); err != nil {
+		t.Fatalf("server_check_jetstream: %v", err)
+	}
+	if ran, _ := os.ReadFile(argsFile); !strings.Contains(string(ran), "--mem-warn=80 --no-replicas --format=json") {
+		t.Fatalf("unexpected command: %s", ran)
+	}
+}
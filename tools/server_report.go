@@ -0,0 +1,311 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/nats-io/nats.go"
+)
+
+// serverPingSubject is the system account subject every server answers a
+// monitoring request of the given kind on, e.g. CONNZ.
+const serverPingSubject = "$SYS.REQ.SERVER.PING.%s"
+
+// defaultReportTimeout bounds how long a report waits for servers to answer.
+const defaultReportTimeout = 2 * time.Second
+
+// reportOption maps a tool argument onto a field of the monitoring request.
+type reportOption struct {
+	arg         string
+	field       string
+	kind        string
+	description string
+	// values translates enum arguments into request values. Enum arguments
+	// without values are passed through as is.
+	enum   []string
+	values map[string]any
+}
+
+// serverReport describes a report tool backed by a monitoring endpoint.
+type serverReport struct {
+	name        string
+	kind        string
+	description string
+	options     []reportOption
+}
+
+// serverFilterOptions select the servers answering a report. Every report
+// accepts them.
+var serverFilterOptions = []reportOption{
+	{arg: "server_name", field: "server_name", kind: "string", description: "Only the server with this name"},
+	{arg: "server_cluster", field: "cluster", kind: "string", description: "Only servers in this NATS cluster"},
+	{arg: "server_host", field: "host", kind: "string", description: "Only servers on this host"},
+	{arg: "server_tags", field: "tags", kind: "array", description: "Only servers with all of these tags"},
+	{arg: "server_domain", field: "domain", kind: "string", description: "Only servers in this JetStream domain"},
+}
+
+var paginationOptions = []reportOption{
+	{arg: "limit", field: "limit", kind: "integer", description: "Return at most this many entries per server"},
+	{arg: "offset", field: "offset", kind: "integer", description: "Skip this many entries per server"},
+}
+
+var serverReports = []serverReport{
+	{
+		name:        "server_report_connections",
+		kind:        "CONNZ",
+		description: "Reports client connections on every server (connz)",
+		options: append([]reportOption{
+			{arg: "sort_by", field: "sort", kind: "string", description: "Sort connections by this field",
+				enum: []string{"cid", "start", "subs", "pending", "msgs_to", "msgs_from", "bytes_to", "bytes_from", "last", "idle", "uptime", "stop", "reason", "rtt"}},
+			{arg: "state", field: "state", kind: "string", description: "Which connections to include",
+				enum: []string{"open", "closed", "all"}, values: map[string]any{"open": 0, "closed": 1, "all": 2}},
+			{arg: "account", field: "acc", kind: "string", description: "Only connections of this account"},
+			{arg: "user", field: "user", kind: "string", description: "Only connections of this user"},
+			{arg: "filter_subject", field: "filter_subject", kind: "string", description: "Only connections subscribed to this subject (requires account)"},
+			{arg: "cid", field: "cid", kind: "integer", description: "Only the connection with this ID"},
+			{arg: "subscriptions", field: "subscriptions", kind: "boolean", description: "Include the subscriptions of each connection"},
+			{arg: "auth", field: "auth", kind: "boolean", description: "Include authentication details"},
+		}, paginationOptions...),
+	},
+	{
+		name:        "server_report_subscriptions",
+		kind:        "SUBSZ",
+		description: "Reports the subscription routing table of every server (subsz)",
+		options: append([]reportOption{
+			{arg: "account", field: "account", kind: "string", description: "Only subscriptions of this account"},
+			{arg: "subscriptions", field: "subscriptions", kind: "boolean", description: "Include individual subscriptions, not just totals"},
+			{arg: "test", field: "test", kind: "string", description: "Only subscriptions matching this publish subject"},
+		}, paginationOptions...),
+	},
+	{
+		name:        "server_report_jetstream",
+		kind:        "JSZ",
+		description: "Reports JetStream usage, accounts, streams and meta cluster state of every server (jsz)",
+		options: append([]reportOption{
+			{arg: "account", field: "account", kind: "string", description: "Only this account"},
+			{arg: "accounts", field: "accounts", kind: "boolean", description: "Include per account details"},
+			{arg: "streams", field: "streams", kind: "boolean", description: "Include stream details"},
+			{arg: "consumers", field: "consumer", kind: "boolean", description: "Include consumer details"},
+			{arg: "config", field: "config", kind: "boolean", description: "Include stream and consumer configuration"},
+			{arg: "leader_only", field: "leader_only", kind: "boolean", description: "Only answer from the meta leader"},
+			{arg: "raft", field: "raft", kind: "boolean", description: "Include Raft group details"},
+		}, paginationOptions...),
+	},
+	{
+		name:        "server_report_routes",
+		kind:        "ROUTEZ",
+		description: "Reports the cluster routes of every server (routez)",
+		options: []reportOption{
+			{arg: "subscriptions", field: "subscriptions", kind: "boolean", description: "Include the subscriptions of each route"},
+		},
+	},
+	{
+		name:        "server_report_gateways",
+		kind:        "GATEWAYZ",
+		description: "Reports the super-cluster gateways of every server (gatewayz)",
+		options: []reportOption{
+			{arg: "gateway", field: "name", kind: "string", description: "Only the gateway to this cluster"},
+			{arg: "accounts", field: "accounts", kind: "boolean", description: "Include account interest details"},
+			{arg: "account", field: "account_name", kind: "string", description: "Only interest of this account"},
+		},
+	},
+	{
+		name:        "server_report_leafnodes",
+		kind:        "LEAFZ",
+		description: "Reports the leafnode connections of every server (leafz)",
+		options: []reportOption{
+			{arg: "account", field: "account", kind: "string", description: "Only leafnodes bound to this account"},
+			{arg: "subscriptions", field: "subscriptions", kind: "boolean", description: "Include the subscriptions of each leafnode"},
+		},
+	},
+	{
+		name:        "server_report_accounts",
+		kind:        "ACCOUNTZ",
+		description: "Lists the accounts of every server, or the details of one account (accountz)",
+		options: []reportOption{
+			{arg: "account", field: "account", kind: "string", description: "Show the details of this account"},
+		},
+	},
+	{
+		name:        "server_report_health",
+		kind:        "HEALTHZ",
+		description: "Reports the health of every server (healthz)",
+		options: []reportOption{
+			{arg: "js_enabled_only", field: "js-enabled-only", kind: "boolean", description: "Only fail when JetStream is disabled"},
+			{arg: "js_server_only", field: "js-server-only", kind: "boolean", description: "Skip the stream and consumer checks"},
+			{arg: "account", field: "account", kind: "string", description: "Only check JetStream assets of this account"},
+			{arg: "stream", field: "stream", kind: "string", description: "Only check this stream (requires account)"},
+			{arg: "consumer", field: "consumer", kind: "string", description: "Only check this consumer (requires stream)"},
+			{arg: "details", field: "details", kind: "boolean", description: "Include the details of every failed check"},
+		},
+	},
+}
+
+// reportTools returns the monitoring report tools. They need system account
+// permissions.
+func (s *ServerTools) reportTools() []Tool {
+	needsAccountName := s.isAccountNameRequired()
+
+	tools := make([]Tool, 0, len(serverReports))
+	for _, report := range serverReports {
+		props := map[string]interface{}{
+			"expect": map[string]interface{}{
+				"type":        "integer",
+				"description": "Stop waiting once this many servers answered",
+			},
+			"timeout": map[string]interface{}{
+				"type":        "string",
+				"description": "How long to wait for servers to answer",
+				"default":     defaultReportTimeout.String(),
+			},
+		}
+		for _, option := range report.allOptions() {
+			prop := map[string]interface{}{
+				"type":        option.kind,
+				"description": option.description,
+			}
+			if option.kind == "array" {
+				prop["items"] = map[string]interface{}{"type": "string"}
+			}
+			if option.enum != nil {
+				prop["enum"] = option.enum
+			}
+			props[option.arg] = prop
+		}
+		required := []string{}
+		if needsAccountName {
+			props["account_name"] = map[string]interface{}{
+				"type":        "string",
+				"description": "The NATS system account to use (required for credentials-based authentication)",
+			}
+			required = append(required, "account_name")
+		}
+		tools = append(tools, Tool{
+			Tool: mcp.Tool{
+				Name:        report.name,
+				Description: report.description + ". Requires system account permissions",
+				InputSchema: mcp.ToolInputSchema{Type: "object", Properties: props, Required: required},
+			},
+			Handler: s.reportHandler(report),
+		})
+	}
+	return tools
+}
+
+// allOptions returns the report options followed by the server filters.
+func (r serverReport) allOptions() []reportOption {
+	return append(append([]reportOption{}, r.options...), serverFilterOptions...)
+}
+
+// reportRequest builds the monitoring request body from the tool arguments.
+func (r serverReport) reportRequest(arguments map[string]interface{}) (map[string]any, error) {
+	body := map[string]any{}
+	for _, option := range r.allOptions() {
+		value, ok := arguments[option.arg]
+		if !ok || value == nil {
+			continue
+		}
+		if option.enum != nil {
+			s, _ := value.(string)
+			if !containsString(option.enum, s) {
+				return nil, fmt.Errorf("invalid %s %q, must be one of %s", option.arg, s, strings.Join(option.enum, ", "))
+			}
+			if translated, ok := option.values[s]; ok {
+				value = translated
+			}
+		}
+		body[option.field] = value
+	}
+	return body, nil
+}
+
+func containsString(values []string, s string) bool {
+	for _, value := range values {
+		if value == s {
+			return true
+		}
+	}
+	return false
+}
+
+// serverReportEntry is the answer of one server to a monitoring request.
+type serverReportEntry struct {
+	Server map[string]any  `json:"server"`
+	Data   json.RawMessage `json:"data,omitempty"`
+	Error  *struct {
+		Code        int    `json:"code"`
+		Description string `json:"description"`
+	} `json:"error,omitempty"`
+}
+
+// serverReportResult is the JSON result of a report tool.
+type serverReportResult struct {
+	Report  string              `json:"report"`
+	Stopped string              `json:"stopped"`
+	Count   int                 `json:"count"`
+	Servers []serverReportEntry `json:"servers"`
+}
+
+func (s *ServerTools) reportHandler(report serverReport) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		body, err := report.reportRequest(request.GetArguments())
+		if err != nil {
+			return nil, err
+		}
+		opts := requestOptions{timeout: defaultReportTimeout, stall: defaultRequestStall}
+		if t, ok := request.GetArguments()["timeout"].(string); ok && t != "" {
+			opts.timeout, err = time.ParseDuration(t)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timeout: %w", err)
+			}
+			if opts.timeout <= 0 || opts.timeout > maxRequestTimeout {
+				return nil, fmt.Errorf("timeout must be positive and at most %s", maxRequestTimeout)
+			}
+		}
+		if expect, ok := request.GetArguments()["expect"].(float64); ok && expect > 0 {
+			opts.maxReplies = int(expect)
+		}
+
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s request: %w", report.kind, err)
+		}
+		nc, err := s.nats.connect(ctx, request.GetArguments())
+		if err != nil {
+			return nil, err
+		}
+		defer nc.Close()
+
+		replies, err := gatherReplies(ctx, nc, &nats.Msg{Subject: fmt.Sprintf(serverPingSubject, report.kind), Data: data}, opts)
+		if err != nil {
+			return nil, err
+		}
+		if replies.Stopped == stopNoResponders || replies.Count == 0 {
+			return nil, fmt.Errorf("no servers answered the %s request, check that the account has system account permissions", report.kind)
+		}
+
+		result := serverReportResult{Report: report.kind, Stopped: replies.Stopped, Count: replies.Count}
+		for _, reply := range replies.Replies {
+			var entry serverReportEntry
+			if err := json.Unmarshal([]byte(reply.Payload), &entry); err != nil {
+				return nil, fmt.Errorf("invalid %s response: %w", report.kind, err)
+			}
+			result.Servers = append(result.Servers, entry)
+		}
+		sort.SliceStable(result.Servers, func(i, j int) bool {
+			return serverName(result.Servers[i]) < serverName(result.Servers[j])
+		})
+		return jsonResult(result)
+	}
+}
+
+func serverName(entry serverReportEntry) string {
+	name, _ := entry.Server["name"].(string)
+	return name
+}
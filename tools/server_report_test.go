@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+)
+
+func TestServerReports_requestAndResult(t *testing.T) {
+	url, sysURL := startTestServer(t)
+	worker, err := nats.Connect(url, nats.Name("worker"))
+	if err != nil {
+		t.Fatalf("connect worker: %v", err)
+	}
+	t.Cleanup(worker.Close)
+	gone, err := nats.Connect(url, nats.Name("gone"))
+	if err != nil {
+		t.Fatalf("connect gone: %v", err)
+	}
+	gone.Close()
+
+	connections := testToolHandler(t, sysURL, (*NATSServerTools).ServerTools, "server_report_connections")
+	out, err := connections(map[string]any{
+		"sort_by":     "bytes_to",
+		"state":       "closed",
+		"account":     "APP",
+		"limit":       float64(5),
+		"auth":        true,
+		"server_name": "test",
+	})
+	if err != nil {
+		t.Fatalf("server_report_connections: %v", err)
+	}
+	var result serverReportResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid result %s: %v", out, err)
+	}
+	if result.Report != "CONNZ" || result.Count != 1 || serverName(result.Servers[0]) != "test" {
+		t.Fatalf("expected the report of the test server: %s", out)
+	}
+	var connz struct {
+		Limit       int `json:"limit"`
+		Connections []struct {
+			Name    string `json:"name"`
+			Account string `json:"account"`
+		} `json:"connections"`
+	}
+	if err := json.Unmarshal(result.Servers[0].Data, &connz); err != nil {
+		t.Fatalf("invalid data: %v", err)
+	}
+	if connz.Limit != 5 || len(connz.Connections) != 1 || connz.Connections[0].Name != "gone" || connz.Connections[0].Account != "APP" {
+		t.Fatalf("expected only the closed APP connection: %s", result.Servers[0].Data)
+	}
+
+	out, err = connections(map[string]any{"expect": float64(1)})
+	if err != nil || !strings.Contains(out, `"stopped": "max_replies"`) {
+		t.Fatalf("expected expect to stop after one server: %s, %v", out, err)
+	}
+	if _, err := connections(map[string]any{"server_cluster": "east", "timeout": "500ms"}); err == nil || !strings.Contains(err.Error(), "no servers answered") {
+		t.Fatalf("expected no server in cluster east, got %v", err)
+	}
+	if _, err := connections(map[string]any{"sort_by": "size"}); err == nil || !strings.Contains(err.Error(), "invalid sort_by") {
+		t.Fatalf("expected an invalid sort to be rejected, got %v", err)
+	}
+
+	out, err = testToolHandler(t, sysURL, (*NATSServerTools).ServerTools, "server_report_health")(map[string]any{})
+	if err != nil || !strings.Contains(out, `"status": "ok"`) {
+		t.Fatalf("server_report_health = %s, %v", out, err)
+	}
+	health := testToolHandler(t, url, (*NATSServerTools).ServerTools, "server_report_health")
+	if _, err := health(map[string]any{}); err == nil || !strings.Contains(err.Error(), "system account") {
+		t.Fatalf("expected a hint about system account permissions, got %v", err)
+	}
+}
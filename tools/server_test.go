@@ -0,0 +1,61 @@
+package tools
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+)
+
+// testServerConfig configures an APP account with JetStream and a subject
+// mapping, which clients without credentials join, and a SYS system account
+// for $SYS requests.
+const testServerConfig = `
+listen: 127.0.0.1:-1
+server_name: test
+jetstream: {store_dir: %q}
+accounts: {
+	APP: {
+		jetstream: enabled
+		users: [{user: app, password: app}]
+		mappings: {"orders.new": "orders.eu.new"}
+	}
+	SYS: {users: [{user: sys, password: sys}]}
+}
+system_account: SYS
+no_auth_user: app
+`
+
+// startTestServer runs an in-process nats-server with JetStream and returns
+// the URL of the APP account and the URL of the system account.
+func startTestServer(t *testing.T) (url, sysURL string) {
+	t.Helper()
+	dir := t.TempDir()
+	conf := filepath.Join(dir, "server.conf")
+	if err := os.WriteFile(conf, []byte(fmt.Sprintf(testServerConfig, filepath.Join(dir, "jetstream"))), 0o600); err != nil {
+		t.Fatalf("write server config: %v", err)
+	}
+	opts, err := server.ProcessConfigFile(conf)
+	if err != nil {
+		t.Fatalf("server config: %v", err)
+	}
+	opts.NoLog, opts.NoSigs = true, true
+	s, err := server.NewServer(opts)
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	go s.Start()
+	if !s.ReadyForConnections(10 * time.Second) {
+		s.Shutdown()
+		t.Fatalf("server not ready for connections")
+	}
+	t.Cleanup(func() {
+		s.Shutdown()
+		s.WaitForShutdown()
+	})
+	addr := s.Addr().String()
+	return "nats://" + addr, "nats://sys:sys@" + addr
+}
@@ -0,0 +1,435 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/micro"
+)
+
+// requestSchemaMetadata is the endpoint metadata key holding the JSON Schema
+// that service_call validates payloads against.
+const requestSchemaMetadata = "request_schema"
+
+// defaultDiscoveryTimeout bounds how long service discovery waits for
+// service instances to answer.
+const defaultDiscoveryTimeout = 2 * time.Second
+
+// ServiceTools represents the tools for services built on the NATS micro
+// framework
+type ServiceTools struct {
+	nats *NATSServerTools
+}
+
+// NewServiceTools creates a new ServiceTools instance
+func NewServiceTools(nats *NATSServerTools) *ServiceTools {
+	return &ServiceTools{
+		nats: nats,
+	}
+}
+
+// inputSchema returns a tool input schema with props, adding account_name
+// when the authentication mode needs it.
+func (s *ServiceTools) inputSchema(props map[string]interface{}, required ...string) mcp.ToolInputSchema {
+	if s.nats.accountNameRequired() {
+		props["account_name"] = map[string]interface{}{
+			"type":        "string",
+			"description": "The NATS account to use (required for credentials-based authentication)",
+		}
+		required = append([]string{"account_name"}, required...)
+	}
+	return mcp.ToolInputSchema{Type: "object", Properties: props, Required: required}
+}
+
+// discoveryProperties are the arguments selecting service instances.
+func discoveryProperties(nameRequired bool) map[string]interface{} {
+	name := "Only services with this name"
+	if nameRequired {
+		name = "Name of the service"
+	}
+	return map[string]interface{}{
+		"service": map[string]interface{}{
+			"type":        "string",
+			"description": name,
+		},
+		"id": map[string]interface{}{
+			"type":        "string",
+			"description": "Only the service instance with this ID",
+		},
+		"timeout": map[string]interface{}{
+			"type":        "string",
+			"description": "How long to wait for service instances to answer",
+			"default":     defaultDiscoveryTimeout.String(),
+		},
+	}
+}
+
+// GetTools implements the ToolCategory interface
+func (s *ServiceTools) GetTools() []Tool {
+	return []Tool{
+		{
+			Tool: mcp.Tool{
+				Name:        "service_list",
+				Description: "Discovers running NATS micro services and their instances",
+				InputSchema: s.inputSchema(discoveryProperties(false)),
+			},
+			Handler: s.serviceListHandler(),
+		},
+		{
+			Tool: mcp.Tool{
+				Name:        "service_info",
+				Description: "Shows the description, metadata and endpoints of every instance of a NATS micro service",
+				InputSchema: s.inputSchema(discoveryProperties(true), "service"),
+			},
+			Handler: s.discoveryHandler(micro.InfoVerb, true, func() any { return &micro.Info{} }),
+		},
+		{
+			Tool: mcp.Tool{
+				Name:        "service_stats",
+				Description: "Shows request, error and processing time statistics of every endpoint of a NATS micro service",
+				InputSchema: s.inputSchema(discoveryProperties(true), "service"),
+			},
+			Handler: s.discoveryHandler(micro.StatsVerb, true, func() any { return &micro.Stats{} }),
+		},
+		{
+			Tool: mcp.Tool{
+				Name:        "service_ping",
+				Description: "Pings NATS micro service instances and reports the latency of each",
+				InputSchema: s.inputSchema(discoveryProperties(false)),
+			},
+			Handler: s.servicePingHandler(),
+		},
+		{
+			Tool: mcp.Tool{
+				Name: "service_call",
+				Description: "Invokes an endpoint of a NATS micro service by service and endpoint name. " +
+					"When the endpoint advertises a JSON Schema in its " + requestSchemaMetadata + " metadata, the body is validated against it first",
+				InputSchema: s.inputSchema(map[string]interface{}{
+					"service": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the service",
+					},
+					"endpoint": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the endpoint",
+					},
+					"id": map[string]interface{}{
+						"type":        "string",
+						"description": "Look up the endpoint on the service instance with this ID",
+					},
+					"body": map[string]interface{}{
+						"type":        "string",
+						"description": "Request body",
+					},
+					"header": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "Adds headers to the request, as Name:value",
+					},
+					"timeout": map[string]interface{}{
+						"type":        "string",
+						"description": fmt.Sprintf("How long to wait for the reply (at most %s)", maxRequestTimeout),
+						"default":     defaultRequestTimeout.String(),
+					},
+				}, "service", "endpoint"),
+			},
+			Handler: s.serviceCallHandler(),
+		},
+	}
+}
+
+// discoveryTarget reads the service name, instance ID and timeout of a
+// discovery request.
+func discoveryTarget(arguments map[string]interface{}, nameRequired bool) (name, id string, timeout time.Duration, err error) {
+	name, _ = arguments["service"].(string)
+	id, _ = arguments["id"].(string)
+	if nameRequired && name == "" {
+		return "", "", 0, fmt.Errorf("missing service")
+	}
+	if id != "" && name == "" {
+		return "", "", 0, fmt.Errorf("id requires service")
+	}
+	timeout = defaultDiscoveryTimeout
+	if t, ok := arguments["timeout"].(string); ok && t != "" {
+		timeout, err = time.ParseDuration(t)
+		if err != nil {
+			return "", "", 0, fmt.Errorf("invalid timeout: %w", err)
+		}
+		if timeout <= 0 || timeout > maxRequestTimeout {
+			return "", "", 0, fmt.Errorf("timeout must be positive and at most %s", maxRequestTimeout)
+		}
+	}
+	return name, id, timeout, nil
+}
+
+// discover sends a micro control request and returns the replies of the
+// service instances that answered.
+func discover(ctx context.Context, nc *nats.Conn, verb micro.Verb, name, id string, timeout time.Duration) ([]receivedReply, error) {
+	subject, err := micro.ControlSubject(verb, name, id)
+	if err != nil {
+		return nil, err
+	}
+	opts := requestOptions{timeout: timeout, stall: defaultRequestStall}
+	if id != "" {
+		opts.maxReplies = 1
+	}
+	result, err := gatherReplies(ctx, nc, &nats.Msg{Subject: subject}, opts)
+	if err != nil {
+		return nil, err
+	}
+	return result.Replies, nil
+}
+
+// decodeReplies decodes discovery replies with newValue, failing if none
+// arrived.
+func decodeReplies(replies []receivedReply, newValue func() any, name string) ([]any, error) {
+	if len(replies) == 0 {
+		if name != "" {
+			return nil, fmt.Errorf("no instances of service %s responded", name)
+		}
+		return nil, fmt.Errorf("no services responded")
+	}
+	values := make([]any, 0, len(replies))
+	for _, reply := range replies {
+		value := newValue()
+		if err := json.Unmarshal([]byte(reply.Payload), value); err != nil {
+			return nil, fmt.Errorf("invalid service response: %w", err)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+func jsonResult(value any) (*mcp.CallToolResult, error) {
+	out, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode result: %w", err)
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+func (s *ServiceTools) serviceListHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, id, timeout, err := discoveryTarget(request.GetArguments(), false)
+		if err != nil {
+			return nil, err
+		}
+		nc, err := s.nats.connect(ctx, request.GetArguments())
+		if err != nil {
+			return nil, err
+		}
+		defer nc.Close()
+
+		replies, err := discover(ctx, nc, micro.PingVerb, name, id, timeout)
+		if err != nil {
+			return nil, err
+		}
+		values, err := decodeReplies(replies, func() any { return &micro.Ping{} }, name)
+		if err != nil {
+			return nil, err
+		}
+		services := make([]micro.ServiceIdentity, 0, len(values))
+		for _, value := range values {
+			services = append(services, value.(*micro.Ping).ServiceIdentity)
+		}
+		sort.Slice(services, func(i, j int) bool {
+			if services[i].Name != services[j].Name {
+				return services[i].Name < services[j].Name
+			}
+			return services[i].ID < services[j].ID
+		})
+		return jsonResult(services)
+	}
+}
+
+func (s *ServiceTools) discoveryHandler(verb micro.Verb, nameRequired bool, newValue func() any) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, id, timeout, err := discoveryTarget(request.GetArguments(), nameRequired)
+		if err != nil {
+			return nil, err
+		}
+		nc, err := s.nats.connect(ctx, request.GetArguments())
+		if err != nil {
+			return nil, err
+		}
+		defer nc.Close()
+
+		replies, err := discover(ctx, nc, verb, name, id, timeout)
+		if err != nil {
+			return nil, err
+		}
+		values, err := decodeReplies(replies, newValue, name)
+		if err != nil {
+			return nil, err
+		}
+		return jsonResult(values)
+	}
+}
+
+// servicePing is a ping reply with its round trip time.
+type servicePing struct {
+	micro.ServiceIdentity
+	LatencyMS float64 `json:"latency_ms"`
+}
+
+func (s *ServiceTools) servicePingHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, id, timeout, err := discoveryTarget(request.GetArguments(), false)
+		if err != nil {
+			return nil, err
+		}
+		nc, err := s.nats.connect(ctx, request.GetArguments())
+		if err != nil {
+			return nil, err
+		}
+		defer nc.Close()
+
+		replies, err := discover(ctx, nc, micro.PingVerb, name, id, timeout)
+		if err != nil {
+			return nil, err
+		}
+		values, err := decodeReplies(replies, func() any { return &micro.Ping{} }, name)
+		if err != nil {
+			return nil, err
+		}
+		pings := make([]servicePing, 0, len(values))
+		for i, value := range values {
+			pings = append(pings, servicePing{
+				ServiceIdentity: value.(*micro.Ping).ServiceIdentity,
+				LatencyMS:       replies[i].LatencyMS,
+			})
+		}
+		return jsonResult(pings)
+	}
+}
+
+// serviceCallResult is the JSON result of service_call.
+type serviceCallResult struct {
+	Service  string        `json:"service"`
+	Endpoint string        `json:"endpoint"`
+	Subject  string        `json:"subject"`
+	Reply    receivedReply `json:"reply"`
+	Error    *serviceError `json:"error,omitempty"`
+}
+
+// serviceError is the error a micro service reports in its reply headers.
+type serviceError struct {
+	Description string `json:"description"`
+	Code        string `json:"code,omitempty"`
+}
+
+func (s *ServiceTools) serviceCallHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, _ := request.GetArguments()["service"].(string)
+		if name == "" {
+			return nil, fmt.Errorf("missing service")
+		}
+		endpointName, _ := request.GetArguments()["endpoint"].(string)
+		if endpointName == "" {
+			return nil, fmt.Errorf("missing endpoint")
+		}
+		id, _ := request.GetArguments()["id"].(string)
+		body, _ := request.GetArguments()["body"].(string)
+		opts, err := requestOptionsFromArgs(request.GetArguments())
+		if err != nil {
+			return nil, err
+		}
+		headers, err := messageHeaders(ctx, request.GetArguments())
+		if err != nil {
+			return nil, err
+		}
+
+		nc, err := s.nats.connect(ctx, request.GetArguments())
+		if err != nil {
+			return nil, err
+		}
+		defer nc.Close()
+
+		endpoint, err := findEndpoint(ctx, nc, name, id, endpointName)
+		if err != nil {
+			return nil, err
+		}
+		if strings.ContainsAny(endpoint.Subject, "*>") {
+			return nil, fmt.Errorf("endpoint %s listens on the wildcard subject %s, use the request tool with a concrete subject", endpointName, endpoint.Subject)
+		}
+		if err := validateRequestSchema(endpoint, body); err != nil {
+			return nil, err
+		}
+
+		result, err := singleRequest(ctx, nc, &nats.Msg{Subject: endpoint.Subject, Data: []byte(body), Header: headers}, opts.timeout)
+		if err != nil {
+			return nil, err
+		}
+		call := serviceCallResult{
+			Service:  name,
+			Endpoint: endpointName,
+			Subject:  endpoint.Subject,
+			Reply:    result.Replies[0],
+		}
+		if description := call.Reply.Headers[micro.ErrorHeader]; len(description) > 0 {
+			call.Error = &serviceError{Description: description[0]}
+			if code := call.Reply.Headers[micro.ErrorCodeHeader]; len(code) > 0 {
+				call.Error.Code = code[0]
+			}
+		}
+		return jsonResult(call)
+	}
+}
+
+// findEndpoint looks up the named endpoint in the info of the first service
+// instance that answers.
+func findEndpoint(ctx context.Context, nc *nats.Conn, service, id, endpoint string) (*micro.EndpointInfo, error) {
+	replies, err := discover(ctx, nc, micro.InfoVerb, service, id, defaultDiscoveryTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if len(replies) > 1 {
+		replies = replies[:1]
+	}
+	values, err := decodeReplies(replies, func() any { return &micro.Info{} }, service)
+	if err != nil {
+		return nil, err
+	}
+	info := values[0].(*micro.Info)
+	names := make([]string, 0, len(info.Endpoints))
+	for i := range info.Endpoints {
+		if info.Endpoints[i].Name == endpoint {
+			return &info.Endpoints[i], nil
+		}
+		names = append(names, info.Endpoints[i].Name)
+	}
+	return nil, fmt.Errorf("service %s has no endpoint %s (endpoints: %s)", service, endpoint, strings.Join(names, ", "))
+}
+
+// validateRequestSchema checks body against the JSON Schema the endpoint
+// advertises in its metadata, if any.
+func validateRequestSchema(endpoint *micro.EndpointInfo, body string) error {
+	raw := endpoint.Metadata[requestSchemaMetadata]
+	if raw == "" {
+		return nil
+	}
+	var schema jsonschema.Schema
+	if err := json.Unmarshal([]byte(raw), &schema); err != nil {
+		return fmt.Errorf("endpoint %s advertises an invalid request schema: %w", endpoint.Name, err)
+	}
+	resolved, err := schema.Resolve(nil)
+	if err != nil {
+		return fmt.Errorf("endpoint %s advertises an invalid request schema: %w", endpoint.Name, err)
+	}
+	var payload any
+	if err := json.Unmarshal([]byte(body), &payload); err != nil {
+		return fmt.Errorf("endpoint %s expects a JSON body: %w", endpoint.Name, err)
+	}
+	if err := resolved.Validate(payload); err != nil {
+		return fmt.Errorf("body does not match the request schema of endpoint %s: %w", endpoint.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/micro"
+)
+
+func TestServiceTools_discoverAndCall(t *testing.T) {
+	url, _ := startTestServer(t)
+	nc, err := nats.Connect(url)
+	if err != nil {
+		t.Fatalf("connect service: %v", err)
+	}
+	t.Cleanup(nc.Close)
+	svc, err := micro.AddService(nc, micro.Config{Name: "orders", Version: "1.2.0", Description: "Order intake"})
+	if err != nil {
+		t.Fatalf("add service: %v", err)
+	}
+	t.Cleanup(func() { _ = svc.Stop() })
+	schema := `{"type":"object","properties":{"id":{"type":"integer"}},"required":["id"]}`
+	if err := svc.AddEndpoint("create", micro.HandlerFunc(func(req micro.Request) {
+		if strings.Contains(string(req.Data()), `"id":0`) {
+			_ = req.Error("400", "id must not be zero", nil)
+			return
+		}
+		_ = req.Respond([]byte("created " + string(req.Data())))
+	}), micro.WithEndpointSubject("orders.create"), micro.WithEndpointMetadata(map[string]string{requestSchemaMetadata: schema})); err != nil {
+		t.Fatalf("add endpoint: %v", err)
+	}
+	if err := nc.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	tool := func(name string) func(map[string]any) (string, error) {
+		return testToolHandler(t, url, (*NATSServerTools).ServiceTools, name)
+	}
+
+	out, err := tool("service_list")(map[string]any{"timeout": "500ms"})
+	if err != nil || !strings.Contains(out, `"name": "orders"`) || !strings.Contains(out, `"version": "1.2.0"`) {
+		t.Fatalf("service_list = %s, %v", out, err)
+	}
+	out, err = tool("service_info")(map[string]any{"service": "orders", "timeout": "500ms"})
+	if err != nil || !strings.Contains(out, `"subject": "orders.create"`) || !strings.Contains(out, "Order intake") {
+		t.Fatalf("service_info = %s, %v", out, err)
+	}
+	if _, err := tool("service_ping")(map[string]any{"service": "billing", "timeout": "200ms"}); err == nil || !strings.Contains(err.Error(), "no instances of service billing") {
+		t.Fatalf("expected no billing instances, got %v", err)
+	}
+
+	call := tool("service_call")
+	out, err = call(map[string]any{"service": "orders", "endpoint": "create", "body": `{"id":7}`})
+	if err != nil {
+		t.Fatalf("service_call: %v", err)
+	}
+	var result serviceCallResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid result %s: %v", out, err)
+	}
+	if result.Subject != "orders.create" || result.Reply.Payload != `created {"id":7}` || result.Error != nil {
+		t.Fatalf("unexpected call result: %s", out)
+	}
+	out, err = call(map[string]any{"service": "orders", "endpoint": "create", "body": `{"id":0}`})
+	if err != nil || !strings.Contains(out, `"code": "400"`) {
+		t.Fatalf("expected the service error in the result: %s, %v", out, err)
+	}
+
+	out, err = tool("service_stats")(map[string]any{"service": "orders", "timeout": "500ms"})
+	if err != nil || !strings.Contains(out, `"num_requests": 2`) || !strings.Contains(out, `"num_errors": 1`) {
+		t.Fatalf("service_stats = %s, %v", out, err)
+	}
+
+	for body, want := range map[string]string{
+		`{"name":"x"}`: "does not match the request schema",
+		`not json`:     "expects a JSON body",
+	} {
+		if _, err := call(map[string]any{"service": "orders", "endpoint": "create", "body": body}); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("body %s: error = %v, want %q", body, err, want)
+		}
+	}
+	if _, err := call(map[string]any{"service": "orders", "endpoint": "cancel"}); err == nil || !strings.Contains(err.Error(), "endpoints: create") {
+		t.Fatalf("expected the known endpoints in the error, got %v", err)
+	}
+}
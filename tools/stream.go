@@ -23,7 +23,7 @@ func NewStreamTools(nats *NATSServerTools) *StreamTools {
 
 // GetTools implements the ToolCategory interface
 func (s *StreamTools) GetTools() []Tool {
-	return []Tool{
+	return append([]Tool{
 		{
 			Tool: mcp.Tool{
 				Name:        "stream_info",
@@ -228,7 +228,7 @@ func (s *StreamTools) GetTools() []Tool {
 			},
 			Handler: s.streamGetHandler(),
 		},
-	}
+	}, s.lifecycleTools()...)
 }
 
 // Helper function to get flags from arguments
@@ -267,7 +267,7 @@ func (s *StreamTools) streamInfoHandler() server.ToolHandlerFunc {
 			args = append(args, flags...)
 		}
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -292,7 +292,7 @@ func (s *StreamTools) streamListHandler() server.ToolHandlerFunc {
 			args = append(args, flags...)
 		}
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -318,7 +318,7 @@ func (s *StreamTools) streamReportHandler() server.ToolHandlerFunc {
 			args = append(args, flags...)
 		}
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -344,7 +344,7 @@ func (s *StreamTools) streamFindHandler() server.ToolHandlerFunc {
 			args = append(args, flags...)
 		}
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -378,7 +378,7 @@ func (s *StreamTools) streamStateHandler() server.ToolHandlerFunc {
 			args = append(args, flags...)
 		}
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -412,7 +412,7 @@ func (s *StreamTools) streamSubjectsHandler() server.ToolHandlerFunc {
 			args = append(args, flags...)
 		}
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -452,7 +452,7 @@ func (s *StreamTools) streamViewHandler() server.ToolHandlerFunc {
 			args = append(args, flags...)
 		}
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
@@ -492,7 +492,7 @@ func (s *StreamTools) streamGetHandler() server.ToolHandlerFunc {
 			args = append(args, flags...)
 		}
 
-		output, err := executor.ExecuteCommand(args...)
+		output, err := executor.ExecuteCommandContext(ctx, args...)
 		if err != nil {
 			return nil, err
 		}
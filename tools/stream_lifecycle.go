@@ -0,0 +1,710 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// streamConfigProperties are the stream settings accepted when adding,
+// editing and copying a stream.
+func streamConfigProperties() map[string]interface{} {
+	return map[string]interface{}{
+		"subjects": map[string]interface{}{
+			"type":        "array",
+			"items":       map[string]interface{}{"type": "string"},
+			"description": "Subjects that are consumed by the stream",
+		},
+		"description": map[string]interface{}{
+			"type":        "string",
+			"description": "A description for the stream",
+		},
+		"storage": map[string]interface{}{
+			"type":        "string",
+			"description": "Storage backend to use",
+			"enum":        []string{"file", "memory"},
+		},
+		"compression": map[string]interface{}{
+			"type":        "string",
+			"description": "Compression algorithm (file storage only)",
+			"enum":        []string{"none", "s2"},
+		},
+		"replicas": map[string]interface{}{
+			"type":        "integer",
+			"description": "How many replicas of the data to store",
+		},
+		"retention": map[string]interface{}{
+			"type":        "string",
+			"description": "Retention policy",
+			"enum":        []string{"limits", "interest", "work"},
+		},
+		"discard": map[string]interface{}{
+			"type":        "string",
+			"description": "Whether old messages are discarded or new ones rejected when the stream is full",
+			"enum":        []string{"old", "new"},
+		},
+		"discard_per_subject": map[string]interface{}{
+			"type":        "boolean",
+			"description": "Apply the new discard policy per subject (requires max_msgs_per_subject)",
+		},
+		"max_msgs": map[string]interface{}{
+			"type":        "integer",
+			"description": "Maximum number of messages to keep",
+		},
+		"max_msgs_per_subject": map[string]interface{}{
+			"type":        "integer",
+			"description": "Maximum number of messages to keep per subject",
+		},
+		"max_bytes": map[string]interface{}{
+			"type":        "string",
+			"description": "Maximum size of the stream, e.g. 1GB",
+		},
+		"max_age": map[string]interface{}{
+			"type":        "string",
+			"description": "Maximum age of messages, e.g. 24h or 7d",
+		},
+		"max_msg_size": map[string]interface{}{
+			"type":        "string",
+			"description": "Maximum size of a single message, e.g. 1MB",
+		},
+		"max_consumers": map[string]interface{}{
+			"type":        "integer",
+			"description": "Maximum number of consumers",
+		},
+		"dupe_window": map[string]interface{}{
+			"type":        "string",
+			"description": "Duration of the duplicate message tracking window, e.g. 2m",
+		},
+		"allow_rollup": map[string]interface{}{
+			"type":        "boolean",
+			"description": "Allow rollup headers to replace the contents of a subject or the stream",
+		},
+		"deny_delete": map[string]interface{}{
+			"type":        "boolean",
+			"description": "Deny deleting messages through the API",
+		},
+		"deny_purge": map[string]interface{}{
+			"type":        "boolean",
+			"description": "Deny purging the stream through the API",
+		},
+		"allow_direct": map[string]interface{}{
+			"type":        "boolean",
+			"description": "Allow direct access to messages from all replicas",
+		},
+		"tags": map[string]interface{}{
+			"type":        "array",
+			"items":       map[string]interface{}{"type": "string"},
+			"description": "Place the stream on servers that have specific tags",
+		},
+		"cluster": map[string]interface{}{
+			"type":        "string",
+			"description": "Place the stream on a specific cluster",
+		},
+		"mirror": map[string]interface{}{
+			"type":        "string",
+			"description": "Create a mirror of a different stream",
+		},
+		"sources": map[string]interface{}{
+			"type":        "array",
+			"items":       map[string]interface{}{"type": "string"},
+			"description": "Source messages from other streams",
+		},
+		"transform_source": map[string]interface{}{
+			"type":        "string",
+			"description": "Subject transform source, applied to messages as they are stored",
+		},
+		"transform_destination": map[string]interface{}{
+			"type":        "string",
+			"description": "Subject transform destination for transform_source",
+		},
+		"republish_source": map[string]interface{}{
+			"type":        "string",
+			"description": "Republish messages matching this subject to republish_destination",
+		},
+		"republish_destination": map[string]interface{}{
+			"type":        "string",
+			"description": "Republish destination for messages in republish_source",
+		},
+	}
+}
+
+// streamToolProperties returns the account_name, stream, dry_run and flags
+// properties shared by the lifecycle tools, merged with extra.
+func streamToolProperties(streamDescription string, extra map[string]interface{}) map[string]interface{} {
+	properties := map[string]interface{}{
+		"account_name": map[string]interface{}{
+			"type":        "string",
+			"description": "The NATS account to use",
+		},
+		"stream": map[string]interface{}{
+			"type":        "string",
+			"description": streamDescription,
+		},
+		"dry_run": map[string]interface{}{
+			"type":        "boolean",
+			"description": "Show what would be done without changing anything",
+		},
+		"flags": map[string]interface{}{
+			"type":        "array",
+			"items":       map[string]interface{}{"type": "string"},
+			"description": "Optional flags to pass to the command",
+		},
+	}
+	for name, property := range extra {
+		properties[name] = property
+	}
+	return properties
+}
+
+// lifecycleTools are the tools that create, change and remove streams.
+func (s *StreamTools) lifecycleTools() []Tool {
+	force := map[string]interface{}{
+		"type":        "boolean",
+		"description": "Confirm the change; required unless dry_run is set, as the confirmation prompt cannot be answered",
+		"default":     false,
+	}
+
+	addExtra := streamConfigProperties()
+	editExtra := streamConfigProperties()
+	editExtra["force"] = force
+	copyExtra := streamConfigProperties()
+	copyExtra["destination"] = map[string]interface{}{
+		"type":        "string",
+		"description": "Name of the new stream",
+	}
+
+	return []Tool{
+		{
+			Tool: mcp.Tool{
+				Name:        "stream_add",
+				Description: "Creates a new stream",
+				InputSchema: mcp.ToolInputSchema{
+					Type:       "object",
+					Properties: streamToolProperties("The name of the stream to create", addExtra),
+					Required:   []string{"account_name", "stream"},
+				},
+			},
+			Handler: s.streamAddHandler(),
+		},
+		{
+			Tool: mcp.Tool{
+				Name:        "stream_edit",
+				Description: "Changes the configuration of an existing stream",
+				InputSchema: mcp.ToolInputSchema{
+					Type:       "object",
+					Properties: streamToolProperties("Stream name", editExtra),
+					Required:   []string{"account_name", "stream"},
+				},
+			},
+			Handler: s.streamEditHandler(),
+		},
+		{
+			Tool: mcp.Tool{
+				Name:        "stream_rm",
+				Description: "Removes a stream and all its messages and consumers",
+				InputSchema: mcp.ToolInputSchema{
+					Type:       "object",
+					Properties: streamToolProperties("Stream name", map[string]interface{}{"force": force}),
+					Required:   []string{"account_name", "stream"},
+				},
+			},
+			Handler: s.streamRmHandler(),
+		},
+		{
+			Tool: mcp.Tool{
+				Name:        "stream_purge",
+				Description: "Purges messages from a stream, optionally only on a subject, up to a sequence or keeping the newest ones",
+				InputSchema: mcp.ToolInputSchema{
+					Type: "object",
+					Properties: streamToolProperties("Stream name", map[string]interface{}{
+						"subject": map[string]interface{}{
+							"type":        "string",
+							"description": "Only purge messages on this subject",
+						},
+						"sequence": map[string]interface{}{
+							"type":        "integer",
+							"description": "Purge messages up to, but not including, this sequence",
+						},
+						"keep": map[string]interface{}{
+							"type":        "integer",
+							"description": "Keep this many of the newest messages",
+						},
+						"force": force,
+					}),
+					Required: []string{"account_name", "stream"},
+				},
+			},
+			Handler: s.streamPurgeHandler(),
+		},
+		{
+			Tool: mcp.Tool{
+				Name:        "stream_rmm",
+				Description: "Securely removes a single message from a stream",
+				InputSchema: mcp.ToolInputSchema{
+					Type: "object",
+					Properties: streamToolProperties("Stream name", map[string]interface{}{
+						"id": map[string]interface{}{
+							"type":        "string",
+							"description": "Sequence of the message to remove",
+						},
+						"force": force,
+					}),
+					Required: []string{"account_name", "stream", "id"},
+				},
+			},
+			Handler: s.streamRmmHandler(),
+		},
+		{
+			Tool: mcp.Tool{
+				Name:        "stream_seal",
+				Description: "Seals a stream so that no messages can be added, removed or purged. This cannot be undone",
+				InputSchema: mcp.ToolInputSchema{
+					Type:       "object",
+					Properties: streamToolProperties("Stream name", map[string]interface{}{"force": force}),
+					Required:   []string{"account_name", "stream"},
+				},
+			},
+			Handler: s.streamSealHandler(),
+		},
+		{
+			Tool: mcp.Tool{
+				Name:        "stream_copy",
+				Description: "Creates a new stream with the configuration of an existing one, overriding the given settings. Messages are not copied",
+				InputSchema: mcp.ToolInputSchema{
+					Type:       "object",
+					Properties: streamToolProperties("Stream to copy the configuration from", copyExtra),
+					Required:   []string{"account_name", "stream", "destination"},
+				},
+			},
+			Handler: s.streamCopyHandler(),
+		},
+	}
+}
+
+// streamConfigFlags converts the stream settings of a request into nats
+// stream add/edit/copy flags.
+func streamConfigFlags(arguments map[string]interface{}) []string {
+	var args []string
+	if subjects, ok := arguments["subjects"].([]interface{}); ok {
+		var strSubjects []string
+		for _, subject := range subjects {
+			if strSubject, ok := subject.(string); ok {
+				strSubjects = append(strSubjects, strSubject)
+			}
+		}
+		args = append(args, fmt.Sprintf("--subjects=%s", strings.Join(strSubjects, ",")))
+	}
+	for _, name := range []string{"description", "storage", "compression", "retention", "discard", "max_bytes", "max_age", "max_msg_size", "dupe_window", "mirror", "transform_source", "transform_destination", "republish_source", "republish_destination"} {
+		if value, ok := arguments[name].(string); ok {
+			args = append(args, fmt.Sprintf("--%s=%s", strings.ReplaceAll(name, "_", "-"), value))
+		}
+	}
+	if cluster, ok := arguments["cluster"].(string); ok {
+		args = append(args, fmt.Sprintf("--cluster=%s", cluster))
+	}
+	for _, name := range []string{"replicas", "max_msgs", "max_msgs_per_subject", "max_consumers"} {
+		if value, ok := arguments[name].(float64); ok {
+			args = append(args, fmt.Sprintf("--%s=%d", strings.ReplaceAll(name, "_", "-"), int(value)))
+		}
+	}
+	for _, name := range []string{"discard_per_subject", "allow_rollup", "deny_delete", "deny_purge", "allow_direct"} {
+		if value, ok := arguments[name].(bool); ok {
+			flag := strings.ReplaceAll(name, "_", "-")
+			if value {
+				args = append(args, "--"+flag)
+			} else {
+				args = append(args, "--no-"+flag)
+			}
+		}
+	}
+	if tags, ok := arguments["tags"].([]interface{}); ok {
+		for _, tag := range tags {
+			if strTag, ok := tag.(string); ok {
+				args = append(args, fmt.Sprintf("--tag=%s", strTag))
+			}
+		}
+	}
+	if sources, ok := arguments["sources"].([]interface{}); ok {
+		for _, source := range sources {
+			if strSource, ok := source.(string); ok {
+				args = append(args, fmt.Sprintf("--source=%s", strSource))
+			}
+		}
+	}
+	return args
+}
+
+// streamDurationPattern matches the durations the CLI accepts for max_age
+// and dupe_window, which add days, weeks, months and years to Go durations.
+var streamDurationPattern = regexp.MustCompile(`^(\d+(\.\d+)?(ns|us|µs|ms|s|m|h|d|w|M|y))+$`)
+
+// streamSizePattern matches the sizes the CLI accepts for max_bytes and
+// max_msg_size, e.g. 1024, 512MiB or 1GB, and -1 for unlimited.
+var streamSizePattern = regexp.MustCompile(`^(-1|\d+(\.\d+)?\s*([KMGTP]i?B?|B)?)$`)
+
+// validateStreamName rejects names the server does not allow for streams.
+func validateStreamName(arg, name string) error {
+	if name == "" || strings.ContainsAny(name, " \t\r\n.*>/\\") {
+		return fmt.Errorf("invalid %s %q: stream names cannot be empty or contain whitespace, '.', '*', '>' or path separators", arg, name)
+	}
+	return nil
+}
+
+// validateStreamConfig checks the stream settings of a request the way the
+// CLI and the server would, so that dry runs report the same errors as the
+// change itself.
+func validateStreamConfig(arguments map[string]interface{}) error {
+	props := streamConfigProperties()
+	for _, name := range []string{"storage", "compression", "retention", "discard"} {
+		value, ok := arguments[name].(string)
+		if !ok {
+			continue
+		}
+		allowed := props[name].(map[string]interface{})["enum"].([]string)
+		if !containsString(allowed, value) {
+			return fmt.Errorf("invalid %s %q, must be one of %s", name, value, strings.Join(allowed, ", "))
+		}
+	}
+	if arguments["compression"] == "s2" && arguments["storage"] == "memory" {
+		return fmt.Errorf("compression requires file storage")
+	}
+	if replicas, ok := arguments["replicas"].(float64); ok && (replicas < 1 || replicas > 5) {
+		return fmt.Errorf("replicas must be between 1 and 5")
+	}
+	for _, name := range []string{"max_msgs", "max_msgs_per_subject", "max_consumers"} {
+		if value, ok := arguments[name].(float64); ok && value < -1 {
+			return fmt.Errorf("%s must be -1 for unlimited or a positive number", name)
+		}
+	}
+	for _, name := range []string{"max_age", "dupe_window"} {
+		if value, ok := arguments[name].(string); ok && value != "0" && !streamDurationPattern.MatchString(value) {
+			return fmt.Errorf("invalid %s %q, expected a duration like 2m, 24h or 7d", name, value)
+		}
+	}
+	for _, name := range []string{"max_bytes", "max_msg_size"} {
+		if value, ok := arguments[name].(string); ok && !streamSizePattern.MatchString(value) {
+			return fmt.Errorf("invalid %s %q, expected a size like 1024, 512MiB or 1GB", name, value)
+		}
+	}
+	if perSubject, ok := arguments["discard_per_subject"].(bool); ok && perSubject {
+		if arguments["discard"] != "new" {
+			return fmt.Errorf("discard_per_subject requires the new discard policy")
+		}
+		if _, ok := arguments["max_msgs_per_subject"].(float64); !ok {
+			return fmt.Errorf("discard_per_subject requires max_msgs_per_subject")
+		}
+	}
+	if mirror, ok := arguments["mirror"].(string); ok {
+		if err := validateStreamName("mirror", mirror); err != nil {
+			return err
+		}
+		for _, name := range []string{"subjects", "sources", "transform_source"} {
+			if _, ok := arguments[name]; ok {
+				return fmt.Errorf("mirror cannot be combined with %s", name)
+			}
+		}
+	}
+	if sources, ok := arguments["sources"].([]interface{}); ok {
+		for _, source := range sources {
+			name, _ := source.(string)
+			if err := validateStreamName("source", name); err != nil {
+				return err
+			}
+		}
+	}
+	for _, pair := range [][2]string{{"transform_source", "transform_destination"}, {"republish_source", "republish_destination"}} {
+		_, hasSource := arguments[pair[0]].(string)
+		_, hasDestination := arguments[pair[1]].(string)
+		if hasSource != hasDestination {
+			return fmt.Errorf("%s and %s must be set together", pair[0], pair[1])
+		}
+	}
+	return nil
+}
+
+// runStreamCommand runs a nats stream command followed by any flags of the
+// request.
+func (s *StreamTools) runStreamCommand(ctx context.Context, request mcp.CallToolRequest, args ...string) (*mcp.CallToolResult, error) {
+	accountName, ok := request.GetArguments()["account_name"].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing account_name")
+	}
+
+	executor, err := s.nats.GetExecutor(ctx, accountName)
+	if err != nil {
+		return nil, err
+	}
+
+	if flags := getFlags(request.GetArguments()); flags != nil {
+		args = append(args, flags...)
+	}
+
+	output, err := executor.ExecuteCommandContext(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultText(output), nil
+}
+
+// runStreamChange runs a mutating nats stream command. With dry_run set the
+// command is not run; instead the result describes it, followed by the
+// output of the preview command (if any) showing what it would affect.
+func (s *StreamTools) runStreamChange(ctx context.Context, request mcp.CallToolRequest, args []string, preview []string) (*mcp.CallToolResult, error) {
+	if dryRun, ok := request.GetArguments()["dry_run"].(bool); !ok || !dryRun {
+		return s.runStreamCommand(ctx, request, args...)
+	}
+
+	// A dry run fails where the change would before running the command.
+	accountName, ok := request.GetArguments()["account_name"].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing account_name")
+	}
+	if _, err := s.nats.GetExecutor(ctx, accountName); err != nil {
+		return nil, err
+	}
+	if flags := getFlags(request.GetArguments()); flags != nil {
+		args = append(args, flags...)
+	}
+	text := fmt.Sprintf("Dry run, nothing was changed. Would run:\n\n  nats %s\n", strings.Join(args, " "))
+	if preview != nil {
+		// The preview runs as the same account on the same cluster, but
+		// without the flags of the change.
+		previewArgs := map[string]interface{}{"account_name": request.GetArguments()["account_name"]}
+		if cluster, ok := request.GetArguments()[clusterArgument]; ok {
+			previewArgs[clusterArgument] = cluster
+		}
+		result, err := s.runStreamCommand(ctx, mcp.CallToolRequest{Params: mcp.CallToolParams{
+			Name:      request.Params.Name,
+			Arguments: previewArgs,
+		}}, preview...)
+		if err != nil {
+			return nil, err
+		}
+		text += fmt.Sprintf("\nCurrent state (nats %s):\n\n%s", strings.Join(preview, " "), result.Content[0].(mcp.TextContent).Text)
+	}
+	return mcp.NewToolResultText(text), nil
+}
+
+// streamArg returns the stream argument of a request.
+func streamArg(request mcp.CallToolRequest) (string, error) {
+	stream, ok := request.GetArguments()["stream"].(string)
+	if !ok {
+		return "", fmt.Errorf("missing stream")
+	}
+	return stream, nil
+}
+
+// requireForce rejects a change that the CLI would confirm interactively
+// unless the request sets force, as there is no terminal to answer the
+// prompt. Dry runs need no confirmation.
+func requireForce(request mcp.CallToolRequest, command string) error {
+	if dryRun, ok := request.GetArguments()["dry_run"].(bool); ok && dryRun {
+		return nil
+	}
+	if force, ok := request.GetArguments()["force"].(bool); !ok || !force {
+		return fmt.Errorf("nats %s asks for confirmation: set force to true to run it, or dry_run to preview it", command)
+	}
+	return nil
+}
+
+// forceFlag returns --force if the request asks to act without confirmation.
+func forceFlag(request mcp.CallToolRequest) []string {
+	if force, ok := request.GetArguments()["force"].(bool); ok && force {
+		return []string{"--force"}
+	}
+	return nil
+}
+
+// nats stream add
+// Args:
+//
+//	[<stream>]  Stream name
+func (s *StreamTools) streamAddHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		stream, err := streamArg(request)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := validateStreamName("stream", stream); err != nil {
+			return nil, err
+		}
+		if err := validateStreamConfig(request.GetArguments()); err != nil {
+			return nil, err
+		}
+
+		args := []string{"stream", "add", stream}
+		args = append(args, streamConfigFlags(request.GetArguments())...)
+		// Settings that were not given take their defaults instead of
+		// prompting, as there is no terminal to answer on.
+		args = append(args, "--defaults")
+
+		return s.runStreamChange(ctx, request, args, nil)
+	}
+}
+
+// nats stream edit
+// Args:
+//
+//	[<stream>]  Stream name
+func (s *StreamTools) streamEditHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		stream, err := streamArg(request)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := validateStreamConfig(request.GetArguments()); err != nil {
+			return nil, err
+		}
+
+		if err := requireForce(request, "stream edit"); err != nil {
+			return nil, err
+		}
+
+		args := []string{"stream", "edit", stream}
+		args = append(args, streamConfigFlags(request.GetArguments())...)
+		args = append(args, forceFlag(request)...)
+
+		// The CLI shows the configuration differences itself.
+		if dryRun, ok := request.GetArguments()["dry_run"].(bool); ok && dryRun {
+			args = append(args, "--dry-run")
+		}
+
+		return s.runStreamCommand(ctx, request, args...)
+	}
+}
+
+// nats stream rm
+// Args:
+//
+//	[<stream>]  Stream name
+func (s *StreamTools) streamRmHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		stream, err := streamArg(request)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := requireForce(request, "stream rm"); err != nil {
+			return nil, err
+		}
+
+		args := append([]string{"stream", "rm", stream}, forceFlag(request)...)
+		return s.runStreamChange(ctx, request, args, []string{"stream", "info", stream})
+	}
+}
+
+// nats stream purge
+// Args:
+//
+//	[<stream>]  Stream name
+func (s *StreamTools) streamPurgeHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		stream, err := streamArg(request)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := requireForce(request, "stream purge"); err != nil {
+			return nil, err
+		}
+
+		args := []string{"stream", "purge", stream}
+		if subject, ok := request.GetArguments()["subject"].(string); ok {
+			args = append(args, fmt.Sprintf("--subject=%s", subject))
+		}
+		if sequence, ok := request.GetArguments()["sequence"].(float64); ok {
+			args = append(args, fmt.Sprintf("--seq=%d", int64(sequence)))
+		}
+		if keep, ok := request.GetArguments()["keep"].(float64); ok {
+			args = append(args, fmt.Sprintf("--keep=%d", int64(keep)))
+		}
+		args = append(args, forceFlag(request)...)
+
+		preview := []string{"stream", "state", stream}
+		if subject, ok := request.GetArguments()["subject"].(string); ok {
+			preview = []string{"stream", "subjects", stream, subject}
+		}
+		return s.runStreamChange(ctx, request, args, preview)
+	}
+}
+
+// nats stream rmm
+// Args:
+//
+//	[<stream>]  Stream name
+//	[<id>]      Message Sequence to remove
+func (s *StreamTools) streamRmmHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		stream, err := streamArg(request)
+		if err != nil {
+			return nil, err
+		}
+
+		id, ok := request.GetArguments()["id"].(string)
+		if !ok {
+			return nil, fmt.Errorf("missing id")
+		}
+
+		if err := requireForce(request, "stream rmm"); err != nil {
+			return nil, err
+		}
+
+		args := append([]string{"stream", "rmm", stream, id}, forceFlag(request)...)
+		return s.runStreamChange(ctx, request, args, []string{"stream", "get", stream, id})
+	}
+}
+
+// nats stream seal
+// Args:
+//
+//	[<stream>]  Stream name
+func (s *StreamTools) streamSealHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		stream, err := streamArg(request)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := requireForce(request, "stream seal"); err != nil {
+			return nil, err
+		}
+
+		args := append([]string{"stream", "seal", stream}, forceFlag(request)...)
+		return s.runStreamChange(ctx, request, args, []string{"stream", "info", stream})
+	}
+}
+
+// nats stream copy
+// Args:
+//
+//	[<source>]       Source Stream to copy
+//	[<destination>]  New Stream to create
+func (s *StreamTools) streamCopyHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		stream, err := streamArg(request)
+		if err != nil {
+			return nil, err
+		}
+
+		destination, ok := request.GetArguments()["destination"].(string)
+		if !ok {
+			return nil, fmt.Errorf("missing destination")
+		}
+		if err := validateStreamName("destination", destination); err != nil {
+			return nil, err
+		}
+		if err := validateStreamConfig(request.GetArguments()); err != nil {
+			return nil, err
+		}
+
+		args := []string{"stream", "copy", stream, destination}
+		args = append(args, streamConfigFlags(request.GetArguments())...)
+		return s.runStreamChange(ctx, request, args, []string{"stream", "info", stream})
+	}
+}
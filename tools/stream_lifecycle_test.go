@@ -0,0 +1,164 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestStreamLifecycle_dryRunAndArguments(t *testing.T) {
+	run := testCLIToolCaller(t, (*NATSServerTools).StreamTools, "ORDERS")
+	call := func(tool string, args map[string]any) string {
+		t.Helper()
+		out, err := run(tool, args)
+		if err != nil {
+			t.Fatalf("%s: %v", tool, err)
+		}
+		return out
+	}
+
+	out := call("stream_add", map[string]any{
+		"subjects":              []any{"orders.>", "returns.>"},
+		"retention":             "work",
+		"max_age":               "24h",
+		"replicas":              float64(3),
+		"deny_purge":            false,
+		"sources":               []any{"EU", "US"},
+		"transform_source":      "orders.>",
+		"transform_destination": "archive.orders.>",
+	})
+	for _, want := range []string{"--subjects=orders.>,returns.>", "--retention=work", "--max-age=24h", "--replicas=3", "--no-deny-purge", "--source=EU", "--source=US", "--transform-source=orders.>", "--transform-destination=archive.orders.>", "--defaults"} {
+		if !strings.Contains(out, want+"\n") {
+			t.Fatalf("stream_add did not pass %s:\n%s", want, out)
+		}
+	}
+
+	// A dry run only shows the planned command and the current state.
+	out = call("stream_purge", map[string]any{"subject": "orders.new", "keep": float64(10), "force": true, "dry_run": true})
+	if !strings.HasPrefix(out, "Dry run, nothing was changed. Would run:\n\n  nats stream purge ORDERS --subject=orders.new --keep=10 --force\n") {
+		t.Fatalf("unexpected dry run output:\n%s", out)
+	}
+	if !strings.Contains(out, "subjects\nORDERS\norders.new\n") || strings.Contains(out, "purge\nORDERS") {
+		t.Fatalf("dry run should only run the preview command:\n%s", out)
+	}
+
+	// stream edit leaves the dry run to the CLI, which shows the differences.
+	out = call("stream_edit", map[string]any{"max_msgs": float64(1000), "dry_run": true})
+	if !strings.Contains(out, "edit\nORDERS\n--max-msgs=1000\n--dry-run\n") {
+		t.Fatalf("stream_edit dry run output:\n%s", out)
+	}
+
+	// Dry runs reject the settings the change itself would fail on.
+	for _, tc := range []struct {
+		args map[string]any
+		want string
+	}{
+		{map[string]any{"storage": "disk"}, "invalid storage"},
+		{map[string]any{"storage": "memory", "compression": "s2"}, "compression requires file storage"},
+		{map[string]any{"max_age": "a week"}, "invalid max_age"},
+		{map[string]any{"max_bytes": "lots"}, "invalid max_bytes"},
+		{map[string]any{"mirror": "EU", "subjects": []any{"orders.>"}}, "mirror cannot be combined with subjects"},
+		{map[string]any{"transform_source": "orders.>"}, "must be set together"},
+		{map[string]any{"discard_per_subject": true, "max_msgs_per_subject": float64(1)}, "requires the new discard policy"},
+	} {
+		tc.args["dry_run"] = true
+		if _, err := run("stream_add", tc.args); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("stream_add dry run %v: error = %v, want %q", tc.args, err, tc.want)
+		}
+	}
+	out = call("stream_add", map[string]any{"max_age": "7d", "max_bytes": "512MiB", "dupe_window": "2m", "dry_run": true})
+	if !strings.HasPrefix(out, "Dry run, nothing was changed.") {
+		t.Fatalf("expected a valid dry run to succeed:\n%s", out)
+	}
+}
+
+func TestStreamLifecycle_confirmationAndPreviewCluster(t *testing.T) {
+	run := testCLIToolCaller(t, (*NATSServerTools).StreamTools, "ORDERS")
+	for _, tool := range []string{"stream_rm", "stream_seal", "stream_purge", "stream_rmm", "stream_edit"} {
+		if _, err := run(tool, map[string]any{"id": "7"}); err == nil || !strings.Contains(err.Error(), "set force to true") {
+			t.Fatalf("%s without force: error = %v", tool, err)
+		}
+		if _, err := run(tool, map[string]any{"id": "7", "force": true}); err != nil {
+			t.Fatalf("%s with force: %v", tool, err)
+		}
+	}
+
+	// The preview of a dry run reads the state from the selected cluster.
+	n := newClusterTestTools(t)
+	for _, tool := range n.StreamTools().GetTools() {
+		if tool.Tool.Name != "stream_rm" {
+			continue
+		}
+		result, err := n.wrapHandler(tool.Tool, tool.Handler)(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{
+			Arguments: map[string]any{"account_name": "A", "stream": "ORDERS", "nats_cluster": "edge", "dry_run": true},
+		}})
+		if err != nil {
+			t.Fatalf("stream_rm dry run: %v", err)
+		}
+		out := result.Content[0].(mcp.TextContent).Text
+		if !strings.Contains(out, "Current state (nats stream info ORDERS):\n\n-s\nnats://edge:4222\n") {
+			t.Fatalf("preview did not run against the edge cluster:\n%s", out)
+		}
+	}
+}
+
+// StreamTestSuite runs the stream lifecycle tools against a NATS container.
+type StreamTestSuite struct {
+	containerSuite
+}
+
+func TestStreamSuite(t *testing.T) {
+	suite.Run(t, new(StreamTestSuite))
+}
+
+func (s *StreamTestSuite) TearDownTest() {
+	_ = s.js.DeleteStream(s.ctx, "ORDERS")
+}
+
+func (s *StreamTestSuite) TestStreamAddAndRm() {
+	_, err := s.call((*NATSServerTools).StreamTools, "stream_add", map[string]any{
+		"stream":   "ORDERS",
+		"subjects": []any{"orders.>"},
+		"storage":  "memory",
+		"max_msgs": float64(1000),
+		"max_age":  "1h",
+	})
+	s.Require().NoError(err, "stream_add should create the stream")
+
+	stream, err := s.js.Stream(s.ctx, "ORDERS")
+	s.Require().NoError(err, "the stream should exist after stream_add")
+	config := stream.CachedInfo().Config
+	s.Assert().Equal([]string{"orders.>"}, config.Subjects)
+	s.Assert().Equal(jetstream.MemoryStorage, config.Storage)
+	s.Assert().Equal(int64(1000), config.MaxMsgs)
+	s.Assert().Equal(time.Hour, config.MaxAge)
+
+	_, err = s.call((*NATSServerTools).StreamTools, "stream_rm", map[string]any{"stream": "ORDERS", "force": true})
+	s.Require().NoError(err, "stream_rm should remove the stream")
+	_, err = s.js.Stream(s.ctx, "ORDERS")
+	s.Assert().ErrorIs(err, jetstream.ErrStreamNotFound)
+}
+
+func (s *StreamTestSuite) TestStreamAddDryRun() {
+	out, err := s.call((*NATSServerTools).StreamTools, "stream_add", map[string]any{
+		"stream":   "ORDERS",
+		"subjects": []any{"orders.>"},
+		"dry_run":  true,
+	})
+	s.Require().NoError(err)
+	s.Assert().Contains(out, "Dry run, nothing was changed.")
+	_, err = s.js.Stream(s.ctx, "ORDERS")
+	s.Assert().ErrorIs(err, jetstream.ErrStreamNotFound, "a dry run should not create the stream")
+
+	_, err = s.call((*NATSServerTools).StreamTools, "stream_add", map[string]any{
+		"stream":  "ORDERS",
+		"storage": "disk",
+		"dry_run": true,
+	})
+	s.Assert().ErrorContains(err, "invalid storage")
+}
@@ -0,0 +1,278 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/nats-io/nats.go"
+)
+
+// Limits of the subscribe tool. Callers may lower them but not raise them
+// beyond the maximums, so a sample always ends.
+const (
+	defaultSubscribeMessages = 10
+	maxSubscribeMessages     = 1000
+	defaultSubscribeDuration = 5 * time.Second
+	maxSubscribeDuration     = 5 * time.Minute
+	defaultSubscribeBytes    = 1 << 20
+	maxSubscribeBytes        = 16 << 20
+)
+
+// Reasons a subscription sample ended.
+const (
+	stopMaxMessages = "max_messages"
+	stopDuration    = "duration"
+	stopMaxBytes    = "max_bytes"
+	stopCancelled   = "cancelled"
+)
+
+// SubscribeTools represents the tools that observe core NATS traffic
+type SubscribeTools struct {
+	nats *NATSServerTools
+}
+
+// NewSubscribeTools creates a new SubscribeTools instance
+func NewSubscribeTools(nats *NATSServerTools) *SubscribeTools {
+	return &SubscribeTools{
+		nats: nats,
+	}
+}
+
+// GetTools implements the ToolCategory interface
+func (s *SubscribeTools) GetTools() []Tool {
+	return []Tool{
+		{
+			Tool: mcp.Tool{
+				Name:        "subscribe",
+				Description: "Samples live messages on a subject until max_messages, duration or max_bytes is reached, whichever comes first",
+				InputSchema: s.subscribeSchema(false),
+			},
+			Handler: s.subscribeHandler(false),
+		},
+		{
+			Tool: mcp.Tool{
+				Name: "subscribe_queue",
+				Description: "Samples live messages on a subject as a member of a queue group, until max_messages, duration or max_bytes is reached. " +
+					"Messages the sample receives are taken away from the group's other members and not processed by them",
+				InputSchema: s.subscribeSchema(true),
+			},
+			Handler: s.subscribeHandler(true),
+		},
+		s.eventsTool(),
+	}
+}
+
+// subscribeSchema returns the input schema of the subscribe tools, with a
+// required queue argument for queue group sampling.
+func (s *SubscribeTools) subscribeSchema(queue bool) mcp.ToolInputSchema {
+	props := map[string]interface{}{
+		"subject": map[string]interface{}{
+			"type":        "string",
+			"description": "Subject to subscribe to, wildcards allowed",
+		},
+		"max_messages": map[string]interface{}{
+			"type":        "integer",
+			"description": fmt.Sprintf("Stop after this many messages (at most %d)", maxSubscribeMessages),
+			"default":     defaultSubscribeMessages,
+		},
+		"duration": map[string]interface{}{
+			"type":        "string",
+			"description": fmt.Sprintf("Stop after this long, e.g. 10s (at most %s)", maxSubscribeDuration),
+			"default":     defaultSubscribeDuration.String(),
+		},
+		"max_bytes": map[string]interface{}{
+			"type":        "integer",
+			"description": fmt.Sprintf("Stop before the payloads exceed this many bytes (at most %d)", maxSubscribeBytes),
+			"default":     defaultSubscribeBytes,
+		},
+	}
+	required := []string{"subject"}
+	if queue {
+		props["queue"] = map[string]interface{}{
+			"type":        "string",
+			"description": "Queue group to join",
+		}
+		required = append(required, "queue")
+	}
+	if s.nats.accountNameRequired() {
+		props["account_name"] = map[string]interface{}{
+			"type":        "string",
+			"description": "The NATS account to use (required for credentials-based authentication)",
+		}
+		required = append([]string{"account_name"}, required...)
+	}
+	return mcp.ToolInputSchema{Type: "object", Properties: props, Required: required}
+}
+
+// subscribeLimits bound a subscription sample.
+type subscribeLimits struct {
+	messages int
+	duration time.Duration
+	bytes    int
+}
+
+// sampledMessage is a received message as returned by the subscribe tool.
+// Payloads that are not valid UTF-8 are base64 encoded.
+type sampledMessage struct {
+	Subject    string              `json:"subject"`
+	Reply      string              `json:"reply,omitempty"`
+	Headers    map[string][]string `json:"headers,omitempty"`
+	Payload    string              `json:"payload"`
+	Encoding   string              `json:"encoding"`
+	Size       int                 `json:"size"`
+	ReceivedAt time.Time           `json:"received_at"`
+}
+
+// subscribeResult is the JSON result of the subscribe tool.
+type subscribeResult struct {
+	Subject  string           `json:"subject"`
+	Queue    string           `json:"queue,omitempty"`
+	Stopped  string           `json:"stopped"`
+	Count    int              `json:"count"`
+	Bytes    int              `json:"bytes"`
+	Messages []sampledMessage `json:"messages"`
+}
+
+// subscribeLimitsFromArgs reads and validates the limits of a request.
+func subscribeLimitsFromArgs(arguments map[string]interface{}) (subscribeLimits, error) {
+	limits := subscribeLimits{
+		messages: defaultSubscribeMessages,
+		duration: defaultSubscribeDuration,
+		bytes:    defaultSubscribeBytes,
+	}
+	if n, ok := arguments["max_messages"].(float64); ok {
+		if n < 1 || n > maxSubscribeMessages {
+			return limits, fmt.Errorf("max_messages must be between 1 and %d", maxSubscribeMessages)
+		}
+		limits.messages = int(n)
+	}
+	if d, ok := arguments["duration"].(string); ok {
+		duration, err := time.ParseDuration(d)
+		if err != nil {
+			return limits, fmt.Errorf("invalid duration: %w", err)
+		}
+		if duration <= 0 || duration > maxSubscribeDuration {
+			return limits, fmt.Errorf("duration must be positive and at most %s", maxSubscribeDuration)
+		}
+		limits.duration = duration
+	}
+	if n, ok := arguments["max_bytes"].(float64); ok {
+		if n < 1 || n > maxSubscribeBytes {
+			return limits, fmt.Errorf("max_bytes must be between 1 and %d", maxSubscribeBytes)
+		}
+		limits.bytes = int(n)
+	}
+	return limits, nil
+}
+
+// subscribeHandler samples a subject. Only queue group sampling, which is
+// classified as mutating, reads the queue argument.
+func (s *SubscribeTools) subscribeHandler(queueGroup bool) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		subject, ok := request.GetArguments()["subject"].(string)
+		if !ok || subject == "" {
+			return nil, fmt.Errorf("missing subject")
+		}
+		var queue string
+		if queueGroup {
+			if queue, _ = request.GetArguments()["queue"].(string); queue == "" {
+				return nil, fmt.Errorf("missing queue")
+			}
+		}
+
+		limits, err := subscribeLimitsFromArgs(request.GetArguments())
+		if err != nil {
+			return nil, err
+		}
+
+		nc, err := s.nats.connect(ctx, request.GetArguments())
+		if err != nil {
+			return nil, err
+		}
+		defer nc.Close()
+
+		// The buffer holds more than a full sample, so the subscription is
+		// never reported as a slow consumer.
+		msgs := make(chan *nats.Msg, maxSubscribeMessages+1)
+		var sub *nats.Subscription
+		if queue != "" {
+			sub, err = nc.ChanQueueSubscribe(subject, queue, msgs)
+		} else {
+			sub, err = nc.ChanSubscribe(subject, msgs)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to subscribe to %s: %w", subject, err)
+		}
+		defer func() { _ = sub.Unsubscribe() }()
+		if err := nc.Flush(); err != nil {
+			return nil, fmt.Errorf("failed to subscribe to %s: %w", subject, err)
+		}
+
+		result := collectMessages(ctx, msgs, limits)
+		result.Subject = subject
+		result.Queue = queue
+
+		out, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode messages: %w", err)
+		}
+		return mcp.NewToolResultText(string(out)), nil
+	}
+}
+
+// collectMessages reads messages from msgs until one of the limits is
+// reached or ctx is done.
+func collectMessages(ctx context.Context, msgs <-chan *nats.Msg, limits subscribeLimits) subscribeResult {
+	result := subscribeResult{Messages: []sampledMessage{}}
+	timer := time.NewTimer(limits.duration)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			result.Stopped = stopCancelled
+			return result
+		case <-timer.C:
+			result.Stopped = stopDuration
+			return result
+		case msg := <-msgs:
+			if result.Bytes+len(msg.Data) > limits.bytes {
+				result.Stopped = stopMaxBytes
+				return result
+			}
+			result.Messages = append(result.Messages, sampleMessage(msg))
+			result.Count++
+			result.Bytes += len(msg.Data)
+			if result.Count >= limits.messages {
+				result.Stopped = stopMaxMessages
+				return result
+			}
+		}
+	}
+}
+
+func sampleMessage(msg *nats.Msg) sampledMessage {
+	sampled := sampledMessage{
+		Subject:    msg.Subject,
+		Reply:      msg.Reply,
+		Size:       len(msg.Data),
+		ReceivedAt: time.Now().UTC(),
+	}
+	if len(msg.Header) > 0 {
+		sampled.Headers = msg.Header
+	}
+	if utf8.Valid(msg.Data) {
+		sampled.Payload = string(msg.Data)
+		sampled.Encoding = "text"
+	} else {
+		sampled.Payload = base64.StdEncoding.EncodeToString(msg.Data)
+		sampled.Encoding = "base64"
+	}
+	return sampled
+}
@@ -0,0 +1,83 @@
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+func TestCollectMessages_stopsAtFirstLimit(t *testing.T) {
+	feed := func(msgs ...*nats.Msg) chan *nats.Msg {
+		ch := make(chan *nats.Msg, len(msgs))
+		for _, msg := range msgs {
+			ch <- msg
+		}
+		return ch
+	}
+	text := &nats.Msg{Subject: "orders.new", Reply: "_INBOX.1", Header: nats.Header{"Id": []string{"1"}}, Data: []byte("hello")}
+	binary := &nats.Msg{Subject: "orders.raw", Data: []byte{0xff, 0x00}}
+
+	result := collectMessages(context.Background(), feed(text, binary, text), subscribeLimits{messages: 2, duration: time.Minute, bytes: 100})
+	if result.Stopped != stopMaxMessages || result.Count != 2 || result.Bytes != 7 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	first, second := result.Messages[0], result.Messages[1]
+	if first.Payload != "hello" || first.Encoding != "text" || first.Reply != "_INBOX.1" || first.Headers["Id"][0] != "1" {
+		t.Fatalf("unexpected text message: %+v", first)
+	}
+	if second.Payload != "/wA=" || second.Encoding != "base64" || second.Size != 2 {
+		t.Fatalf("unexpected binary message: %+v", second)
+	}
+
+	result = collectMessages(context.Background(), feed(text, text), subscribeLimits{messages: 10, duration: time.Minute, bytes: 8})
+	if result.Stopped != stopMaxBytes || result.Count != 1 {
+		t.Fatalf("expected the byte budget to stop the sample: %+v", result)
+	}
+
+	result = collectMessages(context.Background(), feed(text), subscribeLimits{messages: 10, duration: 20 * time.Millisecond, bytes: 100})
+	if result.Stopped != stopDuration || result.Count != 1 {
+		t.Fatalf("expected the duration to stop the sample: %+v", result)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result = collectMessages(ctx, feed(), subscribeLimits{messages: 10, duration: time.Minute, bytes: 100})
+	if result.Stopped != stopCancelled || result.Messages == nil {
+		t.Fatalf("expected a cancelled, empty sample: %+v", result)
+	}
+}
+
+func TestSubscribeLimitsFromArgs(t *testing.T) {
+	limits, err := subscribeLimitsFromArgs(map[string]interface{}{})
+	if err != nil || limits.messages != defaultSubscribeMessages || limits.duration != defaultSubscribeDuration || limits.bytes != defaultSubscribeBytes {
+		t.Fatalf("defaults = %+v, %v", limits, err)
+	}
+	for _, args := range []map[string]interface{}{
+		{"max_messages": float64(0)},
+		{"max_messages": float64(maxSubscribeMessages + 1)},
+		{"duration": "1h"},
+		{"duration": "soon"},
+		{"max_bytes": float64(maxSubscribeBytes + 1)},
+	} {
+		if _, err := subscribeLimitsFromArgs(args); err == nil {
+			t.Fatalf("expected %v to be rejected", args)
+		}
+	}
+}
+
+func TestSubscribeTools_queueGroupsNeedWriteAccess(t *testing.T) {
+	n, err := NewNATSServerTools()
+	if err != nil {
+		t.Fatalf("NewNATSServerTools: %v", err)
+	}
+	for _, tool := range buildTools(n, true) {
+		if tool.Tool.Name == "subscribe_queue" {
+			t.Fatalf("subscribe_queue must not be registered in read-only mode")
+		}
+		if _, ok := tool.Tool.InputSchema.Properties["queue"]; ok && tool.Tool.Name == "subscribe" {
+			t.Fatalf("subscribe must not accept a queue group")
+		}
+	}
+}
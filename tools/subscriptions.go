@@ -0,0 +1,370 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/sinadarbouy/mcp-nats/internal/logger"
+)
+
+// Kinds of resources that support resources/subscribe.
+const (
+	resourceKVBucket     = "kv-bucket"
+	resourceKVEntry      = "kv-entry"
+	resourceObjectBucket = "object-bucket"
+	resourceObject       = "object"
+)
+
+// streamUpdatedAdvisory is published by the server whenever a stream's
+// configuration changes, which is how sealing an object store shows up.
+const streamUpdatedAdvisory = "$JS.EVENT.ADVISORY.STREAM.UPDATED."
+
+// maxSessionWatches caps the resources one session can subscribe to, as every
+// watch holds its own NATS connection.
+const maxSessionWatches = 32
+
+// resourceRef identifies a subscribable resource parsed from a nats:// URI.
+type resourceRef struct {
+	Kind    string
+	Account string
+	Bucket  string
+	// Name is the KV key or object name, empty for bucket resources.
+	Name string
+}
+
+// parseResourceURI parses the URI of a KV key, KV bucket, object bucket or
+// object resource.
+func parseResourceURI(uri string) (resourceRef, error) {
+	rest, ok := strings.CutPrefix(uri, resourceScheme)
+	if !ok {
+		return resourceRef{}, fmt.Errorf("unsupported resource URI %s", uri)
+	}
+	segments := strings.Split(rest, "/")
+	for i, segment := range segments {
+		value, err := url.PathUnescape(segment)
+		if err != nil || value == "" {
+			return resourceRef{}, fmt.Errorf("invalid resource URI %s", uri)
+		}
+		segments[i] = value
+	}
+
+	if len(segments) == 3 || len(segments) == 4 {
+		ref := resourceRef{Account: segments[0], Bucket: segments[2]}
+		switch segments[1] {
+		case "kv":
+			ref.Kind = resourceKVBucket
+		case "object":
+			ref.Kind = resourceObjectBucket
+		}
+		if ref.Kind != "" {
+			if len(segments) == 4 {
+				ref.Name = segments[3]
+				if ref.Kind == resourceKVBucket {
+					ref.Kind = resourceKVEntry
+				} else {
+					ref.Kind = resourceObject
+				}
+			}
+			return ref, nil
+		}
+	}
+	return resourceRef{}, fmt.Errorf("resource %s does not support subscriptions", uri)
+}
+
+// watchFunc starts a background watch of ref, calling notify on every change,
+// and returns a function that stops it.
+type watchFunc func(ctx context.Context, ref resourceRef, notify func()) (func(), error)
+
+// ResourceWatcher backs resources/subscribe for KV and object store resources.
+// Each subscription gets its own background watch, which sends
+// notifications/resources/updated to the subscribing session until the
+// session unsubscribes or ends.
+type ResourceWatcher struct {
+	nats  *NATSServerTools
+	watch watchFunc
+
+	mu sync.Mutex
+	// watches holds the stop function of each watch by session and URI.
+	watches map[string]map[string]func()
+}
+
+// NewResourceWatcher creates a new ResourceWatcher instance
+func NewResourceWatcher(nats *NATSServerTools) *ResourceWatcher {
+	w := &ResourceWatcher{
+		nats:    nats,
+		watches: make(map[string]map[string]func()),
+	}
+	w.watch = w.watchJetStream
+	return w
+}
+
+// AddHooks wires the watcher into the subscribe, unsubscribe and session
+// lifecycle hooks of the MCP server.
+func (w *ResourceWatcher) AddHooks(hooks *server.Hooks) {
+	// The watch is opened before resources/subscribe is acknowledged, so an
+	// invalid URI or a bucket that cannot be watched fails the request.
+	hooks.AddOnRequestInitialization(func(ctx context.Context, _ any, message any) error {
+		raw, ok := message.(json.RawMessage)
+		if !ok {
+			return nil
+		}
+		var request struct {
+			Method mcp.MCPMethod       `json:"method"`
+			Params mcp.SubscribeParams `json:"params"`
+		}
+		if err := json.Unmarshal(raw, &request); err != nil || request.Method != mcp.MethodResourcesSubscribe {
+			return nil
+		}
+		return w.subscribe(ctx, request.Params.URI)
+	})
+	hooks.AddOnError(func(ctx context.Context, _ any, method mcp.MCPMethod, message any, _ error) {
+		request, ok := message.(*mcp.SubscribeRequest)
+		if !ok || method != mcp.MethodResourcesSubscribe {
+			return
+		}
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			w.unsubscribe(session.SessionID(), request.Params.URI)
+		}
+	})
+	hooks.AddAfterUnsubscribe(func(ctx context.Context, _ any, message *mcp.UnsubscribeRequest, _ *mcp.EmptyResult) {
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			w.unsubscribe(session.SessionID(), message.Params.URI)
+		}
+	})
+	hooks.AddOnUnregisterSession(func(_ context.Context, session server.ClientSession) {
+		w.closeSession(session.SessionID())
+	})
+}
+
+// Close stops all watches.
+func (w *ResourceWatcher) Close() {
+	w.mu.Lock()
+	watches := w.watches
+	w.watches = make(map[string]map[string]func())
+	w.mu.Unlock()
+
+	for _, byURI := range watches {
+		for _, stop := range byURI {
+			stop()
+		}
+	}
+}
+
+func (w *ResourceWatcher) subscribe(ctx context.Context, uri string) error {
+	session := server.ClientSessionFromContext(ctx)
+	mcpServer := server.ServerFromContext(ctx)
+	if session == nil || mcpServer == nil {
+		return nil
+	}
+	sessionID := session.SessionID()
+
+	ref, err := parseResourceURI(uri)
+	if err != nil {
+		return err
+	}
+	w.mu.Lock()
+	err = checkWatchLimit(w.watches[sessionID], uri)
+	w.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	notify := func() {
+		err := mcpServer.SendNotificationToSpecificClient(sessionID, mcp.MethodNotificationResourceUpdated, map[string]any{
+			"uri": uri,
+		})
+		if err != nil {
+			logger.Debug("Failed to send resource update", "uri", uri, "session", sessionID, "error", err)
+		}
+	}
+	stop, err := w.watch(ctx, ref, notify)
+	if err != nil {
+		return fmt.Errorf("failed to watch resource %s: %w", uri, err)
+	}
+
+	w.mu.Lock()
+	byURI, ok := w.watches[sessionID]
+	if !ok {
+		byURI = make(map[string]func())
+		w.watches[sessionID] = byURI
+	}
+	// Another subscription of the session may have taken the last slot
+	// while the watch was being opened.
+	if err := checkWatchLimit(byURI, uri); err != nil {
+		w.mu.Unlock()
+		stop()
+		return err
+	}
+	previous := byURI[uri]
+	byURI[uri] = stop
+	w.mu.Unlock()
+
+	if previous != nil {
+		previous()
+	}
+	logger.Debug("Watching resource", "uri", uri, "session", sessionID)
+	return nil
+}
+
+// checkWatchLimit fails if watching uri would take a session with the given
+// watches over maxSessionWatches. Subscribing again to a watched URI replaces
+// its watch and always fits.
+func checkWatchLimit(byURI map[string]func(), uri string) error {
+	if _, ok := byURI[uri]; !ok && len(byURI) >= maxSessionWatches {
+		return fmt.Errorf("too many resource subscriptions: a session can watch at most %d resources", maxSessionWatches)
+	}
+	return nil
+}
+
+func (w *ResourceWatcher) unsubscribe(sessionID, uri string) {
+	w.mu.Lock()
+	stop := w.watches[sessionID][uri]
+	delete(w.watches[sessionID], uri)
+	if len(w.watches[sessionID]) == 0 {
+		delete(w.watches, sessionID)
+	}
+	w.mu.Unlock()
+
+	if stop != nil {
+		stop()
+	}
+}
+
+func (w *ResourceWatcher) closeSession(sessionID string) {
+	w.mu.Lock()
+	byURI := w.watches[sessionID]
+	delete(w.watches, sessionID)
+	w.mu.Unlock()
+
+	for _, stop := range byURI {
+		stop()
+	}
+}
+
+// watchJetStream watches ref over a dedicated NATS connection for the account
+// named in the resource URI.
+func (w *ResourceWatcher) watchJetStream(ctx context.Context, ref resourceRef, notify func()) (func(), error) {
+	executor, err := w.nats.GetExecutor(ctx, ref.Account)
+	if err != nil {
+		return nil, err
+	}
+	nc, err := executor.Connect()
+	if err != nil {
+		return nil, err
+	}
+	js, err := jetstream.New(nc)
+	if err != nil {
+		nc.Close()
+		return nil, err
+	}
+
+	watchCtx, cancel := context.WithCancel(context.Background())
+	stop := func() {
+		cancel()
+		nc.Close()
+	}
+
+	switch ref.Kind {
+	case resourceKVBucket, resourceKVEntry:
+		err = watchKV(watchCtx, js, ref, notify)
+	case resourceObjectBucket, resourceObject:
+		err = watchObjects(watchCtx, nc, js, ref, notify)
+	default:
+		err = fmt.Errorf("resource kind %s does not support subscriptions", ref.Kind)
+	}
+	if err != nil {
+		stop()
+		return nil, err
+	}
+	return stop, nil
+}
+
+// watchKV notifies on every put, delete or purge of the key, or of any key in
+// the bucket.
+func watchKV(ctx context.Context, js jetstream.JetStream, ref resourceRef, notify func()) error {
+	kv, err := js.KeyValue(ctx, ref.Bucket)
+	if err != nil {
+		return fmt.Errorf("failed to open KV bucket %s: %w", ref.Bucket, err)
+	}
+	keys := jetstream.AllKeys
+	if ref.Kind == resourceKVEntry {
+		keys = ref.Name
+	}
+	watcher, err := kv.Watch(ctx, keys, jetstream.UpdatesOnly())
+	if err != nil {
+		return fmt.Errorf("failed to watch KV bucket %s: %w", ref.Bucket, err)
+	}
+
+	go func() {
+		defer func() { _ = watcher.Stop() }()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case entry, ok := <-watcher.Updates():
+				if !ok {
+					return
+				}
+				if entry != nil {
+					notify()
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// watchObjects notifies when an object is added, updated or deleted, and when
+// the bucket is sealed.
+func watchObjects(ctx context.Context, nc *nats.Conn, js jetstream.JetStream, ref resourceRef, notify func()) error {
+	store, err := js.ObjectStore(ctx, ref.Bucket)
+	if err != nil {
+		return fmt.Errorf("failed to open object store %s: %w", ref.Bucket, err)
+	}
+	watcher, err := store.Watch(ctx, jetstream.UpdatesOnly())
+	if err != nil {
+		return fmt.Errorf("failed to watch object store %s: %w", ref.Bucket, err)
+	}
+
+	// Sealing only changes the stream configuration, so it is picked up from
+	// the stream update advisory. Missing permissions for advisories only
+	// lose seal notifications.
+	sealed := make(chan *nats.Msg, 1)
+	sub, err := nc.ChanSubscribe(streamUpdatedAdvisory+"OBJ_"+ref.Bucket, sealed)
+	if err != nil {
+		logger.Warn("Failed to subscribe to object store advisories", "bucket", ref.Bucket, "error", err)
+	}
+
+	go func() {
+		defer func() {
+			_ = watcher.Stop()
+			if sub != nil {
+				_ = sub.Unsubscribe()
+			}
+		}()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sealed:
+				notify()
+			case info, ok := <-watcher.Updates():
+				if !ok {
+					return
+				}
+				if info != nil && (ref.Kind == resourceObjectBucket || info.Name == ref.Name) {
+					notify()
+				}
+			}
+		}
+	}()
+	return nil
+}
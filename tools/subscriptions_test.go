@@ -0,0 +1,141 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sinadarbouy/mcp-nats/internal/logger"
+)
+
+func TestParseResourceURI(t *testing.T) {
+	tests := []struct {
+		uri     string
+		want    resourceRef
+		wantErr bool
+	}{
+		{uri: kvBucketURI("A", "config"), want: resourceRef{Kind: resourceKVBucket, Account: "A", Bucket: "config"}},
+		{uri: kvKeyURI("A", "config", "app/db.url"), want: resourceRef{Kind: resourceKVEntry, Account: "A", Bucket: "config", Name: "app/db.url"}},
+		{uri: objectBucketURI("A", "files"), want: resourceRef{Kind: resourceObjectBucket, Account: "A", Bucket: "files"}},
+		{uri: objectURI("A", "files", "reports/q1 2024.pdf"), want: resourceRef{Kind: resourceObject, Account: "A", Bucket: "files", Name: "reports/q1 2024.pdf"}},
+		{uri: streamURI("A", "ORDERS"), wantErr: true},
+		{uri: "nats://A/kv", wantErr: true},
+		{uri: "nats://A/kv//key", wantErr: true},
+		{uri: "https://A/kv/config", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseResourceURI(tt.uri)
+		if tt.wantErr {
+			if err == nil {
+				t.Fatalf("parseResourceURI(%q) = %+v, want error", tt.uri, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("parseResourceURI(%q): %v", tt.uri, err)
+		}
+		if got != tt.want {
+			t.Fatalf("parseResourceURI(%q) = %+v, want %+v", tt.uri, got, tt.want)
+		}
+	}
+}
+
+type testSession struct {
+	id            string
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *testSession) Initialize()       {}
+func (s *testSession) Initialized() bool { return true }
+func (s *testSession) SessionID() string { return s.id }
+func (s *testSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+
+func TestResourceWatcher_lifecycle(t *testing.T) {
+	logger.Initialize(logger.Config{Level: logger.LevelError})
+	watcher := NewResourceWatcher(nil)
+	stopped := map[string]int{}
+	var notify func()
+	var watchErr error
+	watcher.watch = func(_ context.Context, ref resourceRef, n func()) (func(), error) {
+		if watchErr != nil {
+			return nil, watchErr
+		}
+		notify = n
+		return func() { stopped[ref.Bucket+"/"+ref.Name]++ }, nil
+	}
+
+	hooks := &server.Hooks{}
+	watcher.AddHooks(hooks)
+	s := server.NewMCPServer("test", "0.0.0", server.WithResourceCapabilities(true, false), server.WithHooks(hooks))
+
+	session := &testSession{id: "s1", notifications: make(chan mcp.JSONRPCNotification, 1)}
+	if err := s.RegisterSession(context.Background(), session); err != nil {
+		t.Fatalf("RegisterSession: %v", err)
+	}
+	ctx := s.WithContext(context.Background(), session)
+
+	send := func(method, uri string) mcp.JSONRPCMessage {
+		msg := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":%q,"params":{"uri":%q}}`, method, uri)
+		return s.HandleMessage(ctx, json.RawMessage(msg))
+	}
+	call := func(method, uri string) {
+		t.Helper()
+		if resp, ok := send(method, uri).(mcp.JSONRPCResponse); !ok {
+			t.Fatalf("%s %s: unexpected response %#v", method, uri, resp)
+		}
+	}
+	reject := func(uri, want string) {
+		t.Helper()
+		resp, ok := send("resources/subscribe", uri).(mcp.JSONRPCError)
+		if !ok || !strings.Contains(resp.Error.Message, want) {
+			t.Fatalf("subscribe %s: expected an error containing %q, got %#v", uri, want, resp)
+		}
+	}
+
+	keyURI := kvKeyURI("A", "config", "db")
+	call("resources/subscribe", keyURI)
+	notify()
+	select {
+	case n := <-session.notifications:
+		if n.Method != mcp.MethodNotificationResourceUpdated || n.Params.AdditionalFields["uri"] != keyURI {
+			t.Fatalf("unexpected notification %+v", n)
+		}
+	default:
+		t.Fatal("expected a resource updated notification")
+	}
+
+	call("resources/unsubscribe", keyURI)
+	if stopped["config/db"] != 1 {
+		t.Fatalf("watch not stopped on unsubscribe: %v", stopped)
+	}
+
+	call("resources/subscribe", objectBucketURI("A", "files"))
+	reject(streamURI("A", "ORDERS"), "does not support subscriptions")
+	watchErr = fmt.Errorf("bucket not found")
+	reject(kvBucketURI("A", "missing"), "bucket not found")
+	watchErr = nil
+
+	// Subscriptions past the per-session limit are refused and not watched.
+	for i := len(watcher.watches["s1"]); i < maxSessionWatches; i++ {
+		call("resources/subscribe", kvKeyURI("A", "config", fmt.Sprintf("key%d", i)))
+	}
+	reject(kvKeyURI("A", "config", "one-too-many"), "too many resource subscriptions")
+	call("resources/subscribe", objectBucketURI("A", "files"))
+	if stopped["config/one-too-many"] != 0 || len(watcher.watches["s1"]) != maxSessionWatches {
+		t.Fatalf("watch opened past the limit: %d watches", len(watcher.watches["s1"]))
+	}
+
+	s.UnregisterSession(context.Background(), session.id)
+	if stopped["files/"] != 2 {
+		t.Fatalf("watch not stopped when the session ended: %v", stopped)
+	}
+	if len(watcher.watches) != 0 {
+		t.Fatalf("watches left after the session ended: %v", watcher.watches)
+	}
+}
@@ -0,0 +1,22 @@
+package tools
+
+import (
+	"context"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// TimeoutMiddleware cancels the context of the wrapped tool call after d.
+// NATS CLI invocations and client requests made with that context are
+// aborted when it expires.
+func TimeoutMiddleware(d time.Duration) Middleware {
+	return func(_ mcp.Tool, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+			return next(ctx, request)
+		}
+	}
+}
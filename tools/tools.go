@@ -1,8 +1,11 @@
 package tools
 
 import (
+	"context"
+
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	mcpnats "github.com/sinadarbouy/mcp-nats"
 )
 
 // ToolCategory represents a group of related NATS tools
@@ -16,6 +19,10 @@ type Tool struct {
 	Handler server.ToolHandlerFunc
 }
 
+// Middleware wraps the handler of a tool. It receives the tool definition so
+// it can key behaviour (metrics, auditing, limits) on the tool name.
+type Middleware func(tool mcp.Tool, next server.ToolHandlerFunc) server.ToolHandlerFunc
+
 // Register registers a single tool with the MCP server
 func (t *Tool) Register(mcp *server.MCPServer) {
 	mcp.AddTool(t.Tool, t.Handler)
@@ -23,13 +30,73 @@ func (t *Tool) Register(mcp *server.MCPServer) {
 
 // RegisterTools registers all tools from all categories with the MCP server.
 // When readOnly is true, mutating tools (see IsMutatingTool) are not registered.
+// Handlers are wrapped with the middlewares installed through NATSServerTools.Use,
+// and tools are annotated from their classification in toolClasses.
 func RegisterTools(mcp *server.MCPServer, n *NATSServerTools, readOnly bool) {
+	for _, tool := range buildTools(n, readOnly) {
+		tool.Register(mcp)
+	}
+}
+
+// SetTools replaces the tools registered with the MCP server by the current
+// catalog, as RegisterTools would register it. Clients are sent
+// notifications/tools/list_changed when the server declares that capability.
+func SetTools(mcp *server.MCPServer, n *NATSServerTools, readOnly bool) {
+	tools := buildTools(n, readOnly)
+	serverTools := make([]server.ServerTool, 0, len(tools))
+	for _, tool := range tools {
+		serverTools = append(serverTools, server.ServerTool{Tool: tool.Tool, Handler: tool.Handler})
+	}
+	mcp.SetTools(serverTools...)
+}
+
+// buildTools returns the annotated tools with wrapped handlers.
+func buildTools(n *NATSServerTools, readOnly bool) []Tool {
+	multiCluster := len(n.currentClusters()) > 1
+	var tools []Tool
 	for _, category := range n.toolCategories() {
 		for _, tool := range category.GetTools() {
 			if readOnly && IsMutatingTool(tool.Tool.Name) {
 				continue
 			}
-			tool.Register(mcp)
+			if annotations, ok := toolAnnotations(tool.Tool.Name); ok {
+				tool.Tool.Annotations = annotations
+			}
+			if multiCluster {
+				addClusterArgument(&tool.Tool, n.ClusterNames())
+			}
+			tool.Handler = n.wrapHandler(tool.Tool, tool.Handler)
+			tools = append(tools, tool)
+		}
+	}
+	return tools
+}
+
+// requestAccountName returns the NATS account a tool call runs as, without
+// failing when the call omits it.
+func requestAccountName(ctx context.Context, request mcp.CallToolRequest) string {
+	if account, ok := request.GetArguments()["account_name"].(string); ok && account != "" {
+		return account
+	}
+	account, err := mcpnats.DetermineAccountName(ctx, request.GetArguments())
+	if err != nil {
+		return ""
+	}
+	return account
+}
+
+// resolvedAccountName returns the account the executor of a tool call
+// resolves to, or "unknown" when the call names an account without
+// credentials. Unlike requestAccountName it never echoes client input that
+// does not match the configuration.
+func resolvedAccountName(ctx context.Context, request mcp.CallToolRequest) string {
+	if strategy, err := mcpnats.GetAuthStrategyFromContext(ctx); err == nil {
+		return strategy.GetAccountName()
+	}
+	if account, ok := request.GetArguments()["account_name"].(string); ok && account != "" {
+		if _, err := mcpnats.GetCredsFromContext(ctx, account); err == nil {
+			return account
 		}
 	}
+	return "unknown"
 }
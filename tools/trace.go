@@ -0,0 +1,420 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/nats-io/nats.go"
+)
+
+// Message trace headers understood by NATS 2.11 and later.
+const (
+	traceDestHeader = "Nats-Trace-Dest"
+	traceOnlyHeader = "Nats-Trace-Only"
+	traceHopHeader  = "Nats-Trace-Hop"
+)
+
+// Limits of the trace tool.
+const (
+	defaultTraceTimeout = 2 * time.Second
+	maxTraceTimeout     = time.Minute
+	maxTraceEvents      = 1000
+)
+
+// stopComplete is reported when every server hop reported its trace.
+const stopComplete = "complete"
+
+// traceConnectionKinds names the connection kinds of ingress and egress
+// trace events.
+var traceConnectionKinds = []string{"client", "route", "gateway", "system", "leafnode", "jetstream", "account"}
+
+// traceSteps names the trace event types.
+var traceSteps = map[string]string{
+	"in": "ingress",
+	"sm": "subject_mapping",
+	"se": "stream_export",
+	"si": "service_import",
+	"js": "jetstream",
+	"eg": "egress",
+}
+
+// traceTool returns the tool tracing the path of a message through the servers.
+func (r *RequestTools) traceTool() Tool {
+	props := map[string]interface{}{
+		"subject": map[string]interface{}{
+			"type":        "string",
+			"description": "Subject to publish the traced message to",
+		},
+		"body": map[string]interface{}{
+			"type":        "string",
+			"description": "Message body",
+		},
+		"header": map[string]interface{}{
+			"type":        "array",
+			"items":       map[string]interface{}{"type": "string"},
+			"description": "Adds headers to the message, as Name:value",
+		},
+		"deliver": map[string]interface{}{
+			"type":        "boolean",
+			"description": "Deliver the message to subscribers and streams; by default it is only traced",
+			"default":     false,
+		},
+		"timeout": map[string]interface{}{
+			"type":        "string",
+			"description": fmt.Sprintf("How long to wait for the trace of every server hop (at most %s)", maxTraceTimeout),
+			"default":     defaultTraceTimeout.String(),
+		},
+	}
+	required := []string{"subject"}
+	if r.nats.accountNameRequired() {
+		props["account_name"] = map[string]interface{}{
+			"type":        "string",
+			"description": "The NATS account to use (required for credentials-based authentication)",
+		}
+		required = append([]string{"account_name"}, required...)
+	}
+
+	return Tool{
+		Tool: mcp.Tool{
+			Name: "trace",
+			Description: "Traces a message through the NATS servers and returns its ordered path: ingress, subject mappings, " +
+				"stream exports, service imports, routes, gateways, leafnodes, JetStream stores and egress to subscribers. " +
+				"Use it to find out why a message did not arrive. Requires NATS 2.11 or later",
+			InputSchema: mcp.ToolInputSchema{Type: "object", Properties: props, Required: required},
+		},
+		Handler: r.traceHandler(),
+	}
+}
+
+// traceEvent is the trace a server sends for each message it handled.
+type traceEvent struct {
+	Server struct {
+		Name    string `json:"name"`
+		Cluster string `json:"cluster"`
+	} `json:"server"`
+	Request struct {
+		Header map[string][]string `json:"header"`
+	} `json:"request"`
+	Hops   int              `json:"hops"`
+	Events []traceEventItem `json:"events"`
+}
+
+// hop returns the hop identifier of the server, empty for the first server.
+func (e traceEvent) hop() string {
+	return nats.Header(e.Request.Header).Get(traceHopHeader)
+}
+
+// traceEventItem holds the fields of every trace event type.
+type traceEventItem struct {
+	Type       string    `json:"type"`
+	Timestamp  time.Time `json:"ts"`
+	Kind       int       `json:"kind"`
+	CID        uint64    `json:"cid"`
+	Name       string    `json:"name"`
+	Account    string    `json:"acc"`
+	Subj       string    `json:"subj"`
+	Subject    string    `json:"subject"`
+	From       string    `json:"from"`
+	To         string    `json:"to"`
+	Stream     string    `json:"stream"`
+	NoInterest bool      `json:"nointerest"`
+	Hop        string    `json:"hop"`
+	Sub        string    `json:"sub"`
+	Queue      string    `json:"queue"`
+	Error      string    `json:"error"`
+}
+
+// traceStep is one step of a message path.
+type traceStep struct {
+	Server       string    `json:"server"`
+	Hop          string    `json:"hop,omitempty"`
+	Step         string    `json:"step"`
+	Time         time.Time `json:"time"`
+	Kind         string    `json:"kind,omitempty"`
+	CID          uint64    `json:"cid,omitempty"`
+	Name         string    `json:"name,omitempty"`
+	Account      string    `json:"account,omitempty"`
+	Subject      string    `json:"subject,omitempty"`
+	From         string    `json:"from,omitempty"`
+	To           string    `json:"to,omitempty"`
+	Stream       string    `json:"stream,omitempty"`
+	NoInterest   bool      `json:"no_interest,omitempty"`
+	Subscription string    `json:"subscription,omitempty"`
+	Queue        string    `json:"queue,omitempty"`
+	NextHop      string    `json:"next_hop,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	Summary      string    `json:"summary"`
+}
+
+// traceResult is the JSON result of the trace tool.
+type traceResult struct {
+	Subject   string `json:"subject"`
+	TraceOnly bool   `json:"trace_only"`
+	Stopped   string `json:"stopped"`
+	Servers   int    `json:"servers"`
+	// Deliveries counts the subscribers that received the message and
+	// Stored the streams that accepted it.
+	Deliveries  int         `json:"deliveries"`
+	Stored      int         `json:"stored"`
+	MissingHops []string    `json:"missing_hops,omitempty"`
+	Errors      []string    `json:"errors,omitempty"`
+	Hint        string      `json:"hint,omitempty"`
+	Path        []traceStep `json:"path"`
+}
+
+// supportsMessageTracing reports whether a server version handles trace
+// headers. Older servers ignore them and deliver traced messages.
+func supportsMessageTracing(version string) bool {
+	var major, minor int
+	if _, err := fmt.Sscanf(version, "%d.%d", &major, &minor); err != nil {
+		return false
+	}
+	return major > 2 || major == 2 && minor >= 11
+}
+
+func (r *RequestTools) traceHandler() server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		subject, ok := request.GetArguments()["subject"].(string)
+		if !ok || subject == "" {
+			return nil, fmt.Errorf("missing subject")
+		}
+		body, _ := request.GetArguments()["body"].(string)
+		deliver, _ := request.GetArguments()["deliver"].(bool)
+		timeout := defaultTraceTimeout
+		if t, ok := request.GetArguments()["timeout"].(string); ok && t != "" {
+			var err error
+			if timeout, err = time.ParseDuration(t); err != nil {
+				return nil, fmt.Errorf("invalid timeout: %w", err)
+			}
+			if timeout <= 0 || timeout > maxTraceTimeout {
+				return nil, fmt.Errorf("timeout must be positive and at most %s", maxTraceTimeout)
+			}
+		}
+		headers, err := messageHeaders(ctx, request.GetArguments())
+		if err != nil {
+			return nil, err
+		}
+
+		nc, err := r.nats.connect(ctx, request.GetArguments())
+		if err != nil {
+			return nil, err
+		}
+		defer nc.Close()
+		if !deliver && !supportsMessageTracing(nc.ConnectedServerVersion()) {
+			return nil, fmt.Errorf("server version %s does not support message tracing and would deliver the message, NATS 2.11 or later is required", nc.ConnectedServerVersion())
+		}
+
+		dest := nc.NewRespInbox()
+		traces := make(chan *nats.Msg, maxTraceEvents+1)
+		sub, err := nc.ChanSubscribe(dest, traces)
+		if err != nil {
+			return nil, fmt.Errorf("failed to subscribe for traces: %w", err)
+		}
+		defer func() { _ = sub.Unsubscribe() }()
+
+		headers.Set(traceDestHeader, dest)
+		if !deliver {
+			headers.Set(traceOnlyHeader, "true")
+		}
+		if err := nc.PublishMsg(&nats.Msg{Subject: subject, Data: []byte(body), Header: headers}); err != nil {
+			return nil, fmt.Errorf("failed to publish to %s: %w", subject, err)
+		}
+		if err := nc.Flush(); err != nil {
+			return nil, fmt.Errorf("failed to publish to %s: %w", subject, err)
+		}
+
+		events, stopped, err := collectTraces(ctx, traces, timeout)
+		if err != nil {
+			return nil, err
+		}
+		result := traceReport(events)
+		result.Subject = subject
+		result.TraceOnly = !deliver
+		result.Stopped = stopped
+		return jsonResult(result)
+	}
+}
+
+// collectTraces reads trace events until every hop forwarded to has
+// reported, the timeout elapsed or ctx is done.
+func collectTraces(ctx context.Context, traces <-chan *nats.Msg, timeout time.Duration) ([]traceEvent, string, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var events []traceEvent
+	for {
+		select {
+		case <-ctx.Done():
+			return events, stopCancelled, nil
+		case <-timer.C:
+			return events, stopTimeout, nil
+		case msg := <-traces:
+			var event traceEvent
+			if err := json.Unmarshal(msg.Data, &event); err != nil {
+				return nil, "", fmt.Errorf("invalid trace event: %w", err)
+			}
+			events = append(events, event)
+			if len(missingHops(events)) == 0 {
+				return events, stopComplete, nil
+			}
+		}
+	}
+}
+
+// missingHops returns the hops that were forwarded to but did not report,
+// with an empty hop standing for the first server.
+func missingHops(events []traceEvent) []string {
+	reported := map[string]bool{}
+	for _, event := range events {
+		reported[event.hop()] = true
+	}
+	var missing []string
+	if !reported[""] {
+		missing = append(missing, "")
+	}
+	for _, event := range events {
+		for _, item := range event.Events {
+			if item.Type == "eg" && item.Hop != "" && !reported[item.Hop] {
+				missing = append(missing, item.Hop)
+			}
+		}
+	}
+	return missing
+}
+
+// traceReport orders the trace events into a path, following each
+// forwarding egress into the trace of the hop it forwarded to.
+func traceReport(events []traceEvent) traceResult {
+	result := traceResult{Servers: len(events), Path: []traceStep{}}
+	byHop := map[string]traceEvent{}
+	for _, event := range events {
+		byHop[event.hop()] = event
+	}
+
+	visited := map[string]bool{}
+	var visit func(hop string)
+	visit = func(hop string) {
+		event, ok := byHop[hop]
+		if !ok || visited[hop] {
+			return
+		}
+		visited[hop] = true
+		for _, item := range event.Events {
+			step := newTraceStep(event, hop, item)
+			result.Path = append(result.Path, step)
+			switch {
+			case step.Error != "":
+				result.Errors = append(result.Errors, step.Server+": "+step.Summary)
+			case step.Step == "egress" && step.Kind == "client":
+				result.Deliveries++
+			case step.Step == "jetstream" && !step.NoInterest:
+				result.Stored++
+			}
+			if step.NextHop != "" {
+				visit(step.NextHop)
+			}
+		}
+	}
+	visit("")
+	// Hops whose forwarding server did not report are appended in order.
+	var orphans []string
+	for hop := range byHop {
+		if !visited[hop] {
+			orphans = append(orphans, hop)
+		}
+	}
+	sort.Strings(orphans)
+	for _, hop := range orphans {
+		visit(hop)
+	}
+
+	for _, hop := range missingHops(events) {
+		if hop == "" {
+			hop = "origin"
+		}
+		result.MissingHops = append(result.MissingHops, hop)
+	}
+	switch {
+	case len(events) == 0:
+		result.Hint = "no server reported a trace; message tracing requires NATS 2.11 or later on the server the tool is connected to"
+	case result.Deliveries == 0 && result.Stored == 0:
+		result.Hint = "no subscriber or stream received the message"
+	}
+	return result
+}
+
+// newTraceStep converts a trace event item of a server into a path step.
+func newTraceStep(event traceEvent, hop string, item traceEventItem) traceStep {
+	step := traceStep{
+		Server:  event.Server.Name,
+		Hop:     hop,
+		Step:    traceSteps[item.Type],
+		Time:    item.Timestamp,
+		Account: item.Account,
+		Error:   item.Error,
+	}
+	if step.Step == "" {
+		step.Step = item.Type
+	}
+	connection := func() string {
+		name := fmt.Sprintf("%s %d", step.Kind, step.CID)
+		if step.Name != "" {
+			name += " (" + step.Name + ")"
+		}
+		return name
+	}
+
+	switch item.Type {
+	case "in":
+		step.Kind, step.CID, step.Name, step.Subject = traceConnectionKind(item.Kind), item.CID, item.Name, item.Subj
+		step.Summary = fmt.Sprintf("received on %s from %s in account %s", step.Subject, connection(), step.Account)
+	case "sm":
+		step.To = item.To
+		step.Summary = "subject mapped to " + step.To
+	case "se":
+		step.To = item.To
+		step.Summary = fmt.Sprintf("stream export to account %s as %s", step.Account, step.To)
+	case "si":
+		step.From, step.To = item.From, item.To
+		step.Summary = fmt.Sprintf("service import into account %s from %s to %s", step.Account, step.From, step.To)
+	case "js":
+		step.Stream, step.Subject, step.NoInterest = item.Stream, item.Subject, item.NoInterest
+		step.Summary = "stored in stream " + step.Stream
+		if step.NoInterest {
+			step.Summary = fmt.Sprintf("not stored in stream %s, which has no interest", step.Stream)
+		}
+	case "eg":
+		step.Kind, step.CID, step.Name = traceConnectionKind(item.Kind), item.CID, item.Name
+		step.Subscription, step.Queue, step.NextHop = item.Sub, item.Queue, item.Hop
+		switch {
+		case step.NextHop != "":
+			step.Summary = fmt.Sprintf("forwarded over %s to hop %s", connection(), step.NextHop)
+		case step.Subscription != "":
+			step.Summary = fmt.Sprintf("delivered to %s on subscription %s", connection(), step.Subscription)
+			if step.Queue != "" {
+				step.Summary += " in queue group " + step.Queue
+			}
+		default:
+			step.Summary = "delivered to " + connection()
+		}
+	default:
+		step.Summary = "unknown trace event " + item.Type
+	}
+	if step.Error != "" {
+		step.Summary += ": " + step.Error
+	}
+	return step
+}
+
+// traceConnectionKind names a connection kind of a trace event.
+func traceConnectionKind(kind int) string {
+	if kind >= 0 && kind < len(traceConnectionKinds) {
+		return traceConnectionKinds[kind]
+	}
+	return fmt.Sprintf("kind %d", kind)
+}
@@ -0,0 +1,161 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+func TestTraceTool_orderedPath(t *testing.T) {
+	url, _ := startTestServer(t)
+	connect := func(name string) *nats.Conn {
+		t.Helper()
+		nc, err := nats.Connect(url, nats.Name(name))
+		if err != nil {
+			t.Fatalf("connect %s: %v", name, err)
+		}
+		t.Cleanup(nc.Close)
+		return nc
+	}
+	// orders.new is mapped to orders.eu.new, which a stream, a queue group
+	// and a wildcard subscriber receive.
+	received := make(chan string, 2)
+	audit, worker := connect("audit"), connect("worker")
+	if _, err := audit.Subscribe("orders.>", func(msg *nats.Msg) { received <- "audit" }); err != nil {
+		t.Fatalf("subscribe audit: %v", err)
+	}
+	if _, err := worker.QueueSubscribe("orders.eu.*", "workers", func(msg *nats.Msg) { received <- "worker" }); err != nil {
+		t.Fatalf("subscribe worker: %v", err)
+	}
+	js, err := jetstream.New(audit)
+	if err != nil {
+		t.Fatalf("jetstream: %v", err)
+	}
+	if _, err := js.CreateStream(context.Background(), jetstream.StreamConfig{Name: "ORDERS", Subjects: []string{"orders.eu.>"}, Storage: jetstream.MemoryStorage}); err != nil {
+		t.Fatalf("create stream: %v", err)
+	}
+	for _, nc := range []*nats.Conn{audit, worker} {
+		if err := nc.Flush(); err != nil {
+			t.Fatalf("flush: %v", err)
+		}
+	}
+
+	trace := testToolHandler(t, url, (*NATSServerTools).RequestTools, "trace")
+	out, err := trace(map[string]any{"subject": "orders.new", "body": "{}", "timeout": "5s"})
+	if err != nil {
+		t.Fatalf("trace: %v", err)
+	}
+	var result traceResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid result %s: %v", out, err)
+	}
+	if !result.TraceOnly || result.Stopped != stopComplete || result.Servers != 1 || result.Deliveries != 2 || result.Stored != 1 || len(result.Errors) != 0 {
+		t.Fatalf("unexpected result: %s", out)
+	}
+	var path []string
+	for _, step := range result.Path {
+		path = append(path, fmt.Sprintf("%s %s %s%s%s", step.Server, step.Step, step.Name, step.Stream, step.To))
+	}
+	// The server reports its deliveries in no particular order.
+	sort.Strings(path[2:])
+	want := []string{
+		"test ingress mcp-nats",
+		"test subject_mapping orders.eu.new",
+		"test egress audit",
+		"test egress worker",
+		"test jetstream ORDERS",
+	}
+	if strings.Join(path, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("path:\n%s\nwant:\n%s", strings.Join(path, "\n"), strings.Join(want, "\n"))
+	}
+	if summary := result.Path[0].Summary; !strings.HasPrefix(summary, "received on orders.new from client ") || !strings.HasSuffix(summary, " (mcp-nats) in account APP") {
+		t.Fatalf("unexpected ingress summary %q", summary)
+	}
+	select {
+	case name := <-received:
+		t.Fatalf("trace only message delivered to %s", name)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	out, err = trace(map[string]any{"subject": "orders.new", "deliver": true, "timeout": "5s"})
+	if err != nil || !strings.Contains(out, `"trace_only": false`) {
+		t.Fatalf("trace with delivery = %s, %v", out, err)
+	}
+	for range 2 {
+		select {
+		case <-received:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected the traced message to be delivered")
+		}
+	}
+}
+
+func TestTraceReport_ordersHops(t *testing.T) {
+	// Two servers report the traced message, the second hop first.
+	var events []traceEvent
+	for _, data := range []string{
+		`{"server":{"name":"n2"},"request":{"header":{"Nats-Trace-Hop":["1"]}},"events":[
+			{"type":"in","kind":1,"cid":4,"name":"n1","acc":"APP","subj":"orders.eu.new"},
+			{"type":"js","stream":"ORDERS","subject":"orders.eu.new"},
+			{"type":"eg","kind":0,"cid":9,"name":"worker","sub":"orders.eu.*","queue":"workers"}]}`,
+		`{"server":{"name":"n1"},"request":{"header":{}},"hops":1,"events":[
+			{"type":"in","kind":0,"cid":12,"name":"mcp-nats","acc":"APP","subj":"orders.new"},
+			{"type":"sm","to":"orders.eu.new"},
+			{"type":"eg","kind":0,"cid":13,"name":"audit","sub":"orders.>","error":"Permissions Violation"},
+			{"type":"eg","kind":1,"cid":4,"name":"n2","hop":"1"}]}`,
+	} {
+		var event traceEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			t.Fatalf("invalid event: %v", err)
+		}
+		events = append(events, event)
+	}
+	result := traceReport(events)
+	if result.Servers != 2 || result.Deliveries != 1 || result.Stored != 1 || len(result.Errors) != 1 || len(result.MissingHops) != 0 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	var path []string
+	for _, step := range result.Path {
+		path = append(path, step.Server+" "+step.Summary)
+	}
+	want := []string{
+		"n1 received on orders.new from client 12 (mcp-nats) in account APP",
+		"n1 subject mapped to orders.eu.new",
+		"n1 delivered to client 13 (audit) on subscription orders.>: Permissions Violation",
+		"n1 forwarded over route 4 (n2) to hop 1",
+		"n2 received on orders.eu.new from route 4 (n1) in account APP",
+		"n2 stored in stream ORDERS",
+		"n2 delivered to client 9 (worker) on subscription orders.eu.* in queue group workers",
+	}
+	if strings.Join(path, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("path:\n%s\nwant:\n%s", strings.Join(path, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestTraceReport_missingHops(t *testing.T) {
+	var origin traceEvent
+	if err := json.Unmarshal([]byte(`{"server":{"name":"n1"},"events":[{"type":"in","acc":"APP","subj":"a"},{"type":"eg","kind":2,"name":"west","hop":"1"}]}`), &origin); err != nil {
+		t.Fatalf("invalid event: %v", err)
+	}
+	result := traceReport([]traceEvent{origin})
+	if len(result.MissingHops) != 1 || result.MissingHops[0] != "1" || result.Path[1].Kind != "gateway" ||
+		result.Hint != "no subscriber or stream received the message" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if result := traceReport(nil); !strings.Contains(result.Hint, "2.11") || len(result.MissingHops) != 1 {
+		t.Fatalf("expected a hint about the server version: %+v", result)
+	}
+
+	for version, want := range map[string]bool{"2.11.0": true, "2.12.1-RC.2": true, "2.10.22": false, "3.0.0": true, "": false} {
+		if supportsMessageTracing(version) != want {
+			t.Fatalf("supportsMessageTracing(%q) = %v", version, !want)
+		}
+	}
+}
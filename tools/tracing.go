@@ -0,0 +1,48 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	mcpnats "github.com/sinadarbouy/mcp-nats"
+	"github.com/sinadarbouy/mcp-nats/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracingMiddleware starts a span for every call of the wrapped tool. NATS
+// commands run by the handler become child spans.
+func TracingMiddleware() Middleware {
+	return func(tool mcp.Tool, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			attrs := []attribute.KeyValue{
+				attribute.String("mcp.tool.name", tool.Name),
+				attribute.String("nats.account", requestAccountName(ctx, request)),
+			}
+			if session := server.ClientSessionFromContext(ctx); session != nil {
+				attrs = append(attrs, attribute.String("mcp.session.id", session.SessionID()))
+			}
+			if identity := mcpnats.GetInboundIdentityFromContext(ctx); identity != "" {
+				attrs = append(attrs, attribute.String("mcp.client.identity", identity))
+			}
+
+			ctx, span := tracing.Tracer().Start(ctx, "tools/call "+tool.Name,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(attrs...),
+			)
+			defer span.End()
+
+			result, err := next(ctx, request)
+			switch {
+			case err != nil:
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			case result != nil && result.IsError:
+				span.SetStatus(codes.Error, "tool returned an error result")
+			}
+			return result, err
+		}
+	}
+}
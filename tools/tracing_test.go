@@ -0,0 +1,43 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sinadarbouy/mcp-nats/internal/tracing"
+	"github.com/sinadarbouy/mcp-nats/internal/tracing/tracingtest"
+)
+
+func TestTracingMiddleware_spanAndPropagation(t *testing.T) {
+	exporter, restore := tracingtest.SetupInMemory()
+	defer restore()
+
+	var headers []string
+	handler := TracingMiddleware()(mcp.Tool{Name: "publish"}, func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		headers = tracing.HeaderArgs(ctx)
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	request := mcp.CallToolRequest{Params: mcp.CallToolParams{
+		Name:      "publish",
+		Arguments: map[string]any{"account_name": "A", "subject": "orders.new"},
+	}}
+	if _, err := handler(context.Background(), request); err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("recorded %d spans, want 1", len(spans))
+	}
+	if got := spans[0].Name; got != "tools/call publish" {
+		t.Fatalf("span name = %q, want %q", got, "tools/call publish")
+	}
+
+	traceID := spans[0].SpanContext.TraceID().String()
+	if len(headers) == 0 || !strings.HasPrefix(headers[0], "traceparent:") || !strings.Contains(headers[0], traceID) {
+		t.Fatalf("headers = %v, want traceparent carrying trace %s", headers, traceID)
+	}
+}